@@ -0,0 +1,18 @@
+#!/usr/bin/env bash
+# Calls a backend worker route signed with WORKER_SECRET.
+# Usage: call-worker.sh <path>, e.g. call-worker.sh /api/worker/update-prices
+set -euo pipefail
+
+: "${WORKER_SECRET:?WORKER_SECRET is not set}"
+: "${WORKER_BASE_URL:=https://tpt-v3.vercel.app}"
+
+path="$1"
+timestamp=$(date +%s)
+nonce=$(openssl rand -hex 16)
+signature=$(printf '%s' "$timestamp.$nonce.GET.$path" | openssl dgst -sha256 -hmac "$WORKER_SECRET" | awk '{print $NF}')
+
+curl --fail-with-body --silent --show-error "$WORKER_BASE_URL$path" \
+  -H "X-Worker-Timestamp: $timestamp" \
+  -H "X-Worker-Nonce: $nonce" \
+  -H "X-Worker-Signature: $signature"
+echo
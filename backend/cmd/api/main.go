@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"os"
@@ -8,11 +9,13 @@ import (
 
 	"trade-tracker/core/config"
 	"trade-tracker/core/delivery/http"
+	"trade-tracker/core/integrations/mailer"
 	"trade-tracker/core/integrations/providers"
+	"trade-tracker/core/integrations/storage"
 	"trade-tracker/core/repositories"
 	"trade-tracker/core/services"
 	"trade-tracker/core/worker"
-	"trade-tracker/pkg/utils/market"
+	"trade-tracker/pkg/utils/calendar"
 
 	"github.com/joho/godotenv"
 )
@@ -23,17 +26,10 @@ func main() {
 		log.Println("No .env file found, relying on environment variables")
 	}
 
-	holidays := market.LoadHolidays("holidays.json")
-	now := time.Now()
+	idx := calendar.Load("holidays.json").Exchange(calendar.IDX)
 
-	worker.UpdateStock(holidays, now, true)
-	if os.Getenv("PRODUCTION_ENVIRONMENT") != "vercel" {
-		go func() {
-			ticker := time.NewTicker(5 * time.Minute)
-			for range ticker.C {
-				worker.UpdateStock(holidays, now, false)
-			}
-		}()
+	if _, err := worker.UpdateStock(context.Background(), idx, time.Now(), true); err != nil {
+		log.Printf("Initial stock update failed: %v", err)
 	}
 
 	connStr := os.Getenv("DB_CONNECTION")
@@ -51,23 +47,86 @@ func main() {
 	noteRepo := repositories.NewNoteRepo(db)
 	balRepo := repositories.NewBalanceRepo(db)
 	aRepo := repositories.NewAssetRepo(db)
+	jobRepo := repositories.NewJobRepo(db)
+	sessionRepo := repositories.NewSessionRepo(db)
+	recoveryCodeRepo := repositories.NewRecoveryCodeRepo(db)
+	apiKeyRepo := repositories.NewAPIKeyRepo(db)
+	shareRepo := repositories.NewShareRepo(db)
+	loginRepo := repositories.NewLoginEventRepo(db)
+	auditRepo := repositories.NewAuditRepo(db)
+	idempotencyRepo := repositories.NewIdempotencyRepo(db)
+	scheduleRepo := repositories.NewReportScheduleRepo(db)
+	journalRepo := repositories.NewJournalRepo(db)
+
+	// Jobs are registered everywhere so the worker routes can trigger them and record their runs,
+	// but only started where the process stays alive between requests (i.e. not on Vercel).
+	scheduler := worker.NewScheduler(jobRepo)
+
+	updateStockJob, err := worker.NewUpdateStockJob(idx)
+	if err != nil {
+		log.Fatalf("Error creating job: %v", err)
+	}
+	if err := scheduler.Register(updateStockJob); err != nil {
+		log.Fatalf("Error registering job: %v", err)
+	}
 
-	priceProvider := providers.NewPriceProvider()
+	purgeIdempotencyJob, err := worker.NewPurgeIdempotencyJob(idempotencyRepo)
+	if err != nil {
+		log.Fatalf("Error creating job: %v", err)
+	}
+	if err := scheduler.Register(purgeIdempotencyJob); err != nil {
+		log.Fatalf("Error registering job: %v", err)
+	}
+
+	yahooProvider := providers.NewCachedPriceProvider(providers.NewPriceProvider(), idx)
+	priceProvider := providers.NewStorePriceProvider(yahooProvider, idx)
 	assetProvider := providers.NewAssetProvider()
+	mail := mailer.NewMailer()
+	store := storage.NewStorage()
 
-	nService := services.NewNoteService(noteRepo)
-	tService := services.NewTransactionService(tranRepo, balRepo)
-	bService := services.NewBalanceService(balRepo, tService)
-	pService := services.NewPositionService(posRepo, userRepo, priceProvider, tService, bService)
-	uService := services.NewUserService(userRepo, pService, tService, bService)
-	rService := services.NewReportService(pService, uService, tService, priceProvider)
+	auService := services.NewAuditService(auditRepo)
+	nService := services.NewNoteService(noteRepo, auService, store)
+	jrService := services.NewJournalService(journalRepo, tranRepo, posRepo, auService)
+	shService := services.NewShareService(shareRepo, userRepo, posRepo, tranRepo, balRepo)
+	tService := services.NewTransactionService(tranRepo, balRepo, shService, auService)
+	bService := services.NewBalanceService(balRepo, tService, shService, auService)
+	pService := services.NewPositionService(posRepo, userRepo, priceProvider, tService, bService, shService, auService)
+	uService := services.NewUserService(userRepo, loginRepo, pService, tService, bService, nService, mail)
+	rService := services.NewReportService(pService, uService, tService, bService, idx)
+	rsService := services.NewReportScheduleService(scheduleRepo, userRepo, rService, mail)
 	aService := services.NewAssetService(aRepo, assetProvider, priceProvider)
+	jService := services.NewJobService(jobRepo, scheduler)
+	sService := services.NewSessionService(sessionRepo)
+	tfService := services.NewTwoFactorService(userRepo, recoveryCodeRepo)
+	kService := services.NewAPIKeyService(apiKeyRepo)
+	adService := services.NewAdminService(userRepo, sService, jService)
+	idService := services.NewIdempotencyService(idempotencyRepo)
+
+	deliverReportsJob, err := worker.NewDeliverReportsJob(rsService)
+	if err != nil {
+		log.Fatalf("Error creating job: %v", err)
+	}
+	if err := scheduler.Register(deliverReportsJob); err != nil {
+		log.Fatalf("Error registering job: %v", err)
+	}
+
+	purgeTrashJob, err := worker.NewPurgeTrashJob(services.TrashRetention(), nService, tService)
+	if err != nil {
+		log.Fatalf("Error creating job: %v", err)
+	}
+	if err := scheduler.Register(purgeTrashJob); err != nil {
+		log.Fatalf("Error registering job: %v", err)
+	}
+
+	if os.Getenv("PRODUCTION_ENVIRONMENT") != "vercel" {
+		scheduler.Start()
+	}
 
 	port := os.Getenv("PORT")
 	if port == "" {
 		port = "8080"
 	}
 
-	app := http.InitRoutes(uService, pService, tService, nService, bService, rService, aService)
+	app := http.InitRoutes(uService, pService, tService, nService, sService, bService, rService, aService, jService, tfService, kService, adService, shService, auService, idService, rsService, jrService, idx)
 	log.Fatal(app.Listen(fmt.Sprintf(":%s", port)))
 }
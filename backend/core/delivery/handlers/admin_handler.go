@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"strconv"
+	"trade-tracker/core/domain"
+	"trade-tracker/core/services"
+	"trade-tracker/pkg/utils/format"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v3"
+)
+
+type AdminHandler struct {
+	service  services.AdminService
+	validate *validator.Validate
+}
+
+func NewAdminHandler(service services.AdminService) *AdminHandler {
+	return &AdminHandler{service: service, validate: validator.New()}
+}
+
+func (h *AdminHandler) HandleListUsers(c fiber.Ctx) error {
+	page, _ := strconv.Atoi(c.Query("page"))
+	limit, _ := strconv.Atoi(c.Query("limit"))
+
+	res, err := h.service.ListUsers(c.Query("search"), page, limit)
+	if err != nil {
+		return format.ErrorResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"data": res})
+}
+
+func (h *AdminHandler) HandleLockUser(c fiber.Ctx) error {
+	adminID, ok := c.Locals("user_id").(uint64)
+	if !ok {
+		return c.Status(401).JSON(fiber.Map{"message": "Unauthorized."})
+	}
+
+	userID, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"message": "Invalid user ID."})
+	}
+
+	var req domain.LockUserReq
+	if len(c.Body()) > 0 {
+		if err := c.Bind().Body(&req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"message": "Failed to parse body."})
+		}
+	}
+	if err := h.validate.Struct(req); err != nil {
+		if validationErrors, ok := err.(validator.ValidationErrors); ok {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"message": format.FormatError(validationErrors[0])})
+		}
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"message": "Invalid request."})
+	}
+
+	if err := h.service.LockUser(adminID, userID, req.Reason); err != nil {
+		return format.ErrorResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": "User locked."})
+}
+
+func (h *AdminHandler) HandleUnlockUser(c fiber.Ctx) error {
+	userID, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"message": "Invalid user ID."})
+	}
+
+	if err := h.service.UnlockUser(userID); err != nil {
+		return format.ErrorResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": "User unlocked."})
+}
+
+func (h *AdminHandler) HandleSetRole(c fiber.Ctx) error {
+	adminID, ok := c.Locals("user_id").(uint64)
+	if !ok {
+		return c.Status(401).JSON(fiber.Map{"message": "Unauthorized."})
+	}
+
+	userID, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"message": "Invalid user ID."})
+	}
+
+	var req domain.SetRoleReq
+	if err := c.Bind().Body(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"message": "Failed to parse body."})
+	}
+	if err := h.validate.Struct(req); err != nil {
+		if validationErrors, ok := err.(validator.ValidationErrors); ok {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"message": format.FormatError(validationErrors[0])})
+		}
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"message": "Invalid request."})
+	}
+
+	if err := h.service.SetRole(adminID, userID, req.Role); err != nil {
+		return format.ErrorResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": "Role updated."})
+}
+
+func (h *AdminHandler) HandleHealth(c fiber.Ctx) error {
+	res := h.service.GetHealth()
+
+	status := fiber.StatusOK
+	if res.Status != "ok" {
+		status = fiber.StatusServiceUnavailable
+	}
+	return c.Status(status).JSON(fiber.Map{"data": res})
+}
@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"strconv"
+	"trade-tracker/core/domain"
+	"trade-tracker/core/services"
+	"trade-tracker/pkg/utils/format"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v3"
+)
+
+type APIKeyHandler struct {
+	service  services.APIKeyService
+	validate *validator.Validate
+}
+
+func NewAPIKeyHandler(service services.APIKeyService) *APIKeyHandler {
+	return &APIKeyHandler{service: service, validate: validator.New()}
+}
+
+func (h *APIKeyHandler) HandleCreateKey(c fiber.Ctx) error {
+	uid, ok := c.Locals("user_id").(uint64)
+	if !ok {
+		return c.Status(401).JSON(fiber.Map{"message": "Unauthorized."})
+	}
+
+	var req domain.CreateAPIKeyReq
+	if err := c.Bind().Body(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"message": "Failed to parse body."})
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		if validationErrors, ok := err.(validator.ValidationErrors); ok {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"message": format.FormatError(validationErrors[0])})
+		}
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"message": "Invalid request."})
+	}
+
+	res, err := h.service.CreateKey(uid, req)
+	if err != nil {
+		return format.ErrorResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"message": "API key created. Copy it now, it will not be shown again.",
+		"data":    res,
+	})
+}
+
+func (h *APIKeyHandler) HandleGetKeys(c fiber.Ctx) error {
+	uid, ok := c.Locals("user_id").(uint64)
+	if !ok {
+		return c.Status(401).JSON(fiber.Map{"message": "Unauthorized."})
+	}
+
+	keys, err := h.service.GetKeys(uid)
+	if err != nil {
+		return format.ErrorResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"data": keys})
+}
+
+func (h *APIKeyHandler) HandleRevokeKey(c fiber.Ctx) error {
+	uid, ok := c.Locals("user_id").(uint64)
+	if !ok {
+		return c.Status(401).JSON(fiber.Map{"message": "Unauthorized."})
+	}
+
+	keyID, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"message": "Invalid key ID."})
+	}
+
+	if err := h.service.RevokeKey(uint(keyID), uid); err != nil {
+		return format.ErrorResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": "API key revoked."})
+}
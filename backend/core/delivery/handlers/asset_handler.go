@@ -3,10 +3,7 @@ package handlers
 import (
 	"fmt"
 	"strings"
-	"time"
 	"trade-tracker/core/services"
-	"trade-tracker/core/worker"
-	"trade-tracker/pkg/utils/market"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v3"
@@ -15,15 +12,12 @@ import (
 type AssetHandler struct {
 	service  services.AssetService
 	validate *validator.Validate
-	holidays market.CheckedList
 }
 
 func NewAssetHandler(service services.AssetService) *AssetHandler {
-	holidays := market.LoadHolidays("./holidays.json")
 	return &AssetHandler{
 		service:  service,
 		validate: validator.New(),
-		holidays: holidays,
 	}
 }
 
@@ -72,25 +66,3 @@ func (h *AssetHandler) HandleGetAssetChart(c fiber.Ctx) error {
 
 	return c.Status(200).JSON(fiber.Map{"data": chartData})
 }
-
-func (h *AssetHandler) HandleUpdateStock(c fiber.Ctx) error {
-	var now time.Time
-	loc, _ := time.LoadLocation("Asia/Jakarta")
-
-	if time_ := c.Query("time"); time_ != "" {
-		parsedTime, err := time.Parse(time.RFC3339, time_)
-		if err == nil {
-			now = parsedTime.In(loc)
-		} else {
-			return c.Status(fiber.StatusBadRequest).SendString("Error: mismatch time format (use RFC3339 instead)")
-		}
-	} else {
-		now = time.Now().In(loc)
-	}
-
-	if !worker.UpdateStock(h.holidays, now, false) {
-		return c.Status(fiber.StatusOK).SendString(fmt.Sprintf("[SKIP WORKER] Market closed at %s. (Causes no data to be changed.)", now.Format("2006-01-02 15:04:05")))
-	}
-
-	return c.Status(fiber.StatusOK).SendString(fmt.Sprintf("[SUCCESS WORKER] Stock data updated successfully at %s", now.Format("15:04:05")))
-}
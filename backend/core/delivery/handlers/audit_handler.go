@@ -0,0 +1,42 @@
+package handlers
+
+import (
+	"trade-tracker/core/domain"
+	"trade-tracker/core/services"
+	"trade-tracker/pkg/utils/format"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/gofiber/fiber/v3/middleware/requestid"
+)
+
+// actorOf attributes a mutation to the authenticated user and the current request.
+func actorOf(c fiber.Ctx, uid uint64) domain.Actor {
+	return domain.Actor{UserID: uid, RequestID: requestid.FromContext(c)}
+}
+
+type AuditHandler struct {
+	service services.AuditService
+}
+
+func NewAuditHandler(service services.AuditService) *AuditHandler {
+	return &AuditHandler{service: service}
+}
+
+func (h *AuditHandler) HandleGetLogs(c fiber.Ctx) error {
+	uid, ok := c.Locals("user_id").(uint64)
+	if !ok {
+		return c.Status(401).JSON(fiber.Map{"message": "Unauthorized."})
+	}
+
+	var filter domain.AuditLogFilter
+	if err := c.Bind().Query(&filter); err != nil {
+		return c.Status(400).JSON(fiber.Map{"message": "Invalid query."})
+	}
+
+	res, err := h.service.GetLogs(uid, filter)
+	if err != nil {
+		return format.ErrorResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"data": res})
+}
@@ -40,13 +40,39 @@ func (h *BalanceHandler) HandleUpdateBalance(c fiber.Ctx) error {
 		return c.Status(400).JSON(fiber.Map{"message": "Invalid request."})
 	}
 
-	if err := h.service.AdjustBalance(uid, req); err != nil {
+	if err := h.service.AdjustBalance(actorOf(c, uid), req); err != nil {
 		return format.ErrorResponse(c, err)
 	}
 
 	return c.Status(200).JSON(fiber.Map{"message": "Balance updated."})
 }
 
+func (h *BalanceHandler) HandleRecordDividend(c fiber.Ctx) error {
+	uid, ok := c.Locals("user_id").(uint64)
+	if !ok {
+		return c.Status(401).JSON(fiber.Map{"message": "Unauthorized."})
+	}
+
+	var req domain.DividendReq
+	if err := c.Bind().Body(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"message": "Failed to parse body."})
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		if validationErrors, ok := err.(validator.ValidationErrors); ok {
+			errFirst := validationErrors[0]
+			return c.Status(400).JSON(fiber.Map{"message": format.FormatError(errFirst)})
+		}
+		return c.Status(400).JSON(fiber.Map{"message": "Invalid request."})
+	}
+
+	if err := h.service.RecordDividend(actorOf(c, uid), req); err != nil {
+		return format.ErrorResponse(c, err)
+	}
+
+	return c.Status(200).JSON(fiber.Map{"message": "Dividend recorded."})
+}
+
 func (h *BalanceHandler) HandleGetAccountsByType(c fiber.Ctx) error {
 	uid, ok := c.Locals("user_id").(uint64)
 	if !ok {
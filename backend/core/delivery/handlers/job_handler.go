@@ -0,0 +1,40 @@
+package handlers
+
+import (
+	"strconv"
+	"trade-tracker/core/services"
+	"trade-tracker/pkg/utils/format"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+type JobHandler struct {
+	service services.JobService
+}
+
+func NewJobHandler(service services.JobService) *JobHandler {
+	return &JobHandler{service: service}
+}
+
+func (h *JobHandler) HandleGetJobStatus(c fiber.Ctx) error {
+	return c.Status(200).JSON(fiber.Map{"jobs": h.service.GetJobStatus()})
+}
+
+func (h *JobHandler) HandleGetJobRuns(c fiber.Ctx) error {
+	limit, _ := strconv.Atoi(c.Query("limit"))
+
+	runs, err := h.service.GetJobRuns(c.Query("job"), limit)
+	if err != nil {
+		return format.ErrorResponse(c, err)
+	}
+
+	return c.Status(200).JSON(fiber.Map{"runs": runs})
+}
+
+func (h *JobHandler) HandleRunJob(c fiber.Ctx) error {
+	if err := h.service.RunJob(c.Params("name")); err != nil {
+		return format.ErrorResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusAccepted).JSON(fiber.Map{"message": "Job triggered."})
+}
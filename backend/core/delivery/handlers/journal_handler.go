@@ -0,0 +1,175 @@
+package handlers
+
+import (
+	"strconv"
+	"trade-tracker/core/domain"
+	"trade-tracker/core/services"
+	"trade-tracker/pkg/utils/format"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v3"
+	"github.com/microcosm-cc/bluemonday"
+)
+
+type JournalHandler struct {
+	service  services.JournalService
+	validate *validator.Validate
+}
+
+func NewJournalHandler(service services.JournalService) *JournalHandler {
+	return &JournalHandler{
+		service:  service,
+		validate: validator.New(),
+	}
+}
+
+func (h *JournalHandler) bindRequest(c fiber.Ctx) (domain.JournalRequest, error) {
+	var req domain.JournalRequest
+	if err := c.Bind().Body(&req); err != nil {
+		return req, c.Status(400).JSON(fiber.Map{"message": "Failed to parse body."})
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		if validationErrors, ok := err.(validator.ValidationErrors); ok {
+			return req, c.Status(400).JSON(fiber.Map{"message": format.FormatError(validationErrors[0])})
+		}
+		return req, c.Status(400).JSON(fiber.Map{"message": "Invalid request."})
+	}
+
+	p := bluemonday.StrictPolicy()
+	req.Setup = p.Sanitize(req.Setup)
+	req.Thesis = p.Sanitize(req.Thesis)
+	req.Emotion = p.Sanitize(req.Emotion)
+	req.Mistakes = p.Sanitize(req.Mistakes)
+	for i, tag := range req.Tags {
+		req.Tags[i] = p.Sanitize(tag)
+	}
+	return req, nil
+}
+
+func (h *JournalHandler) HandleAddEntry(c fiber.Ctx) error {
+	uid, ok := c.Locals("user_id").(uint64)
+	if !ok {
+		return c.Status(401).JSON(fiber.Map{"message": "Unauthorized."})
+	}
+
+	req, err := h.bindRequest(c)
+	if err != nil {
+		return err
+	}
+
+	entry, err := h.service.AddEntry(actorOf(c, uid), req)
+	if err != nil {
+		return format.ErrorResponse(c, err)
+	}
+
+	return c.Status(201).JSON(fiber.Map{"message": "Journal entry added.", "data": entry})
+}
+
+func (h *JournalHandler) HandleUpdateEntry(c fiber.Ctx) error {
+	uid, ok := c.Locals("user_id").(uint64)
+	if !ok {
+		return c.Status(401).JSON(fiber.Map{"message": "Unauthorized."})
+	}
+
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"message": "Invalid journal entry ID."})
+	}
+
+	req, err := h.bindRequest(c)
+	if err != nil {
+		return err
+	}
+
+	entry, err := h.service.UpdateEntry(actorOf(c, uid), uint(id), req)
+	if err != nil {
+		return format.ErrorResponse(c, err)
+	}
+
+	return c.Status(200).JSON(fiber.Map{"message": "Journal entry updated.", "data": entry})
+}
+
+func (h *JournalHandler) HandleRemoveEntry(c fiber.Ctx) error {
+	uid, ok := c.Locals("user_id").(uint64)
+	if !ok {
+		return c.Status(401).JSON(fiber.Map{"message": "Unauthorized."})
+	}
+
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"message": "Invalid journal entry ID."})
+	}
+
+	if err := h.service.RemoveEntry(actorOf(c, uid), uint(id)); err != nil {
+		return format.ErrorResponse(c, err)
+	}
+
+	return c.Status(200).JSON(fiber.Map{"message": "Journal entry removed."})
+}
+
+func (h *JournalHandler) HandleGetEntries(c fiber.Ctx) error {
+	uid, ok := c.Locals("user_id").(uint64)
+	if !ok {
+		return c.Status(401).JSON(fiber.Map{"message": "Unauthorized."})
+	}
+
+	entries, err := h.service.GetEntries(uid)
+	if err != nil {
+		return format.ErrorResponse(c, err)
+	}
+
+	return c.Status(200).JSON(fiber.Map{"data": entries})
+}
+
+func (h *JournalHandler) HandleGetTradeJournal(c fiber.Ctx) error {
+	uid, ok := c.Locals("user_id").(uint64)
+	if !ok {
+		return c.Status(401).JSON(fiber.Map{"message": "Unauthorized."})
+	}
+
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"message": "Invalid transaction ID."})
+	}
+
+	data, err := h.service.GetTradeJournal(uid, uint(id))
+	if err != nil {
+		return format.ErrorResponse(c, err)
+	}
+
+	return c.Status(200).JSON(fiber.Map{"data": data})
+}
+
+func (h *JournalHandler) HandleGetPositionJournal(c fiber.Ctx) error {
+	uid, ok := c.Locals("user_id").(uint64)
+	if !ok {
+		return c.Status(401).JSON(fiber.Map{"message": "Unauthorized."})
+	}
+
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"message": "Invalid position ID."})
+	}
+
+	data, err := h.service.GetPositionJournal(uid, uint(id))
+	if err != nil {
+		return format.ErrorResponse(c, err)
+	}
+
+	return c.Status(200).JSON(fiber.Map{"data": data})
+}
+
+func (h *JournalHandler) HandleAnalyze(c fiber.Ctx) error {
+	uid, ok := c.Locals("user_id").(uint64)
+	if !ok {
+		return c.Status(401).JSON(fiber.Map{"message": "Unauthorized."})
+	}
+
+	stats, err := h.service.AnalyzeJournal(uid, c.Query("by"))
+	if err != nil {
+		return format.ErrorResponse(c, err)
+	}
+
+	return c.Status(200).JSON(fiber.Map{"data": stats})
+}
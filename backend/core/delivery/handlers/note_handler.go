@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"io"
 	"strconv"
 	"trade-tracker/core/domain"
 	"trade-tracker/core/services"
@@ -47,18 +48,23 @@ func (h *NoteHandler) HandleAddNote(c fiber.Ctx) error {
 	req.Description = p.Sanitize(req.Description)
 	req.Title = p.Sanitize(req.Title)
 	req.Category = p.Sanitize(req.Category)
+	for i, tag := range req.Tags {
+		req.Tags[i] = p.Sanitize(tag)
+	}
 
-	if err := h.service.AddNote(&domain.Note{
+	note := &domain.Note{
 		UserID:      uid,
 		Title:       req.Title,
 		Description: req.Description,
 		Category:    req.Category,
+		Tags:        req.Tags,
 		ImageURL:    req.ImageURL,
-	}); err != nil {
+	}
+	if err := h.service.AddNote(actorOf(c, uid), note); err != nil {
 		return format.ErrorResponse(c, err)
 	}
 
-	return c.Status(200).JSON(fiber.Map{"message": "Note added."})
+	return c.Status(200).JSON(fiber.Map{"message": "Note added.", "id": note.ID})
 }
 
 func (h *NoteHandler) HandleUpdateNote(c fiber.Ctx) error {
@@ -90,17 +96,21 @@ func (h *NoteHandler) HandleUpdateNote(c fiber.Ctx) error {
 	req.Description = p.Sanitize(req.Description)
 	req.Title = p.Sanitize(req.Title)
 	req.Category = p.Sanitize(req.Category)
+	for i, tag := range req.Tags {
+		req.Tags[i] = p.Sanitize(tag)
+	}
 
 	note := &domain.Note{
 		UserID:      uid,
 		Title:       req.Title,
 		Description: req.Description,
 		Category:    req.Category,
+		Tags:        req.Tags,
 		ImageURL:    req.ImageURL,
 	}
 	note.ID = uint(noteId)
 
-	if err := h.service.UpdateNote(note); err != nil {
+	if err := h.service.UpdateNote(actorOf(c, uid), note); err != nil {
 		return format.ErrorResponse(c, err)
 	}
 
@@ -113,12 +123,17 @@ func (h *NoteHandler) HandleGetNotes(c fiber.Ctx) error {
 		return c.Status(401).JSON(fiber.Map{"message": "Unauthorized."})
 	}
 
-	data, err := h.service.GetNotes(uid)
+	var filter domain.NoteFilter
+	if err := c.Bind().Query(&filter); err != nil {
+		return c.Status(400).JSON(fiber.Map{"message": "Invalid query."})
+	}
+
+	res, err := h.service.SearchNotes(uid, filter)
 	if err != nil {
 		return format.ErrorResponse(c, err)
 	}
 
-	return c.Status(200).JSON(fiber.Map{"notes": data})
+	return c.Status(200).JSON(res)
 }
 
 func (h *NoteHandler) HandleRemoveNote(c fiber.Ctx) error {
@@ -132,9 +147,138 @@ func (h *NoteHandler) HandleRemoveNote(c fiber.Ctx) error {
 		return c.Status(401).JSON(fiber.Map{"message": err.Error()})
 	}
 
-	if err := h.service.RemoveNote(uint(noteId), uid); err != nil {
+	if err := h.service.RemoveNote(actorOf(c, uid), uint(noteId)); err != nil {
 		return format.ErrorResponse(c, err)
 	}
 
 	return c.Status(200).JSON(fiber.Map{"message": "success"})
 }
+
+func (h *NoteHandler) HandleUploadImage(c fiber.Ctx) error {
+	uid, ok := c.Locals("user_id").(uint64)
+	if !ok {
+		return c.Status(401).JSON(fiber.Map{"message": "Unauthorized."})
+	}
+
+	noteId, err := strconv.Atoi(c.Params("nId"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"message": "Invalid note ID."})
+	}
+
+	header, err := c.FormFile("image")
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"message": "Attach the image as the 'image' form field."})
+	}
+	if header.Size > services.MaxNoteImageSize {
+		return format.ErrorResponse(c, domain.ErrImageTooLarge)
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		return format.ErrorResponse(c, err)
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, services.MaxNoteImageSize+1))
+	if err != nil {
+		return format.ErrorResponse(c, err)
+	}
+
+	image, err := h.service.AddImage(actorOf(c, uid), uint(noteId), data)
+	if err != nil {
+		return format.ErrorResponse(c, err)
+	}
+
+	return c.Status(201).JSON(fiber.Map{"message": "Image uploaded.", "data": image})
+}
+
+func (h *NoteHandler) HandleRemoveImage(c fiber.Ctx) error {
+	uid, ok := c.Locals("user_id").(uint64)
+	if !ok {
+		return c.Status(401).JSON(fiber.Map{"message": "Unauthorized."})
+	}
+
+	noteId, err := strconv.Atoi(c.Params("nId"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"message": "Invalid note ID."})
+	}
+	imageId, err := strconv.Atoi(c.Params("imageId"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"message": "Invalid image ID."})
+	}
+
+	if err := h.service.RemoveImage(actorOf(c, uid), uint(noteId), uint(imageId)); err != nil {
+		return format.ErrorResponse(c, err)
+	}
+
+	return c.Status(200).JSON(fiber.Map{"message": "Image removed."})
+}
+
+// HandleGetImage serves uploaded images without authentication so they work in <img> tags.
+// Keys are random, and images never change, so they can be cached indefinitely.
+func (h *NoteHandler) HandleGetImage(c fiber.Ctx) error {
+	data, contentType, err := h.service.GetImageObject(c.Params("key"))
+	if err != nil {
+		return format.ErrorResponse(c, err)
+	}
+
+	c.Set(fiber.HeaderContentType, contentType)
+	c.Set(fiber.HeaderCacheControl, "public, max-age=31536000, immutable")
+	c.Set("Cross-Origin-Resource-Policy", "cross-origin")
+	return c.Status(200).Send(data)
+}
+
+func (h *NoteHandler) HandleGetTrash(c fiber.Ctx) error {
+	uid, ok := c.Locals("user_id").(uint64)
+	if !ok {
+		return c.Status(401).JSON(fiber.Map{"message": "Unauthorized."})
+	}
+
+	var filter domain.NoteFilter
+	if err := c.Bind().Query(&filter); err != nil {
+		return c.Status(400).JSON(fiber.Map{"message": "Invalid query."})
+	}
+
+	res, err := h.service.GetTrash(uid, filter.Page, filter.Limit)
+	if err != nil {
+		return format.ErrorResponse(c, err)
+	}
+
+	return c.Status(200).JSON(res)
+}
+
+func (h *NoteHandler) HandleRestoreNote(c fiber.Ctx) error {
+	uid, ok := c.Locals("user_id").(uint64)
+	if !ok {
+		return c.Status(401).JSON(fiber.Map{"message": "Unauthorized."})
+	}
+
+	noteId, err := strconv.Atoi(c.Params("nId"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"message": "Invalid note ID."})
+	}
+
+	if err := h.service.RestoreNote(actorOf(c, uid), uint(noteId)); err != nil {
+		return format.ErrorResponse(c, err)
+	}
+
+	return c.Status(200).JSON(fiber.Map{"message": "Note restored."})
+}
+
+func (h *NoteHandler) HandlePurgeNote(c fiber.Ctx) error {
+	uid, ok := c.Locals("user_id").(uint64)
+	if !ok {
+		return c.Status(401).JSON(fiber.Map{"message": "Unauthorized."})
+	}
+
+	noteId, err := strconv.Atoi(c.Params("nId"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"message": "Invalid note ID."})
+	}
+
+	if err := h.service.PurgeNote(actorOf(c, uid), uint(noteId)); err != nil {
+		return format.ErrorResponse(c, err)
+	}
+
+	return c.Status(200).JSON(fiber.Map{"message": "Note permanently deleted."})
+}
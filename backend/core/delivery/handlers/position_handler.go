@@ -57,8 +57,13 @@ func (h *PositionHandler) HandleAddPosition(c fiber.Ctx) error {
 		return c.Status(400).JSON(fiber.Map{"message": "Invalid request."})
 	}
 
-	if err := h.service.AddPosition(directionType, &domain.Position{
-		OwnerID:       uid,
+	ownerID := uid
+	if req.OwnerID != 0 {
+		ownerID = req.OwnerID
+	}
+
+	if err := h.service.AddPosition(actorOf(c, uid), directionType, &domain.Position{
+		OwnerID:       ownerID,
 		TotalQty:      req.TotalQty,
 		Ticker:        req.Ticker,
 		InvestedTotal: req.InvestedTotal,
@@ -105,7 +110,7 @@ func (h *PositionHandler) HandleMigratePositions(c fiber.Ctx) error {
 		return c.Status(400).JSON(fiber.Map{"message": "Provider and account_no are required."})
 	}
 
-	if err := h.service.MigratePositions(uid, req.Provider, req.AccountNo); err != nil {
+	if err := h.service.MigratePositions(actorOf(c, uid), req.Provider, req.AccountNo); err != nil {
 		return format.ErrorResponse(c, err)
 	}
 
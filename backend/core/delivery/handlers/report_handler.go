@@ -3,7 +3,11 @@ package handlers
 import (
 	"bytes"
 	"fmt"
+	"time"
+	"trade-tracker/core/domain"
 	"trade-tracker/core/services"
+	"trade-tracker/pkg/utils/export"
+	"trade-tracker/pkg/utils/format"
 
 	"github.com/gofiber/fiber/v3"
 )
@@ -22,7 +26,12 @@ func (h *ReportHandler) ExportProfile(c fiber.Ctx) error {
 		return c.Status(401).JSON(fiber.Map{"message": "Unauthorized."})
 	}
 
-	file, err := h.service.ExportProfile(userID)
+	filter, err := services.ParseReportFilter(c.Query("from"), c.Query("to"), c.Query("accounts"), c.Query("sheets"), c.Query("interval"), time.Now())
+	if err != nil {
+		return format.ErrorResponse(c, err)
+	}
+
+	file, err := h.service.ExportProfile(userID, filter)
 	if err != nil {
 		fmt.Printf("Error on ExportProfile: %s.\n", err)
 		return c.Status(500).JSON(fiber.Map{"error": "Failed to generate excel file."})
@@ -38,3 +47,106 @@ func (h *ReportHandler) ExportProfile(c fiber.Ctx) error {
 
 	return c.Send(buf.Bytes())
 }
+
+func (h *ReportHandler) ExportRecords(c fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(uint64)
+	if !ok {
+		return c.Status(401).JSON(fiber.Map{"message": "Unauthorized."})
+	}
+
+	exportFormat, ok := export.Lookup(c.Query("format"))
+	if !ok {
+		return c.Status(400).JSON(fiber.Map{"message": "Invalid format. Use csv, jsonl or ofx."})
+	}
+
+	filter, err := services.ParseReportFilter(c.Query("from"), c.Query("to"), c.Query("accounts"), c.Query("sheets"), "", time.Now())
+	if err != nil {
+		return format.ErrorResponse(c, err)
+	}
+
+	var buf bytes.Buffer
+	if err := h.service.ExportRecords(userID, filter, exportFormat.New(&buf)); err != nil {
+		fmt.Printf("Error on ExportRecords: %s.\n", err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to generate export file."})
+	}
+
+	c.Set("Content-Type", exportFormat.ContentType)
+	c.Set("Content-Disposition", fmt.Sprintf("attachment; filename=TPT_Export.%s", exportFormat.Extension))
+	return c.Send(buf.Bytes())
+}
+
+func (h *ReportHandler) ExportStatement(c fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(uint64)
+	if !ok {
+		return c.Status(401).JSON(fiber.Map{"message": "Unauthorized."})
+	}
+
+	period, err := services.ParseReportPeriod(c.Query("period"), time.Now())
+	if err != nil {
+		return format.ErrorResponse(c, err)
+	}
+
+	doc, err := h.service.ExportStatement(userID, period)
+	if err != nil {
+		fmt.Printf("Error on ExportStatement: %s.\n", err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to generate pdf file."})
+	}
+
+	var buf bytes.Buffer
+	if err := doc.Write(&buf); err != nil {
+		return err
+	}
+
+	c.Set("Content-Type", "application/pdf")
+	c.Set("Content-Disposition", fmt.Sprintf("attachment; filename=TPT_Statement_%s.pdf", period.Start.Format(periodFileFormat(period.Kind))))
+	return c.Send(buf.Bytes())
+}
+
+func (h *ReportHandler) ExportTax(c fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(uint64)
+	if !ok {
+		return c.Status(401).JSON(fiber.Map{"message": "Unauthorized."})
+	}
+
+	year, err := services.ParseTaxYear(c.Query("year"), time.Now())
+	if err != nil {
+		return format.ErrorResponse(c, err)
+	}
+
+	switch c.Query("format", "xlsx") {
+	case "xlsx":
+		file, err := h.service.ExportTaxExcel(userID, year)
+		if err != nil {
+			fmt.Printf("Error on ExportTax: %s.\n", err)
+			return c.Status(500).JSON(fiber.Map{"error": "Failed to generate excel file."})
+		}
+
+		var buf bytes.Buffer
+		if err := file.Write(&buf); err != nil {
+			return err
+		}
+
+		c.Set("Content-Type", "application/octet-stream")
+		c.Set("Content-Disposition", fmt.Sprintf("attachment; filename=TPT_Tax_%d.xlsx", year))
+		return c.Send(buf.Bytes())
+	case "csv":
+		data, err := h.service.ExportTaxCSV(userID, year)
+		if err != nil {
+			fmt.Printf("Error on ExportTax: %s.\n", err)
+			return c.Status(500).JSON(fiber.Map{"error": "Failed to generate csv file."})
+		}
+
+		c.Set("Content-Type", "text/csv")
+		c.Set("Content-Disposition", fmt.Sprintf("attachment; filename=TPT_Tax_%d.csv", year))
+		return c.Send(data)
+	default:
+		return c.Status(400).JSON(fiber.Map{"message": "Invalid format. Use xlsx or csv."})
+	}
+}
+
+func periodFileFormat(kind string) string {
+	if kind == domain.PeriodAnnual {
+		return "2006"
+	}
+	return "2006-01"
+}
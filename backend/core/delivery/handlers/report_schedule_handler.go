@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"strconv"
+	"trade-tracker/core/domain"
+	"trade-tracker/core/services"
+	"trade-tracker/pkg/utils/format"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v3"
+)
+
+type ReportScheduleHandler struct {
+	service  services.ReportScheduleService
+	validate *validator.Validate
+}
+
+func NewReportScheduleHandler(service services.ReportScheduleService) *ReportScheduleHandler {
+	return &ReportScheduleHandler{service: service, validate: validator.New()}
+}
+
+func (h *ReportScheduleHandler) bindRequest(c fiber.Ctx) (domain.ReportScheduleReq, error) {
+	var req domain.ReportScheduleReq
+	if err := c.Bind().Body(&req); err != nil {
+		return req, c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"message": "Failed to parse body."})
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		if validationErrors, ok := err.(validator.ValidationErrors); ok {
+			return req, c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"message": format.FormatError(validationErrors[0])})
+		}
+		return req, c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"message": "Invalid request."})
+	}
+	return req, nil
+}
+
+func (h *ReportScheduleHandler) HandleCreateSchedule(c fiber.Ctx) error {
+	uid, ok := c.Locals("user_id").(uint64)
+	if !ok {
+		return c.Status(401).JSON(fiber.Map{"message": "Unauthorized."})
+	}
+
+	req, err := h.bindRequest(c)
+	if err != nil {
+		return err
+	}
+
+	schedule, err := h.service.CreateSchedule(uid, req)
+	if err != nil {
+		return format.ErrorResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{"message": "Report schedule created.", "data": schedule})
+}
+
+func (h *ReportScheduleHandler) HandleGetSchedules(c fiber.Ctx) error {
+	uid, ok := c.Locals("user_id").(uint64)
+	if !ok {
+		return c.Status(401).JSON(fiber.Map{"message": "Unauthorized."})
+	}
+
+	schedules, err := h.service.GetSchedules(uid)
+	if err != nil {
+		return format.ErrorResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"data": schedules})
+}
+
+func (h *ReportScheduleHandler) HandleUpdateSchedule(c fiber.Ctx) error {
+	uid, ok := c.Locals("user_id").(uint64)
+	if !ok {
+		return c.Status(401).JSON(fiber.Map{"message": "Unauthorized."})
+	}
+
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"message": "Invalid schedule ID."})
+	}
+
+	req, err := h.bindRequest(c)
+	if err != nil {
+		return err
+	}
+
+	schedule, err := h.service.UpdateSchedule(uid, uint(id), req)
+	if err != nil {
+		return format.ErrorResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": "Report schedule updated.", "data": schedule})
+}
+
+func (h *ReportScheduleHandler) HandleDeleteSchedule(c fiber.Ctx) error {
+	uid, ok := c.Locals("user_id").(uint64)
+	if !ok {
+		return c.Status(401).JSON(fiber.Map{"message": "Unauthorized."})
+	}
+
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"message": "Invalid schedule ID."})
+	}
+
+	if err := h.service.DeleteSchedule(uid, uint(id)); err != nil {
+		return format.ErrorResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": "Report schedule deleted."})
+}
@@ -0,0 +1,145 @@
+package handlers
+
+import (
+	"strconv"
+	"trade-tracker/core/domain"
+	"trade-tracker/core/services"
+	"trade-tracker/pkg/utils/format"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v3"
+)
+
+type ShareHandler struct {
+	service  services.ShareService
+	validate *validator.Validate
+}
+
+func NewShareHandler(service services.ShareService) *ShareHandler {
+	return &ShareHandler{service: service, validate: validator.New()}
+}
+
+func (h *ShareHandler) HandleGetShares(c fiber.Ctx) error {
+	uid, ok := c.Locals("user_id").(uint64)
+	if !ok {
+		return c.Status(401).JSON(fiber.Map{"message": "Unauthorized."})
+	}
+
+	res, err := h.service.GetShares(uid)
+	if err != nil {
+		return format.ErrorResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"data": res})
+}
+
+func (h *ShareHandler) HandleInvite(c fiber.Ctx) error {
+	uid, ok := c.Locals("user_id").(uint64)
+	if !ok {
+		return c.Status(401).JSON(fiber.Map{"message": "Unauthorized."})
+	}
+
+	var req domain.ShareInviteReq
+	if err := c.Bind().Body(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"message": "Failed to parse body."})
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		if validationErrors, ok := err.(validator.ValidationErrors); ok {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"message": format.FormatError(validationErrors[0])})
+		}
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"message": "Invalid request."})
+	}
+
+	res, err := h.service.Invite(uid, req)
+	if err != nil {
+		return format.ErrorResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{"message": "Invitation sent.", "data": res})
+}
+
+func (h *ShareHandler) HandleAccept(c fiber.Ctx) error {
+	uid, ok := c.Locals("user_id").(uint64)
+	if !ok {
+		return c.Status(401).JSON(fiber.Map{"message": "Unauthorized."})
+	}
+
+	shareID, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"message": "Invalid share ID."})
+	}
+
+	if err := h.service.Accept(uid, uint(shareID)); err != nil {
+		return format.ErrorResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": "Invitation accepted."})
+}
+
+func (h *ShareHandler) HandleUpdateRole(c fiber.Ctx) error {
+	uid, ok := c.Locals("user_id").(uint64)
+	if !ok {
+		return c.Status(401).JSON(fiber.Map{"message": "Unauthorized."})
+	}
+
+	shareID, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"message": "Invalid share ID."})
+	}
+
+	var req domain.ShareRoleReq
+	if err := c.Bind().Body(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"message": "Failed to parse body."})
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		if validationErrors, ok := err.(validator.ValidationErrors); ok {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"message": format.FormatError(validationErrors[0])})
+		}
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"message": "Invalid request."})
+	}
+
+	if err := h.service.UpdateRole(uid, uint(shareID), req.Role); err != nil {
+		return format.ErrorResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": "Member role updated."})
+}
+
+func (h *ShareHandler) HandleRemove(c fiber.Ctx) error {
+	uid, ok := c.Locals("user_id").(uint64)
+	if !ok {
+		return c.Status(401).JSON(fiber.Map{"message": "Unauthorized."})
+	}
+
+	shareID, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"message": "Invalid share ID."})
+	}
+
+	if err := h.service.Remove(uid, uint(shareID)); err != nil {
+		return format.ErrorResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": "Share removed."})
+}
+
+func (h *ShareHandler) HandleGetSharedAccount(c fiber.Ctx) error {
+	uid, ok := c.Locals("user_id").(uint64)
+	if !ok {
+		return c.Status(401).JSON(fiber.Map{"message": "Unauthorized."})
+	}
+
+	shareID, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"message": "Invalid share ID."})
+	}
+
+	res, err := h.service.GetSharedAccount(uid, uint(shareID))
+	if err != nil {
+		return format.ErrorResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"data": res})
+}
@@ -60,7 +60,7 @@ func (h *TransactionHandler) HandleUpdateTransaction(c fiber.Ctx) error {
 		return c.Status(400).JSON(fiber.Map{"message": "Invalid request."})
 	}
 
-	if err := h.service.UpdateTransaction(uint(txIdBase), uid, req); err != nil {
+	if err := h.service.UpdateTransaction(actorOf(c, uid), uint(txIdBase), req); err != nil {
 		return format.ErrorResponse(c, err)
 	}
 
@@ -87,10 +87,60 @@ func (h *TransactionHandler) HandleMigrateTransactions(c fiber.Ctx) error {
 		return c.Status(400).JSON(fiber.Map{"message": "Provider, account_no, and transaction_ids are required."})
 	}
 
-	if err := h.service.MigrateTransactions(uid, req.Provider, req.AccountNo, req.TransactionIDs); err != nil {
+	if err := h.service.MigrateTransactions(actorOf(c, uid), req.Provider, req.AccountNo, req.TransactionIDs); err != nil {
 		return format.ErrorResponse(c, err)
 	}
 
 	return c.Status(200).JSON(fiber.Map{"message": "Transactions migrated successfully."})
 }
 
+
+func (h *TransactionHandler) HandleRemoveTransaction(c fiber.Ctx) error {
+	uid, ok := c.Locals("user_id").(uint64)
+	if !ok {
+		return c.Status(401).JSON(fiber.Map{"message": "Unauthorized."})
+	}
+
+	txId, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"message": "Invalid transaction ID."})
+	}
+
+	if err := h.service.RemoveTransaction(actorOf(c, uid), uint(txId)); err != nil {
+		return format.ErrorResponse(c, err)
+	}
+
+	return c.Status(200).JSON(fiber.Map{"message": "Transaction moved to trash."})
+}
+
+func (h *TransactionHandler) HandleRestoreTransaction(c fiber.Ctx) error {
+	uid, ok := c.Locals("user_id").(uint64)
+	if !ok {
+		return c.Status(401).JSON(fiber.Map{"message": "Unauthorized."})
+	}
+
+	txId, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"message": "Invalid transaction ID."})
+	}
+
+	if err := h.service.RestoreTransaction(actorOf(c, uid), uint(txId)); err != nil {
+		return format.ErrorResponse(c, err)
+	}
+
+	return c.Status(200).JSON(fiber.Map{"message": "Transaction restored."})
+}
+
+func (h *TransactionHandler) HandleGetTrash(c fiber.Ctx) error {
+	uid, ok := c.Locals("user_id").(uint64)
+	if !ok {
+		return c.Status(401).JSON(fiber.Map{"message": "Unauthorized."})
+	}
+
+	data, err := h.service.GetTrash(uid)
+	if err != nil {
+		return format.ErrorResponse(c, err)
+	}
+
+	return c.Status(200).JSON(fiber.Map{"transactions": data})
+}
@@ -0,0 +1,107 @@
+package handlers
+
+import (
+	"trade-tracker/core/domain"
+	"trade-tracker/core/services"
+	"trade-tracker/pkg/utils/format"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v3"
+)
+
+type TwoFactorHandler struct {
+	service  services.TwoFactorService
+	validate *validator.Validate
+}
+
+func NewTwoFactorHandler(service services.TwoFactorService) *TwoFactorHandler {
+	return &TwoFactorHandler{service: service, validate: validator.New()}
+}
+
+// parseBody binds and validates req, returning a client-facing message when it is invalid.
+func (h *TwoFactorHandler) parseBody(c fiber.Ctx, req interface{}) string {
+	if err := c.Bind().Body(req); err != nil {
+		return "Failed to parse body."
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		if validationErrors, ok := err.(validator.ValidationErrors); ok {
+			return format.FormatError(validationErrors[0])
+		}
+		return "Invalid request."
+	}
+	return ""
+}
+
+func (h *TwoFactorHandler) HandleSetup(c fiber.Ctx) error {
+	uid, ok := c.Locals("user_id").(uint64)
+	if !ok {
+		return c.Status(401).JSON(fiber.Map{"message": "Unauthorized."})
+	}
+
+	res, err := h.service.Setup(uid)
+	if err != nil {
+		return format.ErrorResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"data": res})
+}
+
+func (h *TwoFactorHandler) HandleEnable(c fiber.Ctx) error {
+	uid, ok := c.Locals("user_id").(uint64)
+	if !ok {
+		return c.Status(401).JSON(fiber.Map{"message": "Unauthorized."})
+	}
+
+	var req domain.TwoFactorCodeReq
+	if msg := h.parseBody(c, &req); msg != "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"message": msg})
+	}
+
+	codes, err := h.service.Enable(uid, req.Code)
+	if err != nil {
+		return format.ErrorResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message":        "Two-factor authentication enabled. Store your recovery codes somewhere safe.",
+		"recovery_codes": codes,
+	})
+}
+
+func (h *TwoFactorHandler) HandleDisable(c fiber.Ctx) error {
+	uid, ok := c.Locals("user_id").(uint64)
+	if !ok {
+		return c.Status(401).JSON(fiber.Map{"message": "Unauthorized."})
+	}
+
+	var req domain.TwoFactorDisableReq
+	if msg := h.parseBody(c, &req); msg != "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"message": msg})
+	}
+
+	if err := h.service.Disable(uid, req.Password, req.Code); err != nil {
+		return format.ErrorResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": "Two-factor authentication disabled."})
+}
+
+func (h *TwoFactorHandler) HandleRegenerateRecoveryCodes(c fiber.Ctx) error {
+	uid, ok := c.Locals("user_id").(uint64)
+	if !ok {
+		return c.Status(401).JSON(fiber.Map{"message": "Unauthorized."})
+	}
+
+	var req domain.TwoFactorCodeReq
+	if msg := h.parseBody(c, &req); msg != "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"message": msg})
+	}
+
+	codes, err := h.service.RegenerateRecoveryCodes(uid, req.Code)
+	if err != nil {
+		return format.ErrorResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"recovery_codes": codes})
+}
@@ -1,13 +1,16 @@
 package handlers
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
 	"log"
 	"os"
+	"strconv"
 	"time"
 
 	"trade-tracker/core/domain"
 	"trade-tracker/core/services"
-	"trade-tracker/pkg/utils/auth"
 	"trade-tracker/pkg/utils/format"
 
 	"github.com/go-playground/validator/v10"
@@ -15,14 +18,53 @@ import (
 )
 
 type UserHandler struct {
-	service  services.UserService
-	validate *validator.Validate
+	service          services.UserService
+	sessionService   services.SessionService
+	twoFactorService services.TwoFactorService
+	validate         *validator.Validate
 }
 
-func NewUserHandler(service services.UserService) *UserHandler {
+func NewUserHandler(service services.UserService, sessionService services.SessionService, twoFactorService services.TwoFactorService) *UserHandler {
 	return &UserHandler{
-		service:  service,
-		validate: validator.New(),
+		service:          service,
+		sessionService:   sessionService,
+		twoFactorService: twoFactorService,
+		validate:         validator.New(),
+	}
+}
+
+func setAuthCookies(c fiber.Ctx, tokens *domain.AuthTokens) {
+	c.Cookie(&fiber.Cookie{
+		Name:     "token",
+		Value:    tokens.AccessToken,
+		Expires:  tokens.AccessExpiresAt,
+		HTTPOnly: true,
+		Secure:   os.Getenv("PRODUCTION_MODE") == "true",
+		SameSite: "Lax",
+		Path:     "/",
+	})
+	c.Cookie(&fiber.Cookie{
+		Name:     "refresh_token",
+		Value:    tokens.RefreshToken,
+		Expires:  tokens.RefreshExpiresAt,
+		HTTPOnly: true,
+		Secure:   os.Getenv("PRODUCTION_MODE") == "true",
+		SameSite: "Strict",
+		Path:     "/",
+	})
+}
+
+func clearAuthCookies(c fiber.Ctx) {
+	for _, name := range []string{"token", "refresh_token"} {
+		c.Cookie(&fiber.Cookie{
+			Name:     name,
+			Value:    "",
+			Expires:  time.Unix(0, 0),
+			HTTPOnly: true,
+			Secure:   (os.Getenv("PRODUCTION_MODE") == "true"),
+			SameSite: "Lax",
+			Path:     "/",
+		})
 	}
 }
 
@@ -69,28 +111,97 @@ func (h *UserHandler) HandleLogin(c fiber.Ctx) error {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"code": fiber.StatusBadRequest, "message": "Invalid request."})
 	}
 
-	user, err := h.service.Login(req.Identifier, req.Password)
+	user, err := h.service.Login(req.Identifier, req.Password, c.IP(), c.Get("User-Agent"))
 	if err != nil {
 		return format.ErrorResponse(c, err)
 	}
 
-	token, err := auth.GenerateToken(user.ID)
+	if user.TOTPEnabled {
+		mfaToken, err := h.twoFactorService.CreateChallenge(uint64(user.ID))
+		if err != nil {
+			return format.ErrorResponse(c, err)
+		}
+
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{
+			"code":                fiber.StatusOK,
+			"message":             "Two-factor authentication required.",
+			"two_factor_required": true,
+			"mfa_token":           mfaToken,
+		})
+	}
+
+	return h.startSession(c, uint64(user.ID))
+}
+
+func (h *UserHandler) HandleLoginTwoFactor(c fiber.Ctx) error {
+	var req domain.TwoFactorLoginReq
+	if err := c.Bind().Body(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"code": fiber.StatusBadRequest, "message": "Failed to parse body."})
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		if validationErrors, ok := err.(validator.ValidationErrors); ok {
+			errFirst := validationErrors[0]
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"code": fiber.StatusBadRequest, "message": format.FormatError(errFirst)})
+		}
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"code": fiber.StatusBadRequest, "message": "Invalid request."})
+	}
+
+	uid, err := h.twoFactorService.VerifyChallenge(req.MFAToken, req.Code)
+	if err != nil {
+		if uid != 0 && errors.Is(err, domain.ErrInvalidTwoFactorCode) {
+			h.service.RecordFailedLogin(uid, c.IP(), c.Get("User-Agent"), domain.LoginReasonWrongCode)
+		}
+		return format.ErrorResponse(c, err)
+	}
+
+	return h.startSession(c, uid)
+}
+
+func (h *UserHandler) startSession(c fiber.Ctx, userID uint64) error {
+	tokens, err := h.sessionService.CreateSession(userID, c.Get("User-Agent"), c.IP())
 	if err != nil {
 		log.Printf("Error when trying to generate token: %v.\n", err.Error())
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"code": fiber.StatusInternalServerError, "message": "Internal server error."})
 	}
 
-	c.Cookie(&fiber.Cookie{
-		Name:     "token",
-		Value:    token,
-		Expires:  time.Now().Add(24 * time.Hour),
-		HTTPOnly: true,
-		Secure:   os.Getenv("PRODUCTION_MODE") == "true",
-		SameSite: "Lax",
-		Path:     "/",
+	h.service.RecordLogin(userID, c.IP(), c.Get("User-Agent"))
+
+	setAuthCookies(c, tokens)
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"code":                     fiber.StatusOK,
+		"message":                  "Login success.",
+		"token":                    tokens.AccessToken,
+		"token_expires_at":         tokens.AccessExpiresAt,
+		"refresh_token":            tokens.RefreshToken,
+		"refresh_token_expires_at": tokens.RefreshExpiresAt,
 	})
+}
+
+func (h *UserHandler) HandleRefresh(c fiber.Ctx) error {
+	refreshToken := c.Cookies("refresh_token")
+	if refreshToken == "" {
+		var req domain.RefreshTokenReq
+		if err := c.Bind().Body(&req); err == nil {
+			refreshToken = req.RefreshToken
+		}
+	}
 
-	return c.Status(fiber.StatusOK).JSON(fiber.Map{"code": fiber.StatusOK, "message": "Login success.", "token": token})
+	tokens, err := h.sessionService.RefreshSession(refreshToken, c.Get("User-Agent"), c.IP())
+	if err != nil {
+		clearAuthCookies(c)
+		return format.ErrorResponse(c, err)
+	}
+
+	setAuthCookies(c, tokens)
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"code":                     fiber.StatusOK,
+		"message":                  "Token refreshed.",
+		"token":                    tokens.AccessToken,
+		"token_expires_at":         tokens.AccessExpiresAt,
+		"refresh_token":            tokens.RefreshToken,
+		"refresh_token_expires_at": tokens.RefreshExpiresAt,
+	})
 }
 
 func (h *UserHandler) HandleGetMe(c fiber.Ctx) error {
@@ -107,17 +218,266 @@ func (h *UserHandler) HandleGetMe(c fiber.Ctx) error {
 }
 
 func (h *UserHandler) Logout(c fiber.Ctx) error {
-	c.Cookie(&fiber.Cookie{
-		Name:     "token",
-		Value:    "",
-		Expires:  time.Unix(0, 0),
-		HTTPOnly: true,
-		Secure:   (os.Getenv("PRODUCTION_MODE") == "true"),
-		SameSite: "Lax",
-		Path:     "/",
-	})
+	if refreshToken := c.Cookies("refresh_token"); refreshToken != "" {
+		if err := h.sessionService.RevokeByRefreshToken(refreshToken); err != nil {
+			log.Printf("Error when trying to revoke session on logout: %v.\n", err)
+		}
+	}
+
+	clearAuthCookies(c)
 	return c.Status(fiber.StatusOK).JSON(fiber.Map{
 		"code":    fiber.StatusOK,
 		"message": "Logged out from server",
 	})
 }
+
+func (h *UserHandler) HandleGetSessions(c fiber.Ctx) error {
+	uid, ok := c.Locals("user_id").(uint64)
+	if !ok {
+		return c.Status(401).JSON(fiber.Map{"message": "Unauthorized."})
+	}
+	sid, _ := c.Locals("session_id").(uint)
+
+	sessions, err := h.sessionService.GetSessions(uid, sid)
+	if err != nil {
+		return format.ErrorResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"sessions": sessions})
+}
+
+func (h *UserHandler) HandleGetLoginHistory(c fiber.Ctx) error {
+	uid, ok := c.Locals("user_id").(uint64)
+	if !ok {
+		return c.Status(401).JSON(fiber.Map{"message": "Unauthorized."})
+	}
+
+	limit, _ := strconv.Atoi(c.Query("limit"))
+	logins, err := h.service.GetLoginHistory(uid, limit)
+	if err != nil {
+		return format.ErrorResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"logins": logins})
+}
+
+func (h *UserHandler) HandleRevokeSession(c fiber.Ctx) error {
+	uid, ok := c.Locals("user_id").(uint64)
+	if !ok {
+		return c.Status(401).JSON(fiber.Map{"message": "Unauthorized."})
+	}
+
+	sessionID, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"message": "Invalid session ID."})
+	}
+
+	if err := h.sessionService.RevokeSession(uint(sessionID), uid); err != nil {
+		return format.ErrorResponse(c, err)
+	}
+
+	if sid, _ := c.Locals("session_id").(uint); sid == uint(sessionID) {
+		clearAuthCookies(c)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": "Session revoked."})
+}
+
+func (h *UserHandler) HandleRevokeAllSessions(c fiber.Ctx) error {
+	uid, ok := c.Locals("user_id").(uint64)
+	if !ok {
+		return c.Status(401).JSON(fiber.Map{"message": "Unauthorized."})
+	}
+
+	if err := h.sessionService.RevokeAllSessions(uid); err != nil {
+		return format.ErrorResponse(c, err)
+	}
+
+	clearAuthCookies(c)
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": "Logged out from all sessions."})
+}
+
+func (h *UserHandler) HandleSendVerification(c fiber.Ctx) error {
+	uid, ok := c.Locals("user_id").(uint64)
+	if !ok {
+		return c.Status(401).JSON(fiber.Map{"message": "Unauthorized."})
+	}
+
+	if err := h.service.SendVerificationEmail(uid); err != nil {
+		return format.ErrorResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": "Verification email sent."})
+}
+
+func (h *UserHandler) HandleVerifyEmail(c fiber.Ctx) error {
+	var req domain.VerifyEmailReq
+	if err := c.Bind().Body(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"message": "Failed to parse body."})
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		if validationErrors, ok := err.(validator.ValidationErrors); ok {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"message": format.FormatError(validationErrors[0])})
+		}
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"message": "Invalid request."})
+	}
+
+	if err := h.service.VerifyEmail(req.Token); err != nil {
+		return format.ErrorResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": "Email verified."})
+}
+
+func (h *UserHandler) HandleForgotPassword(c fiber.Ctx) error {
+	var req domain.ForgotPasswordReq
+	if err := c.Bind().Body(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"message": "Failed to parse body."})
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		if validationErrors, ok := err.(validator.ValidationErrors); ok {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"message": format.FormatError(validationErrors[0])})
+		}
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"message": "Invalid request."})
+	}
+
+	if err := h.service.RequestPasswordReset(req.Email); err != nil {
+		log.Printf("Error when trying to send password reset email: %v.\n", err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": "If the email is registered, a reset link has been sent."})
+}
+
+func (h *UserHandler) HandleResetPassword(c fiber.Ctx) error {
+	var req domain.ResetPasswordReq
+	if err := c.Bind().Body(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"message": "Failed to parse body."})
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		if validationErrors, ok := err.(validator.ValidationErrors); ok {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"message": format.FormatError(validationErrors[0])})
+		}
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"message": "Invalid request."})
+	}
+
+	uid, err := h.service.ResetPassword(req.Token, req.Password)
+	if err != nil {
+		return format.ErrorResponse(c, err)
+	}
+
+	if err := h.sessionService.RevokeAllSessions(uid); err != nil {
+		log.Printf("Error when trying to revoke sessions after password reset: %v.\n", err)
+	}
+
+	clearAuthCookies(c)
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": "Password has been reset, please log in again."})
+}
+
+func (h *UserHandler) HandleUpdateProfile(c fiber.Ctx) error {
+	uid, ok := c.Locals("user_id").(uint64)
+	if !ok {
+		return c.Status(401).JSON(fiber.Map{"message": "Unauthorized."})
+	}
+
+	var req domain.UpdateProfileReq
+	if err := c.Bind().Body(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"message": "Failed to parse body."})
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		if validationErrors, ok := err.(validator.ValidationErrors); ok {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"message": format.FormatError(validationErrors[0])})
+		}
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"message": "Invalid request."})
+	}
+
+	if err := h.service.UpdateProfile(uid, req); err != nil {
+		return format.ErrorResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": "Profile updated."})
+}
+
+func (h *UserHandler) HandleChangePassword(c fiber.Ctx) error {
+	uid, ok := c.Locals("user_id").(uint64)
+	if !ok {
+		return c.Status(401).JSON(fiber.Map{"message": "Unauthorized."})
+	}
+
+	var req domain.ChangePasswordReq
+	if err := c.Bind().Body(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"message": "Failed to parse body."})
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		if validationErrors, ok := err.(validator.ValidationErrors); ok {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"message": format.FormatError(validationErrors[0])})
+		}
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"message": "Invalid request."})
+	}
+
+	if err := h.service.ChangePassword(uid, req.CurrentPassword, req.NewPassword); err != nil {
+		return format.ErrorResponse(c, err)
+	}
+
+	sid, _ := c.Locals("session_id").(uint)
+	if err := h.sessionService.RevokeOtherSessions(uid, sid); err != nil {
+		log.Printf("Error when trying to revoke sessions after password change: %v.\n", err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": "Password changed. Other sessions have been logged out."})
+}
+
+func (h *UserHandler) HandleExportAccount(c fiber.Ctx) error {
+	uid, ok := c.Locals("user_id").(uint64)
+	if !ok {
+		return c.Status(401).JSON(fiber.Map{"message": "Unauthorized."})
+	}
+
+	export, err := h.service.ExportAccount(uid)
+	if err != nil {
+		return format.ErrorResponse(c, err)
+	}
+
+	data, err := json.MarshalIndent(export, "", "  ")
+	if err != nil {
+		return format.ErrorResponse(c, err)
+	}
+
+	c.Set("Content-Type", "application/json")
+	c.Set("Content-Disposition", fmt.Sprintf("attachment; filename=TPT_Account_%s.json", export.ExportedAt.Format("20060102")))
+	return c.Send(data)
+}
+
+func (h *UserHandler) HandleDeleteAccount(c fiber.Ctx) error {
+	uid, ok := c.Locals("user_id").(uint64)
+	if !ok {
+		return c.Status(401).JSON(fiber.Map{"message": "Unauthorized."})
+	}
+
+	var req domain.DeleteAccountReq
+	if err := c.Bind().Body(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"message": "Failed to parse body."})
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		if validationErrors, ok := err.(validator.ValidationErrors); ok {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"message": format.FormatError(validationErrors[0])})
+		}
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"message": "Invalid request."})
+	}
+
+	if err := h.twoFactorService.CheckCode(uid, req.Code); err != nil {
+		return format.ErrorResponse(c, err)
+	}
+
+	if err := h.service.DeleteAccount(uid, req.Password, req.Confirm); err != nil {
+		return format.ErrorResponse(c, err)
+	}
+
+	clearAuthCookies(c)
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": "Account deleted. A copy of your data was sent to your email."})
+}
@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"fmt"
+	"time"
+	"trade-tracker/core/domain"
+	"trade-tracker/core/services"
+	"trade-tracker/core/worker"
+	"trade-tracker/pkg/utils/calendar"
+	"trade-tracker/pkg/utils/format"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+type WorkerHandler struct {
+	service  services.JobService
+	exchange *calendar.Exchange
+}
+
+func NewWorkerHandler(service services.JobService, exchange *calendar.Exchange) *WorkerHandler {
+	return &WorkerHandler{service: service, exchange: exchange}
+}
+
+func (h *WorkerHandler) HandleUpdateStock(c fiber.Ctx) error {
+	var now time.Time
+	loc := h.exchange.Location
+
+	if time_ := c.Query("time"); time_ != "" {
+		parsedTime, err := time.Parse(time.RFC3339, time_)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"message": "Mismatch time format (use RFC3339 instead)."})
+		}
+		now = parsedTime.In(loc)
+	} else {
+		now = time.Now().In(loc)
+	}
+
+	run, err := h.service.TriggerJob(worker.UpdateStockJobName, now)
+	if err != nil {
+		return format.ErrorResponse(c, err)
+	}
+
+	res := domain.WorkerRunResponse{
+		Status:     run.Status,
+		Updated:    run.Processed,
+		DurationMs: run.DurationMs,
+		MarketOpen: h.exchange.IsOpen(now),
+		RanAt:      now,
+		Message:    run.Message,
+	}
+
+	if run.Status == domain.JobStatusFailed {
+		fmt.Printf("Error on UpdateStock: %s.\n", run.Error)
+		res.Message = "Could not fetch stock data."
+		return c.Status(fiber.StatusBadGateway).JSON(res)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(res)
+}
+
+func (h *WorkerHandler) HandleDeliverReports(c fiber.Ctx) error {
+	return h.runJob(c, worker.DeliverReportsJobName)
+}
+
+func (h *WorkerHandler) HandlePurgeTrash(c fiber.Ctx) error {
+	return h.runJob(c, worker.PurgeTrashJobName)
+}
+
+func (h *WorkerHandler) HandlePurgeIdempotency(c fiber.Ctx) error {
+	return h.runJob(c, worker.PurgeIdempotencyJobName)
+}
+
+// runJob runs the named job for an external scheduler and answers with the recorded run.
+// A failed run answers 502, so the caller sees the failure as well.
+func (h *WorkerHandler) runJob(c fiber.Ctx, name string) error {
+	run, err := h.service.TriggerJob(name, time.Now())
+	if err != nil {
+		return format.ErrorResponse(c, err)
+	}
+
+	if run.Status == domain.JobStatusFailed {
+		fmt.Printf("Error on %s: %s.\n", name, run.Error)
+		return c.Status(fiber.StatusBadGateway).JSON(run)
+	}
+	return c.Status(fiber.StatusOK).JSON(run)
+}
@@ -6,8 +6,10 @@ import (
 	"strings"
 
 	"trade-tracker/core/delivery/handlers"
+	"trade-tracker/core/domain"
 	"trade-tracker/core/services"
 	"trade-tracker/pkg/middleware"
+	"trade-tracker/pkg/utils/calendar"
 
 	"time"
 
@@ -15,11 +17,16 @@ import (
 	"github.com/gofiber/fiber/v3/middleware/cors"
 	"github.com/gofiber/fiber/v3/middleware/helmet"
 	"github.com/gofiber/fiber/v3/middleware/limiter"
+	"github.com/gofiber/fiber/v3/middleware/requestid"
 )
 
 func InitRoutes(uService services.UserService, pService services.PositionService,
-	tService services.TransactionService, nService services.NoteService,
-	bService services.BalanceService, rService services.ReportService, aService services.AssetService) *fiber.App {
+	tService services.TransactionService, nService services.NoteService, sService services.SessionService,
+	bService services.BalanceService, rService services.ReportService, aService services.AssetService,
+	jService services.JobService, tfService services.TwoFactorService, kService services.APIKeyService,
+	adService services.AdminService, shService services.ShareService, auService services.AuditService,
+	idService services.IdempotencyService, rsService services.ReportScheduleService,
+	jrService services.JournalService, exchange *calendar.Exchange) *fiber.App {
 	app := fiber.New()
 	originsEnv := os.Getenv("ALLOW_ORIGINS")
 	var origins []string
@@ -31,11 +38,12 @@ func InitRoutes(uService services.UserService, pService services.PositionService
 		origins = []string{"http://localhost:3000", "https://tpt-v3.vercel.app"}
 	}
 
+	app.Use(requestid.New())
 	app.Use(helmet.New())
 	app.Use(cors.New(cors.Config{
 		AllowOrigins:     origins,
 		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "PATCH", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization", "X-Requested-With"},
+		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization", "X-Requested-With", "X-API-Key", "X-Request-ID", "Idempotency-Key"},
 		AllowCredentials: true,
 	}))
 
@@ -43,7 +51,7 @@ func InitRoutes(uService services.UserService, pService services.PositionService
 		if c.Method() == "OPTIONS" {
 			c.Set("Access-Control-Allow-Origin", c.Get("Origin"))
 			c.Set("Access-Control-Allow-Methods", "GET,POST,PUT,DELETE,PATCH,OPTIONS")
-			c.Set("Access-Control-Allow-Headers", "Origin, Content-Type, Accept, Authorization, X-Requested-With")
+			c.Set("Access-Control-Allow-Headers", "Origin, Content-Type, Accept, Authorization, X-Requested-With, X-API-Key, X-Request-ID, Idempotency-Key")
 			c.Set("Access-Control-Allow-Credentials", "true")
 			return c.SendStatus(fiber.StatusNoContent)
 		}
@@ -71,66 +79,190 @@ func InitRoutes(uService services.UserService, pService services.PositionService
 		return c.SendString("Hello World!")
 	})
 
-	// Strict Auth Rate Limiter (5 reqs / 15 min)
-	authLimiter := limiter.New(limiter.Config{
-		Max:        5,
-		Expiration: 15 * time.Minute,
-	})
+	// Strict Auth Rate Limiters (per 15 min). Each flow counts on its own, so failing to log in
+	// does not block a password change. Authenticated flows count per user instead of per IP.
+	authLimiter := func(max int, perUser bool) fiber.Handler {
+		cfg := limiter.Config{
+			Max:        max,
+			Expiration: 15 * time.Minute,
+		}
+		if perUser {
+			cfg.KeyGenerator = func(c fiber.Ctx) string {
+				return fmt.Sprint(c.Locals("user_id"))
+			}
+		}
+		return limiter.New(cfg)
+	}
+	// a login with 2FA takes two requests
+	loginLimiter := authLimiter(10, false)
+	registerLimiter := authLimiter(5, false)
+	recoveryLimiter := authLimiter(5, false)
+	verificationLimiter := authLimiter(5, true)
+	passwordLimiter := authLimiter(5, true)
+	twoFactorLimiter := authLimiter(5, true)
+	// access tokens last 15 minutes, so this leaves room for several tabs and devices behind one IP
+	refreshLimiter := authLimiter(30, false)
+
+	authMiddleware := middleware.AuthMiddleware(sService, kService)
+	// Role checks also reject locked accounts, so every authenticated group carries one.
+	userRole := middleware.RequireRole(uService, domain.RoleUser, domain.RoleAdmin)
+	adminRole := middleware.RequireRole(uService, domain.RoleAdmin)
+	verifiedMiddleware := middleware.VerifiedMiddleware(uService)
+	sessionOnly := middleware.SessionOnly()
+	// trade:write keys may also read what they trade on
+	readScope := middleware.RequireScope(domain.ScopePortfolioRead, domain.ScopeTradeWrite)
+	tradeScope := middleware.RequireScope(domain.ScopeTradeWrite)
+	// Only acts on mutating requests that carry an Idempotency-Key header
+	idempotency := middleware.Idempotency(idService)
 
 	accountApi := api.Group("/account")
-	userService := handlers.NewUserHandler(uService)
+	userService := handlers.NewUserHandler(uService, sService, tfService)
+	twoFactorService := handlers.NewTwoFactorHandler(tfService)
 
-	accountApi.Post("/register", authLimiter, userService.HandleRegister)
-	accountApi.Post("/login", authLimiter, userService.HandleLogin)
+	accountApi.Post("/register", registerLimiter, userService.HandleRegister)
+	accountApi.Post("/login", loginLimiter, userService.HandleLogin)
+	accountApi.Post("/login/2fa", loginLimiter, userService.HandleLoginTwoFactor)
+	accountApi.Post("/refresh", refreshLimiter, userService.HandleRefresh)
 	accountApi.Post("/logout", userService.Logout)
+	accountApi.Post("/verify-email", userService.HandleVerifyEmail)
+	accountApi.Post("/forgot-password", recoveryLimiter, userService.HandleForgotPassword)
+	accountApi.Post("/reset-password", recoveryLimiter, userService.HandleResetPassword)
 
-	userApi := api.Group("/user", middleware.AuthMiddleware())
+	userApi := api.Group("/user", authMiddleware, userRole, sessionOnly, idempotency)
 
 	userApi.Get("/me", userService.HandleGetMe)
+	userApi.Post("/verify-email/send", verificationLimiter, userService.HandleSendVerification)
+	userApi.Put("/profile", userService.HandleUpdateProfile)
+	userApi.Put("/password", passwordLimiter, userService.HandleChangePassword)
+	userApi.Get("/export", userService.HandleExportAccount)
+	userApi.Delete("/account", passwordLimiter, userService.HandleDeleteAccount)
+	userApi.Get("/sessions", userService.HandleGetSessions)
+	userApi.Delete("/sessions/:id", userService.HandleRevokeSession)
+	userApi.Post("/sessions/revoke-all", userService.HandleRevokeAllSessions)
+	userApi.Get("/logins", userService.HandleGetLoginHistory)
 
-	positionApi := api.Group("/position", middleware.AuthMiddleware())
+	auditService := handlers.NewAuditHandler(auService)
+	userApi.Get("/audit-log", auditService.HandleGetLogs)
+	// secretResponse keeps issued secrets out of the stored idempotent responses
+	secretResponse := middleware.SecretResponse()
+	userApi.Post("/2fa/setup", secretResponse, twoFactorService.HandleSetup)
+	userApi.Post("/2fa/enable", twoFactorLimiter, secretResponse, twoFactorService.HandleEnable)
+	userApi.Post("/2fa/disable", twoFactorLimiter, twoFactorService.HandleDisable)
+	userApi.Post("/2fa/recovery-codes", twoFactorLimiter, secretResponse, twoFactorService.HandleRegenerateRecoveryCodes)
+
+	apiKeyService := handlers.NewAPIKeyHandler(kService)
+
+	userApi.Get("/api-keys", apiKeyService.HandleGetKeys)
+	userApi.Post("/api-keys", secretResponse, apiKeyService.HandleCreateKey)
+	userApi.Delete("/api-keys/:id", apiKeyService.HandleRevokeKey)
+
+	positionApi := api.Group("/position", authMiddleware, userRole, readScope, idempotency)
 	positionService := handlers.NewPositionHandler(pService)
 
-	positionApi.Post("/add/:type", positionService.HandleAddPosition)
+	positionApi.Post("/add/:type", tradeScope, verifiedMiddleware, positionService.HandleAddPosition)
 	positionApi.Get("/get-price/:ticker", positionService.HandleGetTickerMarketPrice)
 	positionApi.Get("/portfolio", positionService.HandleGetPortfolio)
-	positionApi.Post("/migrate", positionService.HandleMigratePositions)
+	positionApi.Post("/migrate", tradeScope, verifiedMiddleware, positionService.HandleMigratePositions)
 
-	trxApi := api.Group("/transactions", middleware.AuthMiddleware())
+	trxApi := api.Group("/transactions", authMiddleware, userRole, readScope, idempotency)
 	trxService := handlers.NewTransactionHandler(tService)
 
 	trxApi.Get("/my-info", trxService.HandleGetLocalTransaction)
-	trxApi.Put("/update/:id", trxService.HandleUpdateTransaction)
-	trxApi.Post("/migrate", trxService.HandleMigrateTransactions)
+	trxApi.Put("/update/:id", tradeScope, verifiedMiddleware, trxService.HandleUpdateTransaction)
+	trxApi.Post("/migrate", tradeScope, verifiedMiddleware, trxService.HandleMigrateTransactions)
+	trxApi.Delete("/remove/:id", tradeScope, verifiedMiddleware, trxService.HandleRemoveTransaction)
+	trxApi.Get("/trash", trxService.HandleGetTrash)
+	trxApi.Post("/restore/:id", tradeScope, verifiedMiddleware, trxService.HandleRestoreTransaction)
 
-	noteApi := api.Group("/notes", middleware.AuthMiddleware())
+	noteApi := api.Group("/notes", authMiddleware, userRole, sessionOnly, idempotency)
 	noteService := handlers.NewNoteHandler(nService)
 
 	noteApi.Get("/get", noteService.HandleGetNotes)
 	noteApi.Post("/add", noteService.HandleAddNote)
 	noteApi.Delete("/remove/:nId", noteService.HandleRemoveNote)
 	noteApi.Put("/update/:nId", noteService.HandleUpdateNote)
+	noteApi.Get("/trash", noteService.HandleGetTrash)
+	noteApi.Post("/restore/:nId", noteService.HandleRestoreNote)
+	noteApi.Delete("/trash/:nId", noteService.HandlePurgeNote)
+	noteApi.Post("/:nId/images", noteService.HandleUploadImage)
+	noteApi.Delete("/:nId/images/:imageId", noteService.HandleRemoveImage)
+
+	api.Get("/uploads/:key", noteService.HandleGetImage)
+
+	journalApi := api.Group("/journal", authMiddleware, userRole, sessionOnly, idempotency)
+	journalService := handlers.NewJournalHandler(jrService)
 
-	balanceApi := api.Group("/balance", middleware.AuthMiddleware())
+	journalApi.Get("/get", journalService.HandleGetEntries)
+	journalApi.Post("/add", journalService.HandleAddEntry)
+	journalApi.Put("/update/:id", journalService.HandleUpdateEntry)
+	journalApi.Delete("/remove/:id", journalService.HandleRemoveEntry)
+	journalApi.Get("/trade/:id", journalService.HandleGetTradeJournal)
+	journalApi.Get("/position/:id", journalService.HandleGetPositionJournal)
+	journalApi.Get("/analysis", journalService.HandleAnalyze)
+
+	balanceApi := api.Group("/balance", authMiddleware, userRole, readScope, idempotency)
 	balanceService := handlers.NewBalanceHandler(bService)
 
-	balanceApi.Post("/update-balance", balanceService.HandleUpdateBalance)
+	balanceApi.Post("/update-balance", tradeScope, verifiedMiddleware, balanceService.HandleUpdateBalance)
+	balanceApi.Post("/dividend", tradeScope, verifiedMiddleware, balanceService.HandleRecordDividend)
 	balanceApi.Get("/accounts/:type", balanceService.HandleGetAccountsByType)
 
-	reportApi := api.Group("/report", middleware.AuthMiddleware())
+	shareApi := api.Group("/shares", authMiddleware, userRole, readScope, idempotency)
+	shareService := handlers.NewShareHandler(shService)
+
+	shareApi.Get("/", shareService.HandleGetShares)
+	shareApi.Get("/:id/account", shareService.HandleGetSharedAccount)
+	shareApi.Post("/", sessionOnly, shareService.HandleInvite)
+	shareApi.Post("/:id/accept", sessionOnly, shareService.HandleAccept)
+	shareApi.Put("/:id", sessionOnly, shareService.HandleUpdateRole)
+	shareApi.Delete("/:id", sessionOnly, shareService.HandleRemove)
+
+	reportApi := api.Group("/report", authMiddleware, userRole, middleware.RequireScope(domain.ScopeReportsRead))
 	reportService := handlers.NewReportHandler(rService)
 
 	reportApi.Get("/get", reportService.ExportProfile)
+	reportApi.Get("/export", reportService.ExportRecords)
+	reportApi.Get("/statement", reportService.ExportStatement)
+	reportApi.Get("/tax", reportService.ExportTax)
+
+	scheduleService := handlers.NewReportScheduleHandler(rsService)
+
+	reportApi.Get("/schedules", scheduleService.HandleGetSchedules)
+	reportApi.Post("/schedules", sessionOnly, idempotency, scheduleService.HandleCreateSchedule)
+	reportApi.Put("/schedules/:id", sessionOnly, idempotency, scheduleService.HandleUpdateSchedule)
+	reportApi.Delete("/schedules/:id", sessionOnly, idempotency, scheduleService.HandleDeleteSchedule)
 
-	assetApi := api.Group("/asset", middleware.AuthMiddleware())
+	assetApi := api.Group("/asset", authMiddleware, userRole, readScope)
 	assetService := handlers.NewAssetHandler(aService)
 
 	assetApi.Get("/get-items", assetService.HandleGetAssets)
 	assetApi.Get("/get-item/:ticker", assetService.HandleGetAsset)
 	assetApi.Get("/get-chart/:ticker", assetService.HandleGetAssetChart)
 
-	workerGroup := app.Group("/worker")
-	workerGroup.Get("/update-prices", assetService.HandleUpdateStock)
+	adminApi := api.Group("/admin", authMiddleware, sessionOnly, adminRole, idempotency)
+	jobService := handlers.NewJobHandler(jService)
+	adminService := handlers.NewAdminHandler(adService)
+
+	adminApi.Get("/users", adminService.HandleListUsers)
+	adminApi.Post("/users/:id/lock", adminService.HandleLockUser)
+	adminApi.Post("/users/:id/unlock", adminService.HandleUnlockUser)
+	adminApi.Put("/users/:id/role", adminService.HandleSetRole)
+	adminApi.Get("/health", adminService.HandleHealth)
+	adminApi.Get("/jobs", jobService.HandleGetJobStatus)
+	adminApi.Get("/jobs/runs", jobService.HandleGetJobRuns)
+	adminApi.Post("/jobs/:name/run", jobService.HandleRunJob)
+
+	// Worker Rate Limiter (6 reqs / min), callers must be signed by WORKER_SECRET
+	workerGroup := api.Group("/worker", limiter.New(limiter.Config{
+		Max:        6,
+		Expiration: 1 * time.Minute,
+	}), middleware.WorkerAuthMiddleware(idService))
+	workerService := handlers.NewWorkerHandler(jService, exchange)
+
+	workerGroup.Get("/update-prices", workerService.HandleUpdateStock)
+	workerGroup.Get("/deliver-reports", workerService.HandleDeliverReports)
+	workerGroup.Get("/purge-trash", workerService.HandlePurgeTrash)
+	workerGroup.Get("/purge-idempotency-keys", workerService.HandlePurgeIdempotency)
 
 	return app
 }
@@ -0,0 +1,42 @@
+package domain
+
+import "time"
+
+type AdminUserResponse struct {
+	ID          uint       `json:"id"`
+	Name        string     `json:"name"`
+	Username    string     `json:"username"`
+	Email       string     `json:"email"`
+	Role        string     `json:"role"`
+	Verified    bool       `json:"verified"`
+	TOTPEnabled bool       `json:"totp_enabled"`
+	LockedAt    *time.Time `json:"locked_at"`
+	LockReason  string     `json:"lock_reason"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+type AdminUserListResponse struct {
+	Users []AdminUserResponse `json:"users"`
+	Total int64               `json:"total"`
+	Page  int                 `json:"page"`
+	Limit int                 `json:"limit"`
+}
+
+type LockUserReq struct {
+	Reason string `json:"reason" validate:"max=255"`
+}
+
+type SetRoleReq struct {
+	Role string `json:"role" validate:"required,oneof=user admin"`
+}
+
+type HealthResponse struct {
+	Status              string              `json:"status"`
+	Database            string              `json:"database"`
+	UptimeSeconds       int64               `json:"uptime_seconds"`
+	Goroutines          int                 `json:"goroutines"`
+	MemoryAllocMB       float64             `json:"memory_alloc_mb"`
+	PriceStoreUpdatedAt time.Time           `json:"price_store_updated_at"`
+	Users               int64               `json:"users"`
+	Jobs                []JobStatusResponse `json:"jobs"`
+}
@@ -0,0 +1,45 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/lib/pq"
+)
+
+const (
+	ScopePortfolioRead = "portfolio:read"
+	ScopeTradeWrite    = "trade:write"
+	ScopeReportsRead   = "reports:read"
+
+	// APIKeyPrefix marks personal API keys so AuthMiddleware can tell them apart from JWTs.
+	APIKeyPrefix = "tpt_"
+)
+
+type APIKey struct {
+	BaseModel
+
+	UserID     uint64         `gorm:"not null;index" json:"-"`
+	Name       string         `gorm:"type:varchar(50);not null" json:"name"`
+	Prefix     string         `gorm:"type:varchar(16);not null" json:"prefix"`
+	KeyHash    string         `gorm:"type:varchar(64);not null;uniqueIndex" json:"-"`
+	Scopes     pq.StringArray `gorm:"type:text[];not null" json:"scopes"`
+	ExpiresAt  *time.Time     `json:"expires_at"`
+	LastUsedAt *time.Time     `json:"last_used_at"`
+	LastUsedIP string         `gorm:"type:varchar(64)" json:"last_used_ip"`
+	RevokedAt  *time.Time     `gorm:"index" json:"revoked_at"`
+
+	User User `gorm:"foreignKey:UserID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"-"`
+}
+
+type CreateAPIKeyReq struct {
+	Name   string   `json:"name" validate:"required,max=50"`
+	Scopes []string `json:"scopes" validate:"required,min=1,dive,oneof=portfolio:read trade:write reports:read"`
+	// ExpiresInDays of 0 creates a key that never expires.
+	ExpiresInDays int `json:"expires_in_days" validate:"gte=0,lte=365"`
+}
+
+type CreateAPIKeyResponse struct {
+	APIKey
+	// Key is only returned once, at creation.
+	Key string `json:"key"`
+}
@@ -33,6 +33,13 @@ type AssetChartResponse struct {
 	Chart     []Candle `json:"chart"`
 }
 
+type PriceQuote struct {
+	Price     float64   `json:"price"`
+	Source    string    `json:"source"`
+	UpdatedAt time.Time `json:"updated_at"`
+	Stale     bool      `json:"stale"`
+}
+
 type Candle struct {
 	Time   int64   `json:"time"`
 	Open   float64 `json:"open"`
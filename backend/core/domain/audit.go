@@ -0,0 +1,70 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	AuditCreate  = "create"
+	AuditUpdate  = "update"
+	AuditDelete  = "delete"
+	AuditBuy     = "buy"
+	AuditSell    = "sell"
+	AuditMigrate = "migrate"
+	AuditRestore = "restore"
+	AuditPurge   = "purge"
+
+	AuditEntityBalance     = "balance"
+	AuditEntityPosition    = "position"
+	AuditEntityTransaction = "transaction"
+	AuditEntityNote        = "note"
+	AuditEntityNoteImage   = "note_image"
+	AuditEntityJournal     = "journal"
+)
+
+// Actor identifies who triggers a mutation and the request it came from.
+type Actor struct {
+	UserID    uint64
+	RequestID string
+}
+
+// AuditLog is an append-only record of a mutation. OwnerID is the user whose data changed,
+// which differs from ActorID when a trader member works on a shared account.
+// Both are set to 0 when that user deletes their account.
+type AuditLog struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	CreatedAt time.Time `gorm:"index" json:"created_at"`
+
+	ActorID    uint64 `gorm:"not null;index" json:"actor_id"`
+	OwnerID    uint64 `gorm:"not null;index" json:"owner_id"`
+	RequestID  string `gorm:"type:varchar(64);index" json:"request_id"`
+	Action     string `gorm:"type:varchar(20);not null" json:"action"`
+	EntityType string `gorm:"type:varchar(20);not null;index:idx_audit_entity" json:"entity_type"`
+	EntityID   uint   `gorm:"index:idx_audit_entity" json:"entity_id"`
+	Before     string `gorm:"type:jsonb" json:"-"`
+	After      string `gorm:"type:jsonb" json:"-"`
+}
+
+type AuditLogFilter struct {
+	EntityType string `query:"entity_type"`
+	EntityID   uint   `query:"entity_id"`
+	Action     string `query:"action"`
+	RequestID  string `query:"request_id"`
+	Page       int    `query:"page"`
+	Limit      int    `query:"limit"`
+}
+
+type AuditLogResponse struct {
+	AuditLog
+
+	Before json.RawMessage `json:"before"`
+	After  json.RawMessage `json:"after"`
+}
+
+type AuditLogListResponse struct {
+	Logs  []AuditLogResponse `json:"logs"`
+	Total int64              `json:"total"`
+	Page  int                `json:"page"`
+	Limit int                `json:"limit"`
+}
@@ -38,4 +38,19 @@ type BalanceUpdateReq struct {
 	Date       string  `json:"date"`
 	Provider   string  `json:"provider" validate:"required"`
 	AccountNo  string  `json:"account_no" validate:"required"`
+	// OwnerID targets an account shared with the caller. Empty means the caller's own account.
+	OwnerID uint64 `json:"owner_id"`
+}
+
+// DividendReq records a cash dividend. Amount is the gross dividend and Tax the amount withheld at source;
+// the difference is credited to the account's stock balance.
+type DividendReq struct {
+	Ticker    string  `json:"ticker" validate:"required,lte=12"`
+	Amount    float64 `json:"amount" validate:"required,gt=0"`
+	Tax       float64 `json:"tax" validate:"gte=0,ltefield=Amount"`
+	Date      string  `json:"date"`
+	Note      string  `json:"note" validate:"lte=255"`
+	Provider  string  `json:"provider" validate:"required"`
+	AccountNo string  `json:"account_no" validate:"required"`
+	OwnerID   uint64  `json:"owner_id"`
 }
@@ -14,6 +14,69 @@ var (
 	// Create User
 	ErrAlreadyExist    = errors.New("Email or username has already been taken.")
 	ErrWrongCredential = errors.New("Wrong username or password.")
+	ErrTooManyAttempts = errors.New("Too many failed login attempts. Please try again later.")
+
+	// Account management
+	ErrWrongPassword      = errors.New("Current password is incorrect.")
+	ErrDeleteNotConfirmed = errors.New("Type your username in confirm to delete the account.")
+	ErrPasswordUnchanged  = errors.New("New password must be different from the current one.")
+
+	// Roles
+	ErrAccountLocked  = errors.New("This account has been locked. Please contact support.")
+	ErrForbidden      = errors.New("You are not allowed to do that.")
+	ErrCannotEditSelf = errors.New("Administrators cannot lock or demote themselves.")
+
+	// Session
+	ErrInvalidToken   = errors.New("Invalid or expired token.")
+	ErrSessionRevoked = errors.New("Session has been revoked, please log in again.")
+
+	// Email verification
+	ErrEmailNotVerified   = errors.New("Please verify your email address first.")
+	ErrEmailAlreadyVerify = errors.New("Email address is already verified.")
+
+	// API keys
+	ErrTooManyAPIKeys = errors.New("You have reached the maximum number of API keys.")
+
+	// Two-factor authentication
+	ErrInvalidTwoFactorCode    = errors.New("Invalid authentication code.")
+	ErrTwoFactorAlreadyEnabled = errors.New("Two-factor authentication is already enabled.")
+	ErrTwoFactorNotEnabled     = errors.New("Two-factor authentication is not enabled.")
+	ErrTwoFactorNotSetup       = errors.New("Start two-factor setup before enabling it.")
+
+	// Sharing
+	ErrShareSelf          = errors.New("You cannot share an account with yourself.")
+	ErrShareAccountAbsent = errors.New("You do not own an account with that provider and account number.")
+	ErrAlreadyShared      = errors.New("This account is already shared with that user.")
+
+	// Reports
+	ErrInvalidPeriod      = errors.New("Invalid period. Use YYYY-MM for a month, YYYY for a year, or 'monthly' / 'annual' for the current one.")
+	ErrInvalidDateRange   = errors.New("Invalid date range. Use YYYY-MM-DD and make sure 'from' is not after 'to'.")
+	ErrInvalidAccountList = errors.New("Invalid accounts. Use a comma-separated list of provider or provider:account_no.")
+	ErrUnknownSheet       = errors.New("Unknown sheet. Use transactions, closed_trades, portfolio, financial or balances.")
+	ErrInvalidInterval    = errors.New("Invalid interval. Use 'monthly' or 'annual'.")
+	ErrInvalidTaxYear     = errors.New("Invalid year. Use YYYY for a year that has already started.")
+	ErrTooManySchedules   = errors.New("You have reached the maximum number of report schedules.")
+
+	// Notes
+	ErrInvalidNoteSort = errors.New("Invalid sort. Use updated_at, created_at, title or relevance, and order asc or desc.")
+
+	// Note images
+	ErrImageTooLarge    = errors.New("The image is too large. Upload at most 3 MB and 20 megapixels.")
+	ErrUnsupportedImage = errors.New("Unsupported image. Upload a JPEG, PNG or GIF file.")
+	ErrTooManyImages    = errors.New("A note can have at most 5 uploaded images.")
+	ErrImageNotFound    = errors.New("Image not found.")
+
+	// Journal
+	ErrJournalWithoutTrade = errors.New("Link the journal entry to at least one transaction or position.")
+	ErrJournalTradeAbsent  = errors.New("One or more linked transactions or positions do not exist in your account.")
+	ErrInvalidJournalGroup = errors.New("Invalid grouping. Use 'setup' or 'tag'.")
+
+	// Jobs
+	ErrJobRunning = errors.New("This job is already running.")
+
+	// Idempotency
+	ErrIdempotencyInProgress = errors.New("A request with this Idempotency-Key is still being processed.")
+	ErrIdempotencyMismatch   = errors.New("This Idempotency-Key was already used for a different request.")
 
 	// Add Position
 	ErrMismatchInfo = errors.New("There are some mismatch on the information. (e.g. Owner, quantity, etc.)")
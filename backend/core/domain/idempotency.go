@@ -0,0 +1,30 @@
+package domain
+
+import "time"
+
+// IdempotencyRecord stores the response of a mutating request sent with an Idempotency-Key.
+// StatusCode stays 0 while the first request is still being processed.
+type IdempotencyRecord struct {
+	ID        uint      `gorm:"primaryKey"`
+	CreatedAt time.Time `gorm:"not null"`
+	ExpiresAt time.Time `gorm:"not null;index"`
+
+	UserID      uint64 `gorm:"not null;uniqueIndex:idx_idempotency_user_key"`
+	Key         string `gorm:"type:varchar(255);not null;uniqueIndex:idx_idempotency_user_key"`
+	RequestHash string `gorm:"type:char(64);not null"`
+
+	StatusCode   int    `gorm:"not null;default:0"`
+	ContentType  string `gorm:"type:varchar(100)"`
+	ResponseBody []byte
+
+	User User `gorm:"foreignKey:UserID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+}
+
+// WorkerNonce is an X-Worker-Nonce that has been used. Worker calls can land on any server
+// instance, so used nonces are shared through the database. Rows expire once their signature
+// window has passed.
+type WorkerNonce struct {
+	ID        uint      `gorm:"primaryKey"`
+	Nonce     string    `gorm:"type:varchar(255);not null;uniqueIndex"`
+	ExpiresAt time.Time `gorm:"not null;index"`
+}
@@ -0,0 +1,40 @@
+package domain
+
+import "time"
+
+const (
+	JobStatusRunning = "running"
+	JobStatusSuccess = "success"
+	JobStatusFailed  = "failed"
+	JobStatusSkipped = "skipped"
+)
+
+type JobRun struct {
+	ID         uint       `gorm:"primaryKey" json:"id"`
+	JobName    string     `gorm:"type:varchar(100);not null;index:idx_job_run_name_started" json:"job_name"`
+	Status     string     `gorm:"type:varchar(20);not null" json:"status"`
+	Attempts   int        `gorm:"not null;default:0" json:"attempts"`
+	Processed  int        `gorm:"not null;default:0" json:"processed"`
+	Error      string     `gorm:"type:text" json:"error"`
+	Message    string     `gorm:"type:varchar(255)" json:"message"`
+	StartedAt  time.Time  `gorm:"not null;index:idx_job_run_name_started" json:"started_at"`
+	FinishedAt *time.Time `json:"finished_at"`
+	DurationMs int64      `json:"duration_ms"`
+}
+
+type WorkerRunResponse struct {
+	Status     string    `json:"status"`
+	Updated    int       `json:"updated"`
+	DurationMs int64     `json:"duration_ms"`
+	MarketOpen bool      `json:"market_open"`
+	RanAt      time.Time `json:"ran_at"`
+	Message    string    `json:"message,omitempty"`
+}
+
+type JobStatusResponse struct {
+	Name     string     `json:"name"`
+	Schedule string     `json:"schedule"`
+	Running  bool       `json:"running"`
+	NextRun  *time.Time `json:"next_run"`
+	LastRun  *JobRun    `json:"last_run"`
+}
@@ -0,0 +1,61 @@
+package domain
+
+import "github.com/lib/pq"
+
+const (
+	JournalGroupSetup = "setup"
+	JournalGroupTag   = "tag"
+)
+
+// JournalEntry records the reasoning behind one or more trades. It links to transactions and
+// positions by ID; positions are removed once fully sold, so a position link may outlive it.
+type JournalEntry struct {
+	BaseModel
+
+	UserID         uint64         `gorm:"not null;index" json:"-"`
+	Setup          string         `gorm:"type:varchar(100);not null;default:'';index" json:"setup"`
+	Thesis         string         `gorm:"type:text" json:"thesis"`
+	Emotion        string         `gorm:"type:varchar(50)" json:"emotion"`
+	Mistakes       string         `gorm:"type:text" json:"mistakes"`
+	Rating         int            `gorm:"not null;default:0" json:"rating"`
+	Tags           pq.StringArray `gorm:"type:text[]" json:"tags"`
+	TransactionIDs pq.Int64Array  `gorm:"type:bigint[];index:,type:gin" json:"transaction_ids"`
+	PositionIDs    pq.Int64Array  `gorm:"type:bigint[];index:,type:gin" json:"position_ids"`
+
+	User User `gorm:"foreignKey:UserID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"-"`
+}
+
+type JournalRequest struct {
+	Setup          string   `json:"setup" validate:"lte=100"`
+	Thesis         string   `json:"thesis" validate:"lte=5000"`
+	Emotion        string   `json:"emotion" validate:"lte=50"`
+	Mistakes       string   `json:"mistakes" validate:"lte=5000"`
+	Rating         int      `json:"rating" validate:"gte=0,lte=5"`
+	Tags           []string `json:"tags" validate:"max=10,dive,min=1,max=30"`
+	TransactionIDs []uint   `json:"transaction_ids" validate:"max=50"`
+	PositionIDs    []uint   `json:"position_ids" validate:"max=50"`
+}
+
+type TradeJournalResponse struct {
+	Transaction TransactionResponse `json:"transaction"`
+	Journal     []JournalEntry      `json:"journal"`
+}
+
+type PositionJournalResponse struct {
+	// Position is nil when the position has since been closed.
+	Position *Position      `json:"position"`
+	Journal  []JournalEntry `json:"journal"`
+}
+
+// JournalStat summarizes the realized PnL of the sells linked to entries sharing a setup or tag.
+type JournalStat struct {
+	Key         string  `json:"key"`
+	Entries     int     `json:"entries"`
+	Trades      int     `json:"trades"`
+	Wins        int     `json:"wins"`
+	Losses      int     `json:"losses"`
+	WinRate     float64 `json:"win_rate"`
+	RealizedPnL float64 `json:"realized_pnl"`
+	AvgPnL      float64 `json:"avg_pnl"`
+	AvgRating   float64 `json:"avg_rating"`
+}
@@ -0,0 +1,27 @@
+package domain
+
+import "time"
+
+const (
+	LoginReasonSuccess       = "success"
+	LoginReasonWrongPassword = "wrong_password"
+	LoginReasonWrongCode     = "wrong_two_factor_code"
+	LoginReasonTemporaryLock = "temporarily_locked"
+	LoginReasonAccountLocked = "account_locked"
+)
+
+// LoginEvent is an append-only record of a login attempt on a known account.
+type LoginEvent struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	CreatedAt time.Time `gorm:"index" json:"created_at"`
+
+	UserID    uint64 `gorm:"not null;index" json:"-"`
+	IP        string `gorm:"type:varchar(64)" json:"ip"`
+	UserAgent string `gorm:"type:varchar(255)" json:"user_agent"`
+	Success   bool   `gorm:"not null" json:"success"`
+	Reason    string `gorm:"type:varchar(30);not null" json:"reason"`
+	// NewDevice marks a successful login from an IP and user agent pair not seen before.
+	NewDevice bool `gorm:"not null;default:false" json:"new_device"`
+
+	User User `gorm:"foreignKey:UserID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"-"`
+}
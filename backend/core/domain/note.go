@@ -12,22 +12,93 @@ type Note struct {
 	Title       string         `gorm:"type:varchar(255);not null"`
 	Description string         `gorm:"type:text;not null"`
 	Category    string         `gorm:"type:varchar(100);not null;default:'General';index"`
+	Tags        pq.StringArray `gorm:"type:text[];index:,type:gin"`
 	ImageURL    pq.StringArray `gorm:"type:text[]"`
 	UserID      uint64         `gorm:"not null;index"`
 }
 
+// NoteImage is an image uploaded to a note. The original and its JPEG thumbnail live in
+// object storage under Key and ThumbnailKey.
+type NoteImage struct {
+	BaseModel
+
+	NoteID       uint   `gorm:"not null;index"`
+	UserID       uint64 `gorm:"not null;index"`
+	Key          string `gorm:"type:varchar(100);not null;uniqueIndex"`
+	ThumbnailKey string `gorm:"type:varchar(100);not null"`
+	ContentType  string `gorm:"type:varchar(50);not null"`
+	Size         int64  `gorm:"not null"`
+	Width        int    `gorm:"not null"`
+	Height       int    `gorm:"not null"`
+}
+
+type NoteImageResponse struct {
+	ID           uint   `json:"id"`
+	URL          string `json:"url"`
+	ThumbnailURL string `json:"thumbnail_url"`
+	ContentType  string `json:"content_type"`
+	Size         int64  `json:"size"`
+	Width        int    `json:"width"`
+	Height       int    `json:"height"`
+}
+
 type NoteResponse struct {
 	ID          uint      `json:"id"`
 	Title       string    `json:"title"`
 	Description string    `json:"description"`
 	Category    string    `json:"category"`
+	Tags        []string  `json:"tags"`
 	ImageURL    []string  `json:"image_url"`
+	CreatedAt   time.Time `json:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at"`
+	// DeletedAt is set for notes in the trash.
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+
+	Images []NoteImageResponse `json:"images"`
 }
 
 type NoteRequest struct {
 	Title       string   `json:"title" validate:"required,min=2,max=50"`
 	Description string   `json:"description" validate:"required"`
 	Category    string   `json:"category"`
+	Tags        []string `json:"tags" validate:"max=10,dive,min=1,max=30"`
 	ImageURL    []string `json:"image_url" validate:"max=5,dive,url"`
 }
+
+const (
+	NoteSortUpdated   = "updated_at"
+	NoteSortCreated   = "created_at"
+	NoteSortTitle     = "title"
+	NoteSortRelevance = "relevance"
+)
+
+// NoteFilter is the /notes/get query. Tags is comma-separated and From/To are inclusive YYYY-MM-DD dates.
+type NoteFilter struct {
+	Query    string `query:"q"`
+	Category string `query:"category"`
+	Tags     string `query:"tags"`
+	From     string `query:"from"`
+	To       string `query:"to"`
+	Sort     string `query:"sort"`
+	Order    string `query:"order"`
+	Page     int    `query:"page"`
+	Limit    int    `query:"limit"`
+}
+
+// NoteSearch is a parsed NoteFilter. To is exclusive.
+type NoteSearch struct {
+	Query    string
+	Category string
+	Tags     []string
+	From     time.Time
+	To       time.Time
+	Sort     string
+	Desc     bool
+}
+
+type NoteListResponse struct {
+	Notes []NoteResponse `json:"notes"`
+	Total int64          `json:"total"`
+	Page  int            `json:"page"`
+	Limit int            `json:"limit"`
+}
@@ -28,6 +28,8 @@ type PositionAddReq struct {
 	Notes         string  `json:"notes" validate:"max=255"`
 	Provider      string  `json:"provider" validate:"required"`
 	AccountNo     string  `json:"account_no" validate:"required"`
+	// OwnerID targets an account shared with the caller. Empty means the caller's own account.
+	OwnerID uint64 `json:"owner_id"`
 }
 
 type PortfolioItem struct {
@@ -40,6 +42,9 @@ type PortfolioItem struct {
 	UpdatedAt          time.Time `json:"updated_at"`
 	Provider           string    `json:"provider"`
 	AccountNo          string    `json:"account_no"`
+	PriceSource        string    `json:"price_source"`
+	PriceUpdatedAt     time.Time `json:"price_updated_at"`
+	PriceStale         bool      `json:"price_stale"`
 }
 
 type PortfolioResponse struct {
@@ -0,0 +1,94 @@
+package domain
+
+import "time"
+
+const (
+	PeriodMonthly = "monthly"
+	PeriodAnnual  = "annual"
+)
+
+// ReportPeriod is the half-open range [Start, End) a statement covers.
+type ReportPeriod struct {
+	Kind  string
+	Label string
+	Start time.Time
+	End   time.Time
+}
+
+const (
+	SheetTransactions = "transactions"
+	SheetClosedTrades = "closed_trades"
+	SheetPortfolio    = "portfolio"
+	SheetFinancial    = "financial"
+	SheetBalances     = "balances"
+)
+
+var ReportSheets = []string{SheetTransactions, SheetClosedTrades, SheetPortfolio, SheetFinancial, SheetBalances}
+
+// ReportAccount selects one broker account. An empty AccountNo selects every account of the provider.
+type ReportAccount struct {
+	Provider  string
+	AccountNo string
+}
+
+// ReportFilter scopes an Excel export. Zero From/To leave that side of the range open;
+// empty Accounts and Sheets mean all of them.
+type ReportFilter struct {
+	From     time.Time
+	To       time.Time
+	Accounts []ReportAccount
+	Sheets   []string
+	Interval string
+}
+
+// TaxAccountSummary totals one account's taxable activity in a calendar year.
+type TaxAccountSummary struct {
+	Account       string  `json:"account"`
+	SellProceeds  float64 `json:"sell_proceeds"`
+	CostBasis     float64 `json:"cost_basis"`
+	Fees          float64 `json:"fees"`
+	RealizedGain  float64 `json:"realized_gain"`
+	SaleFinalTax  float64 `json:"sale_final_tax"`
+	DividendGross float64 `json:"dividend_gross"`
+	DividendTax   float64 `json:"dividend_tax"`
+	DividendNet   float64 `json:"dividend_net"`
+}
+
+type TaxSale struct {
+	Date     time.Time `json:"date"`
+	Account  string    `json:"account"`
+	Ticker   string    `json:"ticker"`
+	Quantity float64   `json:"quantity"`
+	Proceeds float64   `json:"proceeds"`
+	Cost     float64   `json:"cost"`
+	Fee      float64   `json:"fee"`
+	Gain     float64   `json:"gain"`
+	// FinalTax is the 0.1% IDX final tax on Proceeds.
+	FinalTax float64 `json:"final_tax"`
+}
+
+type TaxDividend struct {
+	Date    time.Time `json:"date"`
+	Account string    `json:"account"`
+	Ticker  string    `json:"ticker"`
+	Gross   float64   `json:"gross"`
+	Tax     float64   `json:"tax"`
+	Net     float64   `json:"net"`
+}
+
+type TaxHolding struct {
+	Account  string  `json:"account"`
+	Ticker   string  `json:"ticker"`
+	Quantity float64 `json:"quantity"`
+	Cost     float64 `json:"cost"`
+}
+
+// TaxReport is the yearly summary used for the SPT filing. Holdings are valued at cost on 31 December.
+type TaxReport struct {
+	Year      int                 `json:"year"`
+	Accounts  []TaxAccountSummary `json:"accounts"`
+	Total     TaxAccountSummary   `json:"total"`
+	Sales     []TaxSale           `json:"sales"`
+	Dividends []TaxDividend       `json:"dividends"`
+	Holdings  []TaxHolding        `json:"holdings"`
+}
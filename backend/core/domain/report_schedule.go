@@ -0,0 +1,35 @@
+package domain
+
+import "time"
+
+const PeriodWeekly = "weekly"
+
+// ReportSchedule emails a report to its owner every week or month. Weekly runs go out on
+// Monday and cover the previous Monday to Sunday; monthly runs cover the previous calendar month.
+type ReportSchedule struct {
+	BaseModel
+
+	UserID    uint64 `gorm:"not null;index" json:"-"`
+	Frequency string `gorm:"type:varchar(10);not null" json:"frequency"`
+	Format    string `gorm:"type:varchar(10);not null" json:"format"`
+	// Accounts and Sheets use the same syntax as the /report/get query parameters.
+	Accounts   string     `gorm:"type:varchar(255)" json:"accounts"`
+	Sheets     string     `gorm:"type:varchar(100)" json:"sheets"`
+	Active     bool       `gorm:"not null" json:"active"`
+	NextRunAt  time.Time  `gorm:"not null;index" json:"next_run_at"`
+	LastSentAt *time.Time `json:"last_sent_at"`
+	LastError  string     `gorm:"type:text" json:"last_error"`
+	// Failures counts failed deliveries of the current period; see DeliverDue.
+	Failures int `gorm:"not null;default:0" json:"failures"`
+
+	User User `gorm:"foreignKey:UserID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"-"`
+}
+
+type ReportScheduleReq struct {
+	Frequency string `json:"frequency" validate:"required,oneof=weekly monthly"`
+	Format    string `json:"format" validate:"required,oneof=pdf xlsx csv jsonl ofx"`
+	Accounts  string `json:"accounts" validate:"lte=255"`
+	Sheets    string `json:"sheets" validate:"lte=100"`
+	// Active defaults to true on create and keeps its value on update when omitted.
+	Active *bool `json:"active"`
+}
@@ -0,0 +1,40 @@
+package domain
+
+import "time"
+
+type Session struct {
+	BaseModel
+
+	UserID            uint64     `gorm:"not null;index" json:"user_id"`
+	RefreshTokenHash  string     `gorm:"type:varchar(64);not null;uniqueIndex" json:"-"`
+	PreviousTokenHash string     `gorm:"type:varchar(64);index" json:"-"`
+	UserAgent         string     `gorm:"type:varchar(255)" json:"user_agent"`
+	IP                string     `gorm:"type:varchar(64)" json:"ip"`
+	ExpiresAt         time.Time  `gorm:"not null" json:"expires_at"`
+	LastUsedAt        time.Time  `gorm:"not null" json:"last_used_at"`
+	RevokedAt         *time.Time `gorm:"index" json:"revoked_at"`
+
+	User User `gorm:"foreignKey:UserID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"-"`
+}
+
+type AuthTokens struct {
+	SessionID        uint      `json:"-"`
+	AccessToken      string    `json:"token"`
+	AccessExpiresAt  time.Time `json:"token_expires_at"`
+	RefreshToken     string    `json:"refresh_token"`
+	RefreshExpiresAt time.Time `json:"refresh_token_expires_at"`
+}
+
+type SessionResponse struct {
+	ID         uint      `json:"id"`
+	UserAgent  string    `json:"user_agent"`
+	IP         string    `json:"ip"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastUsedAt time.Time `json:"last_used_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	Current    bool      `json:"current"`
+}
+
+type RefreshTokenReq struct {
+	RefreshToken string `json:"refresh_token"`
+}
@@ -0,0 +1,61 @@
+package domain
+
+import "time"
+
+const (
+	MemberRoleViewer = "viewer"
+	MemberRoleTrader = "trader"
+)
+
+// AccountMember grants another user access to one provider account of the owner.
+// The invitation is pending until the member accepts it.
+type AccountMember struct {
+	BaseModel
+
+	OwnerID    uint64     `gorm:"not null;uniqueIndex:idx_account_member" json:"owner_id"`
+	MemberID   uint64     `gorm:"not null;uniqueIndex:idx_account_member;index" json:"member_id"`
+	Provider   string     `gorm:"type:varchar(50);not null;uniqueIndex:idx_account_member" json:"provider"`
+	AccountNo  string     `gorm:"type:varchar(50);not null;uniqueIndex:idx_account_member" json:"account_no"`
+	Role       string     `gorm:"type:varchar(10);not null;default:'viewer'" json:"role"`
+	AcceptedAt *time.Time `json:"accepted_at"`
+
+	Owner  User `gorm:"foreignKey:OwnerID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"-"`
+	Member User `gorm:"foreignKey:MemberID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"-"`
+}
+
+type ShareInviteReq struct {
+	// Identifier is the username or email of the invited user.
+	Identifier string `json:"identifier" validate:"required"`
+	Provider   string `json:"provider" validate:"required"`
+	AccountNo  string `json:"account_no" validate:"required"`
+	Role       string `json:"role" validate:"required,oneof=viewer trader"`
+}
+
+type ShareRoleReq struct {
+	Role string `json:"role" validate:"required,oneof=viewer trader"`
+}
+
+type ShareResponse struct {
+	ID            uint       `json:"id"`
+	OwnerID       uint64     `json:"owner_id"`
+	OwnerUsername string     `json:"owner_username"`
+	MemberID      uint64     `json:"member_id"`
+	MemberName    string     `json:"member_username"`
+	Provider      string     `json:"provider"`
+	AccountNo     string     `json:"account_no"`
+	Role          string     `json:"role"`
+	AcceptedAt    *time.Time `json:"accepted_at"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+type ShareListResponse struct {
+	Owned  []ShareResponse `json:"owned"`
+	Shared []ShareResponse `json:"shared_with_me"`
+}
+
+type SharedAccountResponse struct {
+	Share        ShareResponse         `json:"share"`
+	Balances     []Balance             `json:"balances"`
+	Positions    []Position            `json:"positions"`
+	Transactions []TransactionResponse `json:"transactions"`
+}
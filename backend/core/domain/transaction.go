@@ -1,5 +1,7 @@
 package domain
 
+import "time"
+
 type Transaction struct {
 	BaseModel
 	OwnerID         uint64  `gorm:"not null;index" json:"owner_id"`
@@ -13,6 +15,10 @@ type Transaction struct {
 	Notes           string  `gorm:"type:text" json:"notes"`
 	Provider        string  `gorm:"type:varchar(50);index:idx_provider_account" json:"provider"`
 	AccountNo       string  `gorm:"type:varchar(50);index:idx_provider_account" json:"account_no"`
+	// AssetType and BalanceChange record which balance the transaction moved and by how much.
+	// Rows written before they existed leave them empty; see balanceEffect.
+	AssetType     string  `gorm:"type:varchar(20)" json:"asset_type"`
+	BalanceChange float64 `gorm:"not null;default:0" json:"balance_change"`
 }
 
 type TransactionResponse struct {
@@ -21,6 +27,8 @@ type TransactionResponse struct {
 	RealizedPnl    float64 `json:"realized_pnl"`
 	EntryPriceUnit float64 `json:"entry_price_unit"`
 	SellPriceUnit  float64 `json:"sell_price_unit"`
+	// DeletedAt is set for transactions in the trash.
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
 }
 
 type TransactionUpdateReq struct {
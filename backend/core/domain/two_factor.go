@@ -0,0 +1,33 @@
+package domain
+
+import "time"
+
+type RecoveryCode struct {
+	BaseModel
+
+	UserID   uint64     `gorm:"not null;index" json:"-"`
+	CodeHash string     `gorm:"type:varchar(64);not null;uniqueIndex" json:"-"`
+	UsedAt   *time.Time `json:"used_at"`
+
+	User User `gorm:"foreignKey:UserID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"-"`
+}
+
+type TwoFactorSetupResponse struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioning_uri"`
+}
+
+type TwoFactorCodeReq struct {
+	Code string `json:"code" validate:"required"`
+}
+
+type TwoFactorDisableReq struct {
+	Password string `json:"password" validate:"required"`
+	Code     string `json:"code" validate:"required"`
+}
+
+type TwoFactorLoginReq struct {
+	MFAToken string `json:"mfa_token" validate:"required"`
+	// Code is either a current TOTP code or an unused recovery code.
+	Code string `json:"code" validate:"required"`
+}
@@ -1,5 +1,12 @@
 package domain
 
+import "time"
+
+const (
+	RoleUser  = "user"
+	RoleAdmin = "admin"
+)
+
 type User struct {
 	BaseModel
 
@@ -9,6 +16,17 @@ type User struct {
 	Password string `gorm:"not null"`
 	Verified bool   `gorm:"not null;default:false" json:"verified"`
 
+	Role       string     `gorm:"type:varchar(20);not null;default:'user';index" json:"role"`
+	LockedAt   *time.Time `json:"locked_at"`
+	LockReason string     `gorm:"type:varchar(255)" json:"lock_reason"`
+
+	FailedLogins int        `gorm:"not null;default:0" json:"-"`
+	LockedUntil  *time.Time `json:"-"`
+
+	TOTPSecret   string `gorm:"type:varchar(64)" json:"-"`
+	TOTPEnabled  bool   `gorm:"not null;default:false" json:"totp_enabled"`
+	TOTPLastStep int64  `gorm:"not null;default:0" json:"-"`
+
 	Balances []Balance  `gorm:"foreignKey:UserID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"balances"`
 	Notes    []Note     `gorm:"foreignKey:UserID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"notes"`
 	Position []Position `gorm:"foreignKey:OwnerID;reference:ID;not null;default:0" json:"positions"`
@@ -26,9 +44,66 @@ type UserLoginReq struct {
 	Password   string `json:"password" validate:"required"`
 }
 
+type VerifyEmailReq struct {
+	Token string `json:"token" validate:"required"`
+}
+
+type ForgotPasswordReq struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+type ResetPasswordReq struct {
+	Token    string `json:"token" validate:"required"`
+	Password string `json:"password" validate:"required,gte=8"`
+}
+
+type UpdateProfileReq struct {
+	Name     string `json:"name" validate:"omitempty,min=3"`
+	Username string `json:"username" validate:"omitempty,alphanum,min=4"`
+	Email    string `json:"email" validate:"omitempty,email"`
+	// CurrentPassword is required when the username or email changes.
+	CurrentPassword string `json:"current_password"`
+}
+
+type ChangePasswordReq struct {
+	CurrentPassword string `json:"current_password" validate:"required"`
+	NewPassword     string `json:"new_password" validate:"required,gte=8"`
+}
+
+type DeleteAccountReq struct {
+	Password string `json:"password" validate:"required"`
+	// Code is required when two-factor authentication is enabled.
+	Code string `json:"code"`
+	// Confirm must repeat the username.
+	Confirm string `json:"confirm" validate:"required"`
+}
+
+type AccountExport struct {
+	ExportedAt   time.Time             `json:"exported_at"`
+	Profile      AccountExportProfile  `json:"profile"`
+	Accounts     []AccountResponse     `json:"accounts"`
+	Positions    []Position            `json:"positions"`
+	Transactions []TransactionResponse `json:"transactions"`
+	Notes        []NoteResponse        `json:"notes"`
+}
+
+type AccountExportProfile struct {
+	ID          uint      `json:"id"`
+	Name        string    `json:"name"`
+	Username    string    `json:"username"`
+	Email       string    `json:"email"`
+	Verified    bool      `json:"verified"`
+	TOTPEnabled bool      `json:"totp_enabled"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
 type UserProfileResponse struct {
 	Name        string             `json:"name"`
 	Username    string             `json:"username"`
+	Email       string             `json:"email"`
+	Verified    bool               `json:"verified"`
+	TOTPEnabled bool               `json:"totp_enabled"`
+	Role        string             `json:"role"`
 	Balance     BalanceDetail      `json:"balance"`
 	TotalEquity float64            `json:"total_equity"`
 	Portfolio   *PortfolioResponse `json:"positions"`
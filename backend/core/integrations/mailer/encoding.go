@@ -0,0 +1,20 @@
+package mailer
+
+import (
+	"encoding/base64"
+	"strings"
+)
+
+// encodeBase64Lines wraps base64 output at 76 characters as required by RFC 2045.
+func encodeBase64Lines(data []byte) string {
+	encoded := base64.StdEncoding.EncodeToString(data)
+
+	var b strings.Builder
+	for len(encoded) > 76 {
+		b.WriteString(encoded[:76])
+		b.WriteString("\r\n")
+		encoded = encoded[76:]
+	}
+	b.WriteString(encoded)
+	return b.String()
+}
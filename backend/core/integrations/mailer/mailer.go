@@ -0,0 +1,130 @@
+package mailer
+
+import (
+	"fmt"
+	"net/smtp"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+	// Attachments are optional files, keyed by file name.
+	Attachments map[string][]byte
+}
+
+type Mailer interface {
+	Send(msg Message) error
+}
+
+// NewMailer returns an SMTP mailer when SMTP_HOST is set and a file mailer otherwise.
+func NewMailer() Mailer {
+	from := os.Getenv("MAIL_FROM")
+	if from == "" {
+		from = "Trade Tracker <no-reply@trade-tracker.local>"
+	}
+
+	if host := os.Getenv("SMTP_HOST"); host != "" {
+		port := os.Getenv("SMTP_PORT")
+		if port == "" {
+			port = "587"
+		}
+		return NewSMTPMailer(host, port, os.Getenv("SMTP_USERNAME"), os.Getenv("SMTP_PASSWORD"), from)
+	}
+
+	dir := os.Getenv("MAIL_DIR")
+	if dir == "" {
+		dir = "mail"
+	}
+	return NewFileMailer(dir, from)
+}
+
+func buildMIME(from string, msg Message) []byte {
+	var b strings.Builder
+	boundary := fmt.Sprintf("tpt-%d", time.Now().UnixNano())
+
+	fmt.Fprintf(&b, "From: %s\r\n", from)
+	fmt.Fprintf(&b, "To: %s\r\n", msg.To)
+	fmt.Fprintf(&b, "Subject: %s\r\n", msg.Subject)
+	fmt.Fprintf(&b, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+
+	if len(msg.Attachments) == 0 {
+		b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n\r\n")
+		b.WriteString(msg.Body)
+		return []byte(b.String())
+	}
+
+	fmt.Fprintf(&b, "Content-Type: multipart/mixed; boundary=%s\r\n\r\n", boundary)
+	fmt.Fprintf(&b, "--%s\r\nContent-Type: text/plain; charset=UTF-8\r\n\r\n%s\r\n", boundary, msg.Body)
+	for name, data := range msg.Attachments {
+		fmt.Fprintf(&b, "--%s\r\n", boundary)
+		b.WriteString("Content-Type: application/octet-stream\r\n")
+		b.WriteString("Content-Transfer-Encoding: base64\r\n")
+		fmt.Fprintf(&b, "Content-Disposition: attachment; filename=%q\r\n\r\n", name)
+		b.WriteString(encodeBase64Lines(data))
+		b.WriteString("\r\n")
+	}
+	fmt.Fprintf(&b, "--%s--\r\n", boundary)
+
+	return []byte(b.String())
+}
+
+type smtpMailer struct {
+	addr string
+	auth smtp.Auth
+	from string
+}
+
+func NewSMTPMailer(host, port, username, password, from string) Mailer {
+	var auth smtp.Auth
+	if username != "" {
+		auth = smtp.PlainAuth("", username, password, host)
+	}
+	return &smtpMailer{addr: host + ":" + port, auth: auth, from: from}
+}
+
+func (m *smtpMailer) Send(msg Message) error {
+	return smtp.SendMail(m.addr, m.auth, envelopeAddress(m.from), []string{msg.To}, buildMIME(m.from, msg))
+}
+
+// fileMailer writes every message as an .eml file, a stand-in for local development.
+type fileMailer struct {
+	dir  string
+	from string
+}
+
+func NewFileMailer(dir, from string) Mailer {
+	return &fileMailer{dir: dir, from: from}
+}
+
+func (m *fileMailer) Send(msg Message) error {
+	if err := os.MkdirAll(m.dir, 0o755); err != nil {
+		return err
+	}
+
+	name := fmt.Sprintf("%s_%s.eml", time.Now().Format("20060102-150405.000000"), sanitizeFileName(msg.To))
+	return os.WriteFile(filepath.Join(m.dir, name), buildMIME(m.from, msg), 0o644)
+}
+
+func envelopeAddress(from string) string {
+	if start := strings.Index(from, "<"); start >= 0 {
+		if end := strings.Index(from[start:], ">"); end > 0 {
+			return from[start+1 : start+end]
+		}
+	}
+	return from
+}
+
+func sanitizeFileName(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '@' || r == '.' || r == '-' || r == '_' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			return r
+		}
+		return '_'
+	}, s)
+}
@@ -0,0 +1,144 @@
+package providers
+
+import (
+	"sync"
+	"time"
+
+	"trade-tracker/core/domain"
+	"trade-tracker/pkg/utils/calendar"
+
+	"golang.org/x/sync/singleflight"
+)
+
+const (
+	openMarketPriceTTL   = 1 * time.Minute
+	closedMarketPriceTTL = 12 * time.Hour
+	maxConcurrentFetches = 8
+)
+
+type cachedPriceProvider struct {
+	inner    PriceProvider
+	exchange *calendar.Exchange
+
+	mu     sync.RWMutex
+	quotes map[string]domain.PriceQuote
+	group  singleflight.Group
+	sem    chan struct{}
+}
+
+// NewCachedPriceProvider wraps a PriceProvider with a TTL cache. Concurrent lookups
+// of the same ticker share one upstream call, and upstream calls are bounded.
+func NewCachedPriceProvider(inner PriceProvider, exchange *calendar.Exchange) PriceProvider {
+	return &cachedPriceProvider{
+		inner:    inner,
+		exchange: exchange,
+		quotes:   make(map[string]domain.PriceQuote),
+		sem:      make(chan struct{}, maxConcurrentFetches),
+	}
+}
+
+// isFresh keeps prices for a short time while the market trades. A price fetched
+// after the last close stays valid until the market opens again (capped by closedMarketPriceTTL).
+func (s *cachedPriceProvider) isFresh(quote domain.PriceQuote, now time.Time) bool {
+	age := now.Sub(quote.UpdatedAt)
+	if s.exchange.IsOpen(now) {
+		return age < openMarketPriceTTL
+	}
+	return age < closedMarketPriceTTL && !quote.UpdatedAt.Before(s.exchange.PreviousClose(now))
+}
+
+func (s *cachedPriceProvider) lookup(ticker string) (domain.PriceQuote, bool) {
+	s.mu.RLock()
+	quote, found := s.quotes[ticker]
+	s.mu.RUnlock()
+
+	if !found || !s.isFresh(quote, time.Now()) {
+		return domain.PriceQuote{}, false
+	}
+	return quote, true
+}
+
+func (s *cachedPriceProvider) GetChart(ticker string, timeframe string) (*domain.AssetChartResponse, error) {
+	return s.inner.GetChart(ticker, timeframe)
+}
+
+func (s *cachedPriceProvider) GetQuote(ticker string) (domain.PriceQuote, error) {
+	if quote, ok := s.lookup(ticker); ok {
+		return quote, nil
+	}
+
+	v, err, _ := s.group.Do(ticker, func() (interface{}, error) {
+		if quote, ok := s.lookup(ticker); ok { // filled by a call that finished just before us
+			return quote, nil
+		}
+
+		s.sem <- struct{}{}
+		defer func() { <-s.sem }()
+
+		quote, err := s.inner.GetQuote(ticker)
+		if err != nil {
+			return domain.PriceQuote{}, err
+		}
+
+		s.mu.Lock()
+		s.quotes[ticker] = quote
+		s.mu.Unlock()
+
+		return quote, nil
+	})
+	if err != nil {
+		return domain.PriceQuote{}, err
+	}
+
+	return v.(domain.PriceQuote), nil
+}
+
+func (s *cachedPriceProvider) GetBatchQuotes(tickers []string) (map[string]domain.PriceQuote, error) {
+	result := make(map[string]domain.PriceQuote)
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+
+	seen := make(map[string]bool)
+	for _, t := range tickers {
+		if seen[t] {
+			continue
+		}
+		seen[t] = true
+
+		wg.Add(1)
+		go func(ticker string) {
+			defer wg.Done()
+			quote, err := s.GetQuote(ticker)
+			if err != nil {
+				return
+			}
+			mu.Lock()
+			result[ticker] = quote
+			mu.Unlock()
+		}(t)
+	}
+
+	wg.Wait()
+	return result, nil
+}
+
+func (s *cachedPriceProvider) GetCurrentPrice(ticker string) (float64, error) {
+	quote, err := s.GetQuote(ticker)
+	if err != nil {
+		return 0, err
+	}
+	return quote.Price, nil
+}
+
+func (s *cachedPriceProvider) GetBatchPrices(tickers []string) (map[string]float64, error) {
+	quotes, err := s.GetBatchQuotes(tickers)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]float64, len(quotes))
+	for ticker, quote := range quotes {
+		result[ticker] = quote.Price
+	}
+	return result, nil
+}
@@ -0,0 +1,138 @@
+package providers
+
+import (
+	"os"
+	"time"
+
+	"trade-tracker/core/domain"
+	"trade-tracker/pkg/utils/calendar"
+
+	"github.com/VYDev37/go-tvscanner-api/pkg/scanner"
+)
+
+// The store is refreshed every 15 minutes by the GitHub Actions cron in production
+// (every 5 minutes by the in-process scheduler elsewhere), so the default leaves
+// room for a late run before quotes are treated as stale.
+const defaultStoreStaleAfter = 25 * time.Minute
+
+// storeStaleAfter is how old the store may get before the fallback is asked, set by PRICE_STALE_AFTER.
+func storeStaleAfter() time.Duration {
+	if d, err := time.ParseDuration(os.Getenv("PRICE_STALE_AFTER")); err == nil && d > 0 {
+		return d
+	}
+	return defaultStoreStaleAfter
+}
+
+type storePriceProvider struct {
+	fallback   PriceProvider
+	exchange   *calendar.Exchange
+	staleAfter time.Duration
+}
+
+// NewStorePriceProvider serves IDX prices from scanner.GlobalStore and asks the
+// fallback provider for tickers the store does not have or has only stale data for.
+func NewStorePriceProvider(fallback PriceProvider, exchange *calendar.Exchange) PriceProvider {
+	return &storePriceProvider{fallback: fallback, exchange: exchange, staleAfter: storeStaleAfter()}
+}
+
+func (s *storePriceProvider) isStale(updatedAt time.Time, now time.Time) bool {
+	if updatedAt.IsZero() {
+		return true
+	}
+	if s.exchange.IsOpen(now) {
+		return now.Sub(updatedAt) > s.staleAfter
+	}
+	// the last refresh of a session happens shortly before its close
+	return updatedAt.Before(s.exchange.PreviousClose(now).Add(-s.staleAfter))
+}
+
+func (s *storePriceProvider) lookup(tickers []string) map[string]domain.PriceQuote {
+	updatedAt := StoreUpdatedAt()
+	stale := s.isStale(updatedAt, time.Now())
+
+	result := make(map[string]domain.PriceQuote, len(tickers))
+
+	store := scanner.GlobalStore
+	store.RLock()
+	for _, t := range tickers {
+		asset, found := store.Index[t]
+		if !found || asset.Price <= 0 {
+			continue
+		}
+		result[t] = domain.PriceQuote{
+			Price:     float64(asset.Price),
+			Source:    "tvscanner",
+			UpdatedAt: updatedAt,
+			Stale:     stale,
+		}
+	}
+	store.RUnlock()
+
+	return result
+}
+
+func (s *storePriceProvider) GetChart(ticker string, timeframe string) (*domain.AssetChartResponse, error) {
+	return s.fallback.GetChart(ticker, timeframe)
+}
+
+func (s *storePriceProvider) GetQuote(ticker string) (domain.PriceQuote, error) {
+	stored, found := s.lookup([]string{ticker})[ticker]
+	if found && !stored.Stale {
+		return stored, nil
+	}
+
+	quote, err := s.fallback.GetQuote(ticker)
+	if err != nil {
+		if found {
+			return stored, nil
+		}
+		return domain.PriceQuote{}, err
+	}
+	return quote, nil
+}
+
+func (s *storePriceProvider) GetBatchQuotes(tickers []string) (map[string]domain.PriceQuote, error) {
+	result := s.lookup(tickers)
+
+	var missing []string
+	for _, t := range tickers {
+		if quote, found := result[t]; !found || quote.Stale {
+			missing = append(missing, t)
+		}
+	}
+
+	if len(missing) == 0 {
+		return result, nil
+	}
+
+	fetched, err := s.fallback.GetBatchQuotes(missing)
+	if err != nil {
+		return result, nil
+	}
+	for ticker, quote := range fetched {
+		result[ticker] = quote
+	}
+
+	return result, nil
+}
+
+func (s *storePriceProvider) GetCurrentPrice(ticker string) (float64, error) {
+	quote, err := s.GetQuote(ticker)
+	if err != nil {
+		return 0, err
+	}
+	return quote.Price, nil
+}
+
+func (s *storePriceProvider) GetBatchPrices(tickers []string) (map[string]float64, error) {
+	quotes, err := s.GetBatchQuotes(tickers)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]float64, len(quotes))
+	for ticker, quote := range quotes {
+		result[ticker] = quote.Price
+	}
+	return result, nil
+}
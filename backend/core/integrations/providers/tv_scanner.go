@@ -1,6 +1,27 @@
 package providers
 
-import "github.com/VYDev37/go-tvscanner-api/pkg/scanner"
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/VYDev37/go-tvscanner-api/pkg/scanner"
+)
+
+// storeUpdatedAt holds the unix nano time of the last scanner.GlobalStore refresh.
+var storeUpdatedAt atomic.Int64
+
+// MarkStoreUpdated is called by the worker after every successful GlobalStore refresh.
+func MarkStoreUpdated(t time.Time) {
+	storeUpdatedAt.Store(t.UnixNano())
+}
+
+func StoreUpdatedAt() time.Time {
+	ts := storeUpdatedAt.Load()
+	if ts == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, ts)
+}
 
 type AssetProvider interface {
 	GetAssets() []scanner.M
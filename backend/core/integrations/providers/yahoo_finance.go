@@ -41,6 +41,8 @@ type PriceProvider interface {
 	GetChart(ticker string, timeframe string) (*domain.AssetChartResponse, error)
 	GetCurrentPrice(ticker string) (float64, error)
 	GetBatchPrices(tickers []string) (map[string]float64, error)
+	GetQuote(ticker string) (domain.PriceQuote, error)
+	GetBatchQuotes(tickers []string) (map[string]domain.PriceQuote, error)
 }
 
 type priceProvider struct {
@@ -250,3 +252,25 @@ func (s *priceProvider) GetBatchPrices(tickers []string) (map[string]float64, er
 
 	return result, nil
 }
+
+func (s *priceProvider) GetQuote(ticker string) (domain.PriceQuote, error) {
+	price, err := s.GetCurrentPrice(ticker)
+	if err != nil {
+		return domain.PriceQuote{}, err
+	}
+	return domain.PriceQuote{Price: price, Source: "yahoo", UpdatedAt: time.Now()}, nil
+}
+
+func (s *priceProvider) GetBatchQuotes(tickers []string) (map[string]domain.PriceQuote, error) {
+	prices, err := s.GetBatchPrices(tickers)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	result := make(map[string]domain.PriceQuote, len(prices))
+	for ticker, price := range prices {
+		result[ticker] = domain.PriceQuote{Price: price, Source: "yahoo", UpdatedAt: now}
+	}
+	return result, nil
+}
@@ -0,0 +1,63 @@
+package storage
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+type localStorage struct {
+	dir       string
+	publicURL string
+}
+
+func NewLocalStorage(dir, publicURL string) Storage {
+	return &localStorage{dir: dir, publicURL: publicURL}
+}
+
+func (s *localStorage) path(key string) string {
+	return filepath.Join(s.dir, filepath.Base(key))
+}
+
+func (s *localStorage) Put(key string, data []byte, contentType string) error {
+	if err := os.MkdirAll(s.dir, 0o755); err != nil {
+		return err
+	}
+
+	// Write to a temporary file first so readers never see a partial object.
+	tmp, err := os.CreateTemp(s.dir, ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), s.path(key))
+}
+
+func (s *localStorage) Get(key string) ([]byte, error) {
+	data, err := os.ReadFile(s.path(key))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, ErrObjectNotFound
+	}
+	return data, err
+}
+
+func (s *localStorage) Delete(key string) error {
+	err := os.Remove(s.path(key))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+func (s *localStorage) URL(key string) string {
+	return s.publicURL + "/" + key
+}
@@ -0,0 +1,42 @@
+package storage
+
+import (
+	"errors"
+	"os"
+	"strings"
+)
+
+var ErrObjectNotFound = errors.New("object not found")
+
+// Storage keeps uploaded files under flat keys. Delete ignores missing objects.
+type Storage interface {
+	Put(key string, data []byte, contentType string) error
+	Get(key string) ([]byte, error)
+	Delete(key string) error
+	// URL is where clients fetch the object.
+	URL(key string) string
+}
+
+// NewStorage returns an S3-compatible storage when S3_BUCKET is set and a local directory otherwise.
+// Objects are served from UPLOAD_PUBLIC_URL, which defaults to the API's own uploads route
+// under API_GROUP_NAME (e.g. /api/uploads).
+func NewStorage() Storage {
+	publicURL := strings.TrimRight(os.Getenv("UPLOAD_PUBLIC_URL"), "/")
+	if publicURL == "" {
+		publicURL = strings.TrimRight(os.Getenv("API_GROUP_NAME"), "/") + "/uploads"
+	}
+
+	if bucket := os.Getenv("S3_BUCKET"); bucket != "" {
+		region := os.Getenv("S3_REGION")
+		if region == "" {
+			region = "us-east-1"
+		}
+		return NewS3Storage(os.Getenv("S3_ENDPOINT"), region, bucket, os.Getenv("S3_ACCESS_KEY"), os.Getenv("S3_SECRET_KEY"), publicURL)
+	}
+
+	dir := os.Getenv("UPLOAD_DIR")
+	if dir == "" {
+		dir = "uploads"
+	}
+	return NewLocalStorage(dir, publicURL)
+}
@@ -0,0 +1,76 @@
+package repositories
+
+import (
+	"time"
+	"trade-tracker/core/domain"
+
+	"gorm.io/gorm"
+)
+
+type APIKeyRepository interface {
+	CreateKey(key *domain.APIKey, trx *gorm.DB) error
+	RevokeKey(keyID uint, userID uint64, trx *gorm.DB) error
+	TouchKey(keyID uint, ip string, usedAt time.Time) error
+
+	GetKeyByHash(hash string) (*domain.APIKey, error)
+	GetUserKeys(userID uint64) ([]domain.APIKey, error)
+	GetDB() *gorm.DB
+}
+
+type apiKeyRepo struct {
+	DB *gorm.DB
+}
+
+func NewAPIKeyRepo(DB *gorm.DB) APIKeyRepository {
+	return &apiKeyRepo{DB: DB}
+}
+
+func (r *apiKeyRepo) CreateKey(key *domain.APIKey, trx *gorm.DB) error {
+	db := r.DB
+	if trx != nil {
+		db = trx
+	}
+	return db.Create(key).Error
+}
+
+func (r *apiKeyRepo) RevokeKey(keyID uint, userID uint64, trx *gorm.DB) error {
+	db := r.DB
+	if trx != nil {
+		db = trx
+	}
+
+	result := db.Model(&domain.APIKey{}).
+		Where("id = ? AND user_id = ? AND revoked_at IS NULL", keyID, userID).
+		Update("revoked_at", time.Now())
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrItemNotFound
+	}
+	return nil
+}
+
+func (r *apiKeyRepo) TouchKey(keyID uint, ip string, usedAt time.Time) error {
+	return r.DB.Model(&domain.APIKey{}).
+		Where("id = ?", keyID).
+		Updates(map[string]interface{}{"last_used_at": usedAt, "last_used_ip": ip}).Error
+}
+
+func (r *apiKeyRepo) GetKeyByHash(hash string) (*domain.APIKey, error) {
+	var key domain.APIKey
+	if err := r.DB.Where("key_hash = ?", hash).Take(&key).Error; err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+func (r *apiKeyRepo) GetUserKeys(userID uint64) ([]domain.APIKey, error) {
+	var keys []domain.APIKey
+	err := r.DB.Where("user_id = ? AND revoked_at IS NULL", userID).Order("created_at DESC").Find(&keys).Error
+	return keys, err
+}
+
+func (r *apiKeyRepo) GetDB() *gorm.DB {
+	return r.DB
+}
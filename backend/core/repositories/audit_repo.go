@@ -0,0 +1,55 @@
+package repositories
+
+import (
+	"trade-tracker/core/domain"
+
+	"gorm.io/gorm"
+)
+
+// AuditRepository only appends and reads; audit rows are never updated.
+type AuditRepository interface {
+	CreateLog(entry *domain.AuditLog, trx *gorm.DB) error
+	GetLogs(userID uint64, filter domain.AuditLogFilter, offset, limit int) ([]domain.AuditLog, int64, error)
+}
+
+type auditRepo struct {
+	DB *gorm.DB
+}
+
+func NewAuditRepo(DB *gorm.DB) AuditRepository {
+	return &auditRepo{DB: DB}
+}
+
+func (r *auditRepo) CreateLog(entry *domain.AuditLog, trx *gorm.DB) error {
+	db := r.DB
+	if trx != nil {
+		db = trx
+	}
+	return db.Create(entry).Error
+}
+
+// GetLogs returns entries on the user's data and entries the user made on shared accounts.
+func (r *auditRepo) GetLogs(userID uint64, filter domain.AuditLogFilter, offset, limit int) ([]domain.AuditLog, int64, error) {
+	query := r.DB.Model(&domain.AuditLog{}).Where("owner_id = ? OR actor_id = ?", userID, userID)
+	if filter.EntityType != "" {
+		query = query.Where("entity_type = ?", filter.EntityType)
+	}
+	if filter.EntityID != 0 {
+		query = query.Where("entity_id = ?", filter.EntityID)
+	}
+	if filter.Action != "" {
+		query = query.Where("action = ?", filter.Action)
+	}
+	if filter.RequestID != "" {
+		query = query.Where("request_id = ?", filter.RequestID)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var logs []domain.AuditLog
+	err := query.Order("id DESC").Offset(offset).Limit(limit).Find(&logs).Error
+	return logs, total, err
+}
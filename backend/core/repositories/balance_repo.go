@@ -16,6 +16,7 @@ type BalanceRepository interface {
 	GetBalance(balanceID uint64, trx *gorm.DB) (*domain.Balance, error)
 	GetProviderAccount(userID uint64, assetType, provider, accountNo string, trx *gorm.DB) (*domain.Balance, error)
 	GetBalances(userID uint64, trx *gorm.DB) ([]domain.Balance, error)
+	GetAccountBalances(userID uint64, provider string, accountNo string) ([]domain.Balance, error)
 	GetProviderAccounts(userID uint64, assetType string) ([]domain.AccountResponse, error)
 	SaveBalance(balance *domain.Balance, trx *gorm.DB) error
 	GetDB() *gorm.DB
@@ -101,6 +102,15 @@ func (r *balanceRepo) GetBalances(userID uint64, trx *gorm.DB) ([]domain.Balance
 	return balance, nil
 }
 
+func (r *balanceRepo) GetAccountBalances(userID uint64, provider string, accountNo string) ([]domain.Balance, error) {
+	var balances []domain.Balance
+	if err := r.DB.Where("user_id = ? AND provider = ? AND account_no = ?", userID, provider, accountNo).Find(&balances).Error; err != nil {
+		return nil, err
+	}
+
+	return balances, nil
+}
+
 func (r *balanceRepo) GetBalance(balanceID uint64, trx *gorm.DB) (*domain.Balance, error) {
 	var balances domain.Balance
 	db := r.DB
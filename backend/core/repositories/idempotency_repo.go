@@ -0,0 +1,70 @@
+package repositories
+
+import (
+	"time"
+	"trade-tracker/core/domain"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type IdempotencyRepository interface {
+	// CreateRecord inserts the record unless the user already has one with the same key.
+	CreateRecord(record *domain.IdempotencyRecord) (bool, error)
+	GetRecord(userID uint64, key string) (*domain.IdempotencyRecord, error)
+	CompleteRecord(id uint, status int, contentType string, body []byte) error
+	DeleteRecord(id uint) error
+	DeleteExpired(now time.Time) (int64, error)
+
+	// CreateWorkerNonce inserts the nonce unless it is already stored.
+	CreateWorkerNonce(nonce *domain.WorkerNonce) (bool, error)
+	DeleteExpiredWorkerNonces(now time.Time) (int64, error)
+}
+
+type idempotencyRepo struct {
+	DB *gorm.DB
+}
+
+func NewIdempotencyRepo(DB *gorm.DB) IdempotencyRepository {
+	return &idempotencyRepo{DB: DB}
+}
+
+func (r *idempotencyRepo) CreateRecord(record *domain.IdempotencyRecord) (bool, error) {
+	result := r.DB.Clauses(clause.OnConflict{DoNothing: true}).Create(record)
+	return result.RowsAffected == 1, result.Error
+}
+
+func (r *idempotencyRepo) GetRecord(userID uint64, key string) (*domain.IdempotencyRecord, error) {
+	var record domain.IdempotencyRecord
+	if err := r.DB.Where("user_id = ? AND key = ?", userID, key).Take(&record).Error; err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
+
+func (r *idempotencyRepo) CompleteRecord(id uint, status int, contentType string, body []byte) error {
+	return r.DB.Model(&domain.IdempotencyRecord{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"status_code":   status,
+		"content_type":  contentType,
+		"response_body": body,
+	}).Error
+}
+
+func (r *idempotencyRepo) DeleteRecord(id uint) error {
+	return r.DB.Delete(&domain.IdempotencyRecord{}, id).Error
+}
+
+func (r *idempotencyRepo) DeleteExpired(now time.Time) (int64, error) {
+	result := r.DB.Where("expires_at < ?", now).Delete(&domain.IdempotencyRecord{})
+	return result.RowsAffected, result.Error
+}
+
+func (r *idempotencyRepo) CreateWorkerNonce(nonce *domain.WorkerNonce) (bool, error) {
+	result := r.DB.Clauses(clause.OnConflict{DoNothing: true}).Create(nonce)
+	return result.RowsAffected == 1, result.Error
+}
+
+func (r *idempotencyRepo) DeleteExpiredWorkerNonces(now time.Time) (int64, error) {
+	result := r.DB.Where("expires_at < ?", now).Delete(&domain.WorkerNonce{})
+	return result.RowsAffected, result.Error
+}
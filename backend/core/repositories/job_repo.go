@@ -0,0 +1,63 @@
+package repositories
+
+import (
+	"trade-tracker/core/domain"
+
+	"gorm.io/gorm"
+)
+
+type JobRepository interface {
+	CreateRun(run *domain.JobRun, trx *gorm.DB) error
+	UpdateRun(run *domain.JobRun, trx *gorm.DB) error
+
+	GetRuns(jobName string, limit int) ([]domain.JobRun, error)
+	GetLastRun(jobName string) (*domain.JobRun, error)
+}
+
+type jobRepo struct {
+	DB *gorm.DB
+}
+
+func NewJobRepo(DB *gorm.DB) JobRepository {
+	return &jobRepo{DB: DB}
+}
+
+func (r *jobRepo) CreateRun(run *domain.JobRun, trx *gorm.DB) error {
+	db := r.DB
+	if trx != nil {
+		db = trx
+	}
+	return db.Create(run).Error
+}
+
+func (r *jobRepo) UpdateRun(run *domain.JobRun, trx *gorm.DB) error {
+	db := r.DB
+	if trx != nil {
+		db = trx
+	}
+	return db.Save(run).Error
+}
+
+func (r *jobRepo) GetRuns(jobName string, limit int) ([]domain.JobRun, error) {
+	var runs []domain.JobRun
+	query := r.DB.Order("started_at DESC").Limit(limit)
+	if jobName != "" {
+		query = query.Where("job_name = ?", jobName)
+	}
+
+	if err := query.Find(&runs).Error; err != nil {
+		return nil, err
+	}
+	return runs, nil
+}
+
+func (r *jobRepo) GetLastRun(jobName string) (*domain.JobRun, error) {
+	var run domain.JobRun
+	if err := r.DB.Where("job_name = ?", jobName).Order("started_at DESC").Take(&run).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &run, nil
+}
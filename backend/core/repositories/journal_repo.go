@@ -0,0 +1,98 @@
+package repositories
+
+import (
+	"trade-tracker/core/domain"
+
+	"gorm.io/gorm"
+)
+
+type JournalRepository interface {
+	AddEntry(entry *domain.JournalEntry, trx *gorm.DB) error
+	UpdateEntry(entry *domain.JournalEntry, trx *gorm.DB) error
+	RemoveEntry(id uint, userID uint64, trx *gorm.DB) error
+
+	GetEntry(id uint, userID uint64, trx *gorm.DB) (*domain.JournalEntry, error)
+	GetEntries(userID uint64) ([]domain.JournalEntry, error)
+	GetEntriesByTransaction(userID uint64, transactionID uint) ([]domain.JournalEntry, error)
+	GetEntriesByPosition(userID uint64, positionID uint) ([]domain.JournalEntry, error)
+	GetDB() *gorm.DB
+}
+
+type journalRepo struct {
+	DB *gorm.DB
+}
+
+func NewJournalRepo(DB *gorm.DB) JournalRepository {
+	return &journalRepo{DB: DB}
+}
+
+func (r *journalRepo) AddEntry(entry *domain.JournalEntry, trx *gorm.DB) error {
+	db := r.DB
+	if trx != nil {
+		db = trx
+	}
+	return db.Create(entry).Error
+}
+
+func (r *journalRepo) UpdateEntry(entry *domain.JournalEntry, trx *gorm.DB) error {
+	db := r.DB
+	if trx != nil {
+		db = trx
+	}
+	return db.Save(entry).Error
+}
+
+func (r *journalRepo) RemoveEntry(id uint, userID uint64, trx *gorm.DB) error {
+	db := r.DB
+	if trx != nil {
+		db = trx
+	}
+
+	result := db.Unscoped().Where("id = ? AND user_id = ?", id, userID).Delete(&domain.JournalEntry{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrItemNotFound
+	}
+	return nil
+}
+
+func (r *journalRepo) GetEntry(id uint, userID uint64, trx *gorm.DB) (*domain.JournalEntry, error) {
+	db := r.DB
+	if trx != nil {
+		db = trx
+	}
+
+	var entry domain.JournalEntry
+	if err := db.Where("id = ? AND user_id = ?", id, userID).Take(&entry).Error; err != nil {
+		return nil, domain.ErrItemNotFound
+	}
+	return &entry, nil
+}
+
+func (r *journalRepo) GetEntries(userID uint64) ([]domain.JournalEntry, error) {
+	var entries []domain.JournalEntry
+	err := r.DB.Where("user_id = ?", userID).Order("created_at DESC").Find(&entries).Error
+	return entries, err
+}
+
+func (r *journalRepo) GetEntriesByTransaction(userID uint64, transactionID uint) ([]domain.JournalEntry, error) {
+	var entries []domain.JournalEntry
+	err := r.DB.Where("user_id = ? AND transaction_ids @> ARRAY[?]::bigint[]", userID, transactionID).
+		Order("created_at ASC").
+		Find(&entries).Error
+	return entries, err
+}
+
+func (r *journalRepo) GetEntriesByPosition(userID uint64, positionID uint) ([]domain.JournalEntry, error) {
+	var entries []domain.JournalEntry
+	err := r.DB.Where("user_id = ? AND position_ids @> ARRAY[?]::bigint[]", userID, positionID).
+		Order("created_at ASC").
+		Find(&entries).Error
+	return entries, err
+}
+
+func (r *journalRepo) GetDB() *gorm.DB {
+	return r.DB
+}
@@ -0,0 +1,39 @@
+package repositories
+
+import (
+	"trade-tracker/core/domain"
+
+	"gorm.io/gorm"
+)
+
+type LoginEventRepository interface {
+	CreateEvent(event *domain.LoginEvent) error
+	GetUserEvents(userID uint64, limit int) ([]domain.LoginEvent, error)
+	HasSuccessfulLogin(userID uint64, ip string, userAgent string) (bool, error)
+}
+
+type loginEventRepo struct {
+	DB *gorm.DB
+}
+
+func NewLoginEventRepo(DB *gorm.DB) LoginEventRepository {
+	return &loginEventRepo{DB: DB}
+}
+
+func (r *loginEventRepo) CreateEvent(event *domain.LoginEvent) error {
+	return r.DB.Create(event).Error
+}
+
+func (r *loginEventRepo) GetUserEvents(userID uint64, limit int) ([]domain.LoginEvent, error) {
+	var events []domain.LoginEvent
+	err := r.DB.Where("user_id = ?", userID).Order("created_at DESC").Limit(limit).Find(&events).Error
+	return events, err
+}
+
+func (r *loginEventRepo) HasSuccessfulLogin(userID uint64, ip string, userAgent string) (bool, error) {
+	var count int64
+	err := r.DB.Model(&domain.LoginEvent{}).
+		Where("user_id = ? AND success = ? AND ip = ? AND user_agent = ?", userID, true, ip, userAgent).
+		Count(&count).Error
+	return count > 0, err
+}
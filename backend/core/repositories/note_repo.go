@@ -1,18 +1,33 @@
 package repositories
 
 import (
+	"time"
 	"trade-tracker/core/domain"
 
+	"github.com/lib/pq"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type NoteRepository interface {
 	AddNote(note *domain.Note, trx *gorm.DB) error
 	RemoveNote(noteID uint, trx *gorm.DB) error
+	RestoreNote(noteID uint, trx *gorm.DB) error
+	PurgeNote(noteID uint, trx *gorm.DB) error
 	UpdateNote(note *domain.Note, trx *gorm.DB) error
 
 	GetNote(noteID uint, tx *gorm.DB) (*domain.Note, error)
 	GetNotes(userID uint64) ([]domain.Note, error)
+	SearchNotes(userID uint64, search domain.NoteSearch, offset, limit int) ([]domain.Note, int64, error)
+	GetDeletedNote(noteID uint, trx *gorm.DB) (*domain.Note, error)
+	GetDeletedNotes(userID uint64, offset, limit int) ([]domain.Note, int64, error)
+	GetExpiredNotes(before time.Time, limit int) ([]domain.Note, error)
+
+	AddImage(image *domain.NoteImage, trx *gorm.DB) error
+	RemoveImage(imageID uint, noteID uint, trx *gorm.DB) (*domain.NoteImage, error)
+	RemoveNoteImages(noteID uint, trx *gorm.DB) ([]domain.NoteImage, error)
+	RemoveUserImages(userID uint64, trx *gorm.DB) ([]domain.NoteImage, error)
+	GetNoteImages(noteIDs []uint, trx *gorm.DB) ([]domain.NoteImage, error)
 	GetDB() *gorm.DB
 }
 
@@ -39,7 +54,7 @@ func (r *noteRepo) RemoveNote(noteID uint, trx *gorm.DB) error {
 		db = trx
 	}
 
-	result := db.Unscoped().Delete(&domain.Note{}, noteID)
+	result := db.Delete(&domain.Note{}, noteID)
 	if result.Error != nil {
 		return result.Error
 	}
@@ -51,6 +66,63 @@ func (r *noteRepo) RemoveNote(noteID uint, trx *gorm.DB) error {
 	return nil
 }
 
+func (r *noteRepo) RestoreNote(noteID uint, trx *gorm.DB) error {
+	db := r.DB
+	if trx != nil {
+		db = trx
+	}
+	return db.Unscoped().Model(&domain.Note{}).Where("id = ?", noteID).Update("deleted_at", nil).Error
+}
+
+// PurgeNote permanently deletes a note, whether or not it is in the trash.
+func (r *noteRepo) PurgeNote(noteID uint, trx *gorm.DB) error {
+	db := r.DB
+	if trx != nil {
+		db = trx
+	}
+
+	result := db.Unscoped().Delete(&domain.Note{}, noteID)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrItemNotFound
+	}
+	return nil
+}
+
+func (r *noteRepo) GetDeletedNote(noteID uint, trx *gorm.DB) (*domain.Note, error) {
+	db := r.DB
+	if trx != nil {
+		db = trx
+	}
+
+	var note domain.Note
+	if err := db.Unscoped().Where("id = ? AND deleted_at IS NOT NULL", noteID).Take(&note).Error; err != nil {
+		return nil, err
+	}
+	return &note, nil
+}
+
+func (r *noteRepo) GetDeletedNotes(userID uint64, offset, limit int) ([]domain.Note, int64, error) {
+	query := r.DB.Unscoped().Model(&domain.Note{}).Where("user_id = ? AND deleted_at IS NOT NULL", userID)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var notes []domain.Note
+	err := query.Order("deleted_at DESC, id DESC").Offset(offset).Limit(limit).Find(&notes).Error
+	return notes, total, err
+}
+
+func (r *noteRepo) GetExpiredNotes(before time.Time, limit int) ([]domain.Note, error) {
+	var notes []domain.Note
+	err := r.DB.Unscoped().Where("deleted_at < ?", before).Order("deleted_at ASC").Limit(limit).Find(&notes).Error
+	return notes, err
+}
+
 func (r *noteRepo) UpdateNote(note *domain.Note, trx *gorm.DB) error {
 	db := r.DB
 	if trx != nil {
@@ -61,13 +133,59 @@ func (r *noteRepo) UpdateNote(note *domain.Note, trx *gorm.DB) error {
 
 func (r *noteRepo) GetNotes(userID uint64) ([]domain.Note, error) {
 	var notes []domain.Note
-	if err := r.DB.Where("user_id = ?", userID).Find(&notes).Error; err != nil {
+	if err := r.DB.Where("user_id = ?", userID).Order("updated_at DESC").Find(&notes).Error; err != nil {
 		return nil, err
 	}
 
 	return notes, nil
 }
 
+// noteDocument must match the expression of idx_notes_search so full-text queries can use the index.
+const noteDocument = "to_tsvector('simple', title || ' ' || description)"
+
+func (r *noteRepo) SearchNotes(userID uint64, search domain.NoteSearch, offset, limit int) ([]domain.Note, int64, error) {
+	query := r.DB.Model(&domain.Note{}).Where("user_id = ?", userID)
+	if search.Query != "" {
+		query = query.Where(noteDocument+" @@ websearch_to_tsquery('simple', ?)", search.Query)
+	}
+	if search.Category != "" {
+		query = query.Where("LOWER(category) = LOWER(?)", search.Category)
+	}
+	if len(search.Tags) > 0 {
+		query = query.Where("tags @> ?", pq.StringArray(search.Tags))
+	}
+	if !search.From.IsZero() {
+		query = query.Where("created_at >= ?", search.From)
+	}
+	if !search.To.IsZero() {
+		query = query.Where("created_at < ?", search.To)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	direction := "ASC"
+	if search.Desc {
+		direction = "DESC"
+	}
+	// The id tiebreaker keeps pages stable; it is part of one ORDER BY since GORM drops an
+	// expression when a later Order call merges into it.
+	if search.Sort == domain.NoteSortRelevance {
+		query = query.Order(clause.OrderBy{Expression: clause.Expr{
+			SQL:  "ts_rank(" + noteDocument + ", websearch_to_tsquery('simple', ?)) " + direction + ", id DESC",
+			Vars: []interface{}{search.Query},
+		}})
+	} else {
+		query = query.Order(search.Sort + " " + direction + ", id DESC")
+	}
+
+	var notes []domain.Note
+	err := query.Offset(offset).Limit(limit).Find(&notes).Error
+	return notes, total, err
+}
+
 func (r *noteRepo) GetNote(noteID uint, tx *gorm.DB) (*domain.Note, error) {
 	db := r.DB
 
@@ -82,6 +200,64 @@ func (r *noteRepo) GetNote(noteID uint, tx *gorm.DB) (*domain.Note, error) {
 	return &notes, nil
 }
 
+func (r *noteRepo) AddImage(image *domain.NoteImage, trx *gorm.DB) error {
+	db := r.DB
+	if trx != nil {
+		db = trx
+	}
+	return db.Create(image).Error
+}
+
+func (r *noteRepo) RemoveImage(imageID uint, noteID uint, trx *gorm.DB) (*domain.NoteImage, error) {
+	db := r.DB
+	if trx != nil {
+		db = trx
+	}
+
+	var image domain.NoteImage
+	if err := db.Where("id = ? AND note_id = ?", imageID, noteID).Take(&image).Error; err != nil {
+		return nil, domain.ErrImageNotFound
+	}
+	if err := db.Unscoped().Delete(&image).Error; err != nil {
+		return nil, err
+	}
+	return &image, nil
+}
+
+// RemoveNoteImages deletes the image rows of a note and returns them so their objects can be removed.
+func (r *noteRepo) RemoveNoteImages(noteID uint, trx *gorm.DB) ([]domain.NoteImage, error) {
+	db := r.DB
+	if trx != nil {
+		db = trx
+	}
+
+	var images []domain.NoteImage
+	err := db.Unscoped().Clauses(clause.Returning{}).Where("note_id = ?", noteID).Delete(&images).Error
+	return images, err
+}
+
+func (r *noteRepo) RemoveUserImages(userID uint64, trx *gorm.DB) ([]domain.NoteImage, error) {
+	db := r.DB
+	if trx != nil {
+		db = trx
+	}
+
+	var images []domain.NoteImage
+	err := db.Unscoped().Clauses(clause.Returning{}).Where("user_id = ?", userID).Delete(&images).Error
+	return images, err
+}
+
+func (r *noteRepo) GetNoteImages(noteIDs []uint, trx *gorm.DB) ([]domain.NoteImage, error) {
+	db := r.DB
+	if trx != nil {
+		db = trx
+	}
+
+	var images []domain.NoteImage
+	err := db.Where("note_id IN ?", noteIDs).Order("id ASC").Find(&images).Error
+	return images, err
+}
+
 func (r *noteRepo) GetDB() *gorm.DB {
 	return r.DB
 }
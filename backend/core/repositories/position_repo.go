@@ -13,6 +13,8 @@ type PositionRepository interface {
 	UpdatePosition(pos *domain.Position, trx *gorm.DB) error
 
 	GetPositions(userID uint64) ([]domain.Position, error)
+	GetPositionsByIDs(userID uint64, ids []uint, tx *gorm.DB) ([]domain.Position, error)
+	GetAccountPositions(userID uint64, provider string, accountNo string) ([]domain.Position, error)
 	GetPosByTicker(userID uint64, ticker string, provider string, accountNo string, tx *gorm.DB) (*domain.Position, error)
 	GetDB() *gorm.DB
 }
@@ -59,6 +61,27 @@ func (r *positionRepo) GetPositions(userID uint64) ([]domain.Position, error) {
 	return positions, nil
 }
 
+func (r *positionRepo) GetPositionsByIDs(userID uint64, ids []uint, tx *gorm.DB) ([]domain.Position, error) {
+	db := r.DB
+	if tx != nil {
+		db = tx
+	}
+	var positions []domain.Position
+	if err := db.Where("owner_id = ? AND id IN ?", userID, ids).Find(&positions).Error; err != nil {
+		return nil, err
+	}
+	return positions, nil
+}
+
+func (r *positionRepo) GetAccountPositions(userID uint64, provider string, accountNo string) ([]domain.Position, error) {
+	var positions []domain.Position
+	if err := r.DB.Where("owner_id = ? AND provider = ? AND account_no = ?", userID, provider, accountNo).Find(&positions).Error; err != nil {
+		return nil, err
+	}
+
+	return positions, nil
+}
+
 func (r *positionRepo) RemovePosition(posID uint, trx *gorm.DB) error {
 	db := r.DB
 	if trx != nil {
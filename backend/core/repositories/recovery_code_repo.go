@@ -0,0 +1,72 @@
+package repositories
+
+import (
+	"time"
+	"trade-tracker/core/domain"
+
+	"gorm.io/gorm"
+)
+
+type RecoveryCodeRepository interface {
+	ReplaceCodes(userID uint64, hashes []string, trx *gorm.DB) error
+	DeleteCodes(userID uint64, trx *gorm.DB) error
+	UseCode(userID uint64, hash string, trx *gorm.DB) (bool, error)
+	CountUnused(userID uint64) (int64, error)
+	GetDB() *gorm.DB
+}
+
+type recoveryCodeRepo struct {
+	DB *gorm.DB
+}
+
+func NewRecoveryCodeRepo(DB *gorm.DB) RecoveryCodeRepository {
+	return &recoveryCodeRepo{DB: DB}
+}
+
+func (r *recoveryCodeRepo) ReplaceCodes(userID uint64, hashes []string, trx *gorm.DB) error {
+	db := r.DB
+	if trx != nil {
+		db = trx
+	}
+
+	if err := db.Unscoped().Where("user_id = ?", userID).Delete(&domain.RecoveryCode{}).Error; err != nil {
+		return err
+	}
+
+	codes := make([]domain.RecoveryCode, len(hashes))
+	for i, h := range hashes {
+		codes[i] = domain.RecoveryCode{UserID: userID, CodeHash: h}
+	}
+	return db.Create(&codes).Error
+}
+
+func (r *recoveryCodeRepo) DeleteCodes(userID uint64, trx *gorm.DB) error {
+	db := r.DB
+	if trx != nil {
+		db = trx
+	}
+	return db.Unscoped().Where("user_id = ?", userID).Delete(&domain.RecoveryCode{}).Error
+}
+
+// UseCode marks a code as used and reports false when it does not exist or was already used.
+func (r *recoveryCodeRepo) UseCode(userID uint64, hash string, trx *gorm.DB) (bool, error) {
+	db := r.DB
+	if trx != nil {
+		db = trx
+	}
+
+	result := db.Model(&domain.RecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, hash).
+		Update("used_at", time.Now())
+	return result.RowsAffected == 1, result.Error
+}
+
+func (r *recoveryCodeRepo) CountUnused(userID uint64) (int64, error) {
+	var count int64
+	err := r.DB.Model(&domain.RecoveryCode{}).Where("user_id = ? AND used_at IS NULL", userID).Count(&count).Error
+	return count, err
+}
+
+func (r *recoveryCodeRepo) GetDB() *gorm.DB {
+	return r.DB
+}
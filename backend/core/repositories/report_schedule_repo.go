@@ -0,0 +1,89 @@
+package repositories
+
+import (
+	"time"
+	"trade-tracker/core/domain"
+
+	"gorm.io/gorm"
+)
+
+type ReportScheduleRepository interface {
+	CreateSchedule(schedule *domain.ReportSchedule, trx *gorm.DB) error
+	SaveSchedule(schedule *domain.ReportSchedule, trx *gorm.DB) error
+	DeleteSchedule(id uint, userID uint64, trx *gorm.DB) error
+
+	GetSchedule(id uint, userID uint64) (*domain.ReportSchedule, error)
+	GetUserSchedules(userID uint64) ([]domain.ReportSchedule, error)
+	GetDueSchedules(now time.Time, limit int) ([]domain.ReportSchedule, error)
+	GetDB() *gorm.DB
+}
+
+type reportScheduleRepo struct {
+	DB *gorm.DB
+}
+
+func NewReportScheduleRepo(DB *gorm.DB) ReportScheduleRepository {
+	return &reportScheduleRepo{DB: DB}
+}
+
+func (r *reportScheduleRepo) CreateSchedule(schedule *domain.ReportSchedule, trx *gorm.DB) error {
+	db := r.DB
+	if trx != nil {
+		db = trx
+	}
+	return db.Create(schedule).Error
+}
+
+func (r *reportScheduleRepo) SaveSchedule(schedule *domain.ReportSchedule, trx *gorm.DB) error {
+	db := r.DB
+	if trx != nil {
+		db = trx
+	}
+	return db.Save(schedule).Error
+}
+
+func (r *reportScheduleRepo) DeleteSchedule(id uint, userID uint64, trx *gorm.DB) error {
+	db := r.DB
+	if trx != nil {
+		db = trx
+	}
+
+	result := db.Unscoped().Where("id = ? AND user_id = ?", id, userID).Delete(&domain.ReportSchedule{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrItemNotFound
+	}
+	return nil
+}
+
+func (r *reportScheduleRepo) GetSchedule(id uint, userID uint64) (*domain.ReportSchedule, error) {
+	var schedule domain.ReportSchedule
+	if err := r.DB.Where("id = ? AND user_id = ?", id, userID).Take(&schedule).Error; err != nil {
+		return nil, domain.ErrItemNotFound
+	}
+	return &schedule, nil
+}
+
+func (r *reportScheduleRepo) GetUserSchedules(userID uint64) ([]domain.ReportSchedule, error) {
+	var schedules []domain.ReportSchedule
+	err := r.DB.Where("user_id = ?", userID).Order("created_at ASC").Find(&schedules).Error
+	return schedules, err
+}
+
+// GetDueSchedules leaves out schedules of locked or unverified users, so they neither receive
+// mail nor take up the limit; their schedules become due again once the account is usable.
+func (r *reportScheduleRepo) GetDueSchedules(now time.Time, limit int) ([]domain.ReportSchedule, error) {
+	var schedules []domain.ReportSchedule
+	usable := r.DB.Model(&domain.User{}).Select("id").Where("verified = ? AND locked_at IS NULL", true)
+	err := r.DB.Where("active = ? AND next_run_at <= ? AND user_id IN (?)", true, now, usable).
+		Order("next_run_at ASC").
+		Limit(limit).
+		Find(&schedules).Error
+	return schedules, err
+}
+
+func (r *reportScheduleRepo) GetDB() *gorm.DB {
+	return r.DB
+}
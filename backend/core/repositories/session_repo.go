@@ -0,0 +1,142 @@
+package repositories
+
+import (
+	"time"
+	"trade-tracker/core/domain"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type SessionRepository interface {
+	CreateSession(session *domain.Session, trx *gorm.DB) error
+	UpdateSession(session *domain.Session, trx *gorm.DB) error
+	RevokeSession(sessionID uint, userID uint64, trx *gorm.DB) error
+	RevokeUserSessions(userID uint64, trx *gorm.DB) error
+	RevokeOtherSessions(userID uint64, keepSessionID uint, trx *gorm.DB) error
+
+	GetSessionByID(sessionID uint, trx *gorm.DB) (*domain.Session, error)
+	GetSessionByTokenHash(hash string, trx *gorm.DB) (*domain.Session, error)
+	GetSessionByPreviousHash(hash string, trx *gorm.DB) (*domain.Session, error)
+	GetActiveSessions(userID uint64) ([]domain.Session, error)
+	GetDB() *gorm.DB
+}
+
+type sessionRepo struct {
+	DB *gorm.DB
+}
+
+func NewSessionRepo(DB *gorm.DB) SessionRepository {
+	return &sessionRepo{DB: DB}
+}
+
+func (r *sessionRepo) CreateSession(session *domain.Session, trx *gorm.DB) error {
+	db := r.DB
+	if trx != nil {
+		db = trx
+	}
+	return db.Create(session).Error
+}
+
+func (r *sessionRepo) UpdateSession(session *domain.Session, trx *gorm.DB) error {
+	db := r.DB
+	if trx != nil {
+		db = trx
+	}
+	return db.Save(session).Error
+}
+
+func (r *sessionRepo) RevokeSession(sessionID uint, userID uint64, trx *gorm.DB) error {
+	db := r.DB
+	if trx != nil {
+		db = trx
+	}
+
+	result := db.Model(&domain.Session{}).
+		Where("id = ? AND user_id = ? AND revoked_at IS NULL", sessionID, userID).
+		Update("revoked_at", time.Now())
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return domain.ErrItemNotFound
+	}
+
+	return nil
+}
+
+func (r *sessionRepo) RevokeUserSessions(userID uint64, trx *gorm.DB) error {
+	db := r.DB
+	if trx != nil {
+		db = trx
+	}
+	return db.Model(&domain.Session{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", time.Now()).Error
+}
+
+func (r *sessionRepo) RevokeOtherSessions(userID uint64, keepSessionID uint, trx *gorm.DB) error {
+	db := r.DB
+	if trx != nil {
+		db = trx
+	}
+	return db.Model(&domain.Session{}).
+		Where("user_id = ? AND id <> ? AND revoked_at IS NULL", userID, keepSessionID).
+		Update("revoked_at", time.Now()).Error
+}
+
+func (r *sessionRepo) GetSessionByID(sessionID uint, trx *gorm.DB) (*domain.Session, error) {
+	db := r.DB
+	if trx != nil {
+		db = trx
+	}
+
+	var session domain.Session
+	if err := db.Take(&session, sessionID).Error; err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+func (r *sessionRepo) GetSessionByTokenHash(hash string, trx *gorm.DB) (*domain.Session, error) {
+	db := r.DB
+	if trx != nil {
+		db = trx
+	}
+
+	var session domain.Session
+	if err := db.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("refresh_token_hash = ?", hash).
+		Take(&session).Error; err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+func (r *sessionRepo) GetSessionByPreviousHash(hash string, trx *gorm.DB) (*domain.Session, error) {
+	db := r.DB
+	if trx != nil {
+		db = trx
+	}
+
+	var session domain.Session
+	if err := db.Where("previous_token_hash = ?", hash).Take(&session).Error; err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+func (r *sessionRepo) GetActiveSessions(userID uint64) ([]domain.Session, error) {
+	var sessions []domain.Session
+	if err := r.DB.Where("user_id = ? AND revoked_at IS NULL AND expires_at > ?", userID, time.Now()).
+		Order("last_used_at DESC").
+		Find(&sessions).Error; err != nil {
+		return nil, err
+	}
+	return sessions, nil
+}
+
+func (r *sessionRepo) GetDB() *gorm.DB {
+	return r.DB
+}
@@ -0,0 +1,94 @@
+package repositories
+
+import (
+	"trade-tracker/core/domain"
+
+	"gorm.io/gorm"
+)
+
+type ShareRepository interface {
+	CreateMember(member *domain.AccountMember, trx *gorm.DB) error
+	UpdateMember(member *domain.AccountMember, trx *gorm.DB) error
+	RemoveMember(memberID uint, trx *gorm.DB) error
+
+	GetMember(id uint) (*domain.AccountMember, error)
+	GetAcceptedMember(ownerID, memberID uint64, provider, accountNo string) (*domain.AccountMember, error)
+	GetOwnedMembers(ownerID uint64) ([]domain.AccountMember, error)
+	GetMemberships(memberID uint64) ([]domain.AccountMember, error)
+	GetDB() *gorm.DB
+}
+
+type shareRepo struct {
+	DB *gorm.DB
+}
+
+func NewShareRepo(DB *gorm.DB) ShareRepository {
+	return &shareRepo{DB: DB}
+}
+
+func (r *shareRepo) CreateMember(member *domain.AccountMember, trx *gorm.DB) error {
+	db := r.DB
+	if trx != nil {
+		db = trx
+	}
+	return db.Create(member).Error
+}
+
+func (r *shareRepo) UpdateMember(member *domain.AccountMember, trx *gorm.DB) error {
+	db := r.DB
+	if trx != nil {
+		db = trx
+	}
+	return db.Save(member).Error
+}
+
+func (r *shareRepo) RemoveMember(memberID uint, trx *gorm.DB) error {
+	db := r.DB
+	if trx != nil {
+		db = trx
+	}
+
+	result := db.Unscoped().Delete(&domain.AccountMember{}, memberID)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrItemNotFound
+	}
+	return nil
+}
+
+func (r *shareRepo) GetMember(id uint) (*domain.AccountMember, error) {
+	var member domain.AccountMember
+	if err := r.DB.Preload("Owner").Preload("Member").Take(&member, id).Error; err != nil {
+		return nil, err
+	}
+	return &member, nil
+}
+
+func (r *shareRepo) GetAcceptedMember(ownerID, memberID uint64, provider, accountNo string) (*domain.AccountMember, error) {
+	var member domain.AccountMember
+	err := r.DB.
+		Where("owner_id = ? AND member_id = ? AND provider = ? AND account_no = ? AND accepted_at IS NOT NULL", ownerID, memberID, provider, accountNo).
+		Take(&member).Error
+	if err != nil {
+		return nil, err
+	}
+	return &member, nil
+}
+
+func (r *shareRepo) GetOwnedMembers(ownerID uint64) ([]domain.AccountMember, error) {
+	var members []domain.AccountMember
+	err := r.DB.Preload("Owner").Preload("Member").Where("owner_id = ?", ownerID).Order("created_at DESC").Find(&members).Error
+	return members, err
+}
+
+func (r *shareRepo) GetMemberships(memberID uint64) ([]domain.AccountMember, error) {
+	var members []domain.AccountMember
+	err := r.DB.Preload("Owner").Preload("Member").Where("member_id = ?", memberID).Order("created_at DESC").Find(&members).Error
+	return members, err
+}
+
+func (r *shareRepo) GetDB() *gorm.DB {
+	return r.DB
+}
@@ -1,16 +1,26 @@
 package repositories
 
 import (
+	"time"
 	"trade-tracker/core/domain"
 
+	"github.com/lib/pq"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type TransactionRepository interface {
 	AddTransaction(log *domain.Transaction, tx *gorm.DB) error
 	GetTransactions(userID uint64) ([]domain.Transaction, error)
+	GetAccountTransactions(userID uint64, provider string, accountNo string) ([]domain.Transaction, error)
 	GetTransactionByID(id uint, tx *gorm.DB) (*domain.Transaction, error)
+	GetTransactionsByIDs(userID uint64, ids []uint, tx *gorm.DB) ([]domain.Transaction, error)
 	UpdateTransaction(transaction *domain.Transaction, tx *gorm.DB) error
+	RemoveTransaction(id uint, tx *gorm.DB) error
+	RestoreTransaction(id uint, tx *gorm.DB) error
+	GetDeletedTransaction(id uint, tx *gorm.DB) (*domain.Transaction, error)
+	GetDeletedTransactions(userID uint64) ([]domain.Transaction, error)
+	PurgeDeletedTransactions(before time.Time) (int64, error)
 	MigrateTradingTransactions(userID uint64, provider string, accountNo string, tx *gorm.DB) error
 	MigrateNonTradingTransactions(userID uint64, provider string, accountNo string, transactionIDs []uint, tx *gorm.DB) error
 	GetTransactionsByIDsAndTypes(userID uint64, ids []uint, types []string, tx *gorm.DB) ([]domain.Transaction, error)
@@ -42,6 +52,15 @@ func (r *transactionRepo) GetTransactions(userID uint64) ([]domain.Transaction,
 	return transactions, nil
 }
 
+func (r *transactionRepo) GetAccountTransactions(userID uint64, provider string, accountNo string) ([]domain.Transaction, error) {
+	var transactions []domain.Transaction
+	if err := r.DB.Where("owner_id = ? AND provider = ? AND account_no = ?", userID, provider, accountNo).Order("created_at DESC").Find(&transactions).Error; err != nil {
+		return nil, err
+	}
+
+	return transactions, nil
+}
+
 func (r *transactionRepo) GetTransactionByID(id uint, tx *gorm.DB) (*domain.Transaction, error) {
 	db := r.DB
 	if tx != nil {
@@ -105,3 +124,87 @@ func (r *transactionRepo) GetTransactionsByIDsAndTypes(userID uint64, ids []uint
 	}
 	return transactions, nil
 }
+
+func (r *transactionRepo) GetTransactionsByIDs(userID uint64, ids []uint, tx *gorm.DB) ([]domain.Transaction, error) {
+	db := r.DB
+	if tx != nil {
+		db = tx
+	}
+	var transactions []domain.Transaction
+	if err := db.Where("owner_id = ? AND id IN ?", userID, ids).Find(&transactions).Error; err != nil {
+		return nil, err
+	}
+	return transactions, nil
+}
+
+// RemoveTransaction moves a transaction to the trash; RestoreTransaction brings it back.
+func (r *transactionRepo) RemoveTransaction(id uint, tx *gorm.DB) error {
+	db := r.DB
+	if tx != nil {
+		db = tx
+	}
+	result := db.Delete(&domain.Transaction{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrItemNotFound
+	}
+	return nil
+}
+
+func (r *transactionRepo) RestoreTransaction(id uint, tx *gorm.DB) error {
+	db := r.DB
+	if tx != nil {
+		db = tx
+	}
+	return db.Unscoped().Model(&domain.Transaction{}).Where("id = ?", id).Update("deleted_at", nil).Error
+}
+
+func (r *transactionRepo) GetDeletedTransaction(id uint, tx *gorm.DB) (*domain.Transaction, error) {
+	db := r.DB
+	if tx != nil {
+		db = tx
+	}
+	var transaction domain.Transaction
+	if err := db.Unscoped().Where("id = ? AND deleted_at IS NOT NULL", id).Take(&transaction).Error; err != nil {
+		return nil, err
+	}
+	return &transaction, nil
+}
+
+func (r *transactionRepo) GetDeletedTransactions(userID uint64) ([]domain.Transaction, error) {
+	var transactions []domain.Transaction
+	err := r.DB.Unscoped().
+		Where("owner_id = ? AND deleted_at IS NOT NULL", userID).
+		Order("deleted_at DESC").
+		Find(&transactions).Error
+	return transactions, err
+}
+
+// PurgeDeletedTransactions also drops the purged IDs from journal entries, so no entry keeps
+// linking to a transaction that no longer exists.
+func (r *transactionRepo) PurgeDeletedTransactions(before time.Time) (int64, error) {
+	var purged int64
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		var transactions []domain.Transaction
+		result := tx.Unscoped().Clauses(clause.Returning{Columns: []clause.Column{{Name: "id"}}}).
+			Where("deleted_at < ?", before).Delete(&transactions)
+		if result.Error != nil {
+			return result.Error
+		}
+		purged = result.RowsAffected
+		if len(transactions) == 0 {
+			return nil
+		}
+
+		ids := make(pq.Int64Array, len(transactions))
+		for i, t := range transactions {
+			ids[i] = int64(t.ID)
+		}
+		return tx.Unscoped().Model(&domain.JournalEntry{}).
+			Where("transaction_ids && ?::bigint[]", ids).
+			Update("transaction_ids", gorm.Expr("ARRAY(SELECT linked FROM unnest(transaction_ids) AS linked WHERE linked <> ALL(?::bigint[]))", ids)).Error
+	})
+	return purged, err
+}
@@ -1,15 +1,27 @@
 package repositories
 
 import (
+	"strings"
+	"time"
 	"trade-tracker/core/domain"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type UserRepository interface {
 	CreateUser(user *domain.User, trx *gorm.DB) error
 	GetUserByUsernameOrEmail(username, email string, tx *gorm.DB) (*domain.User, error)
 	GetUserByID(userID uint64) (*domain.User, error)
+	UpdateUser(user *domain.User, trx *gorm.DB) error
+	AdvanceTOTPStep(userID uint64, step int64, trx *gorm.DB) (bool, error)
+	DeleteUser(userID uint64, trx *gorm.DB) error
+	RegisterFailedLogin(userID uint64) (int, error)
+	SetLoginLock(userID uint64, until *time.Time) error
+	ResetFailedLogins(userID uint64) error
+
+	ListUsers(search string, offset, limit int) ([]domain.User, int64, error)
+	CountUsers() (int64, error)
 
 	GetDB() *gorm.DB
 }
@@ -51,6 +63,122 @@ func (r *userRepo) GetUserByID(userID uint64) (*domain.User, error) {
 	return &user, nil
 }
 
+func (r *userRepo) UpdateUser(user *domain.User, trx *gorm.DB) error {
+	db := r.db
+	if trx != nil {
+		db = trx
+	}
+
+	return db.Save(user).Error
+}
+
+// AdvanceTOTPStep records the last accepted TOTP step and reports false if step was not newer,
+// which means the code was already used.
+func (r *userRepo) AdvanceTOTPStep(userID uint64, step int64, trx *gorm.DB) (bool, error) {
+	db := r.db
+	if trx != nil {
+		db = trx
+	}
+
+	result := db.Model(&domain.User{}).
+		Where("id = ? AND totp_last_step < ?", userID, step).
+		Update("totp_last_step", step)
+	return result.RowsAffected == 1, result.Error
+}
+
+// DeleteUser permanently removes the user and every row owned by them, skipping soft delete.
+func (r *userRepo) DeleteUser(userID uint64, trx *gorm.DB) error {
+	db := r.db
+	if trx != nil {
+		db = trx
+	}
+	db = db.Unscoped()
+
+	owned := []struct {
+		model  interface{}
+		column string
+	}{
+		{&domain.Transaction{}, "owner_id"},
+		{&domain.Position{}, "owner_id"},
+		{&domain.Balance{}, "user_id"},
+		{&domain.Note{}, "user_id"},
+		{&domain.NoteImage{}, "user_id"},
+		{&domain.JournalEntry{}, "user_id"},
+		{&domain.RecoveryCode{}, "user_id"},
+		{&domain.APIKey{}, "user_id"},
+		{&domain.AccountMember{}, "owner_id"},
+		{&domain.AccountMember{}, "member_id"},
+		{&domain.LoginEvent{}, "user_id"},
+		{&domain.IdempotencyRecord{}, "user_id"},
+		{&domain.ReportSchedule{}, "user_id"},
+		{&domain.Session{}, "user_id"},
+	}
+	for _, o := range owned {
+		if err := db.Where(o.column+" = ?", userID).Delete(o.model).Error; err != nil {
+			return err
+		}
+	}
+
+	// The audit trail is append-only, so its rows stay and only lose the link to the user.
+	for _, column := range []string{"owner_id", "actor_id"} {
+		if err := db.Model(&domain.AuditLog{}).Where(column+" = ?", userID).Update(column, 0).Error; err != nil {
+			return err
+		}
+	}
+
+	result := db.Delete(&domain.User{}, userID)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrUserNotFound
+	}
+	return nil
+}
+
+// RegisterFailedLogin increments the failed login counter atomically and returns its new value.
+func (r *userRepo) RegisterFailedLogin(userID uint64) (int, error) {
+	var user domain.User
+	result := r.db.Model(&user).
+		Clauses(clause.Returning{Columns: []clause.Column{{Name: "failed_logins"}}}).
+		Where("id = ?", userID).
+		Update("failed_logins", gorm.Expr("failed_logins + 1"))
+	return user.FailedLogins, result.Error
+}
+
+func (r *userRepo) SetLoginLock(userID uint64, until *time.Time) error {
+	return r.db.Model(&domain.User{}).Where("id = ?", userID).Update("locked_until", until).Error
+}
+
+func (r *userRepo) ResetFailedLogins(userID uint64) error {
+	return r.db.Model(&domain.User{}).
+		Where("id = ? AND (failed_logins <> 0 OR locked_until IS NOT NULL)", userID).
+		Updates(map[string]interface{}{"failed_logins": 0, "locked_until": nil}).Error
+}
+
+func (r *userRepo) ListUsers(search string, offset, limit int) ([]domain.User, int64, error) {
+	query := r.db.Model(&domain.User{})
+	if search != "" {
+		pattern := "%" + strings.ToLower(search) + "%"
+		query = query.Where("LOWER(username) LIKE ? OR LOWER(email) LIKE ? OR LOWER(name) LIKE ?", pattern, pattern, pattern)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var users []domain.User
+	err := query.Order("id ASC").Offset(offset).Limit(limit).Find(&users).Error
+	return users, total, err
+}
+
+func (r *userRepo) CountUsers() (int64, error) {
+	var count int64
+	err := r.db.Model(&domain.User{}).Count(&count).Error
+	return count, err
+}
+
 func (r *userRepo) GetDB() *gorm.DB {
 	return r.db
 }
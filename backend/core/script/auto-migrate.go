@@ -3,6 +3,7 @@ package main
 import (
 	"log"
 	"os"
+	"strings"
 
 	"trade-tracker/core/config"
 	"trade-tracker/core/domain"
@@ -26,11 +27,45 @@ func main() {
 		&domain.Position{},
 		&domain.Transaction{},
 		&domain.Note{},
+		&domain.NoteImage{},
+		&domain.JournalEntry{},
 		&domain.Balance{},
 		&domain.Asset{},
+		&domain.JobRun{},
+		&domain.Session{},
+		&domain.RecoveryCode{},
+		&domain.APIKey{},
+		&domain.AccountMember{},
+		&domain.LoginEvent{},
+		&domain.AuditLog{},
+		&domain.IdempotencyRecord{},
+		&domain.WorkerNonce{},
+		&domain.ReportSchedule{},
 	); err != nil {
 		log.Fatalf("Failed to migrate database: %v.\n", err)
 	}
 
+	// Full-text search over notes; the expression must match noteDocument in the note repository.
+	if err := db.Exec("CREATE INDEX IF NOT EXISTS idx_notes_search ON notes USING GIN (to_tsvector('simple', title || ' ' || description))").Error; err != nil {
+		log.Fatalf("Failed to create note search index: %v.\n", err)
+	}
+
+	// ADMIN_EMAILS bootstraps administrators, who can then manage roles through /admin.
+	for _, email := range strings.Split(os.Getenv("ADMIN_EMAILS"), ",") {
+		email = strings.ToLower(strings.TrimSpace(email))
+		if email == "" {
+			continue
+		}
+
+		result := db.Model(&domain.User{}).Where("email = ?", email).Update("role", domain.RoleAdmin)
+		if result.Error != nil {
+			log.Printf("Failed to promote %s: %v.\n", email, result.Error)
+		} else if result.RowsAffected == 0 {
+			log.Printf("No user with email %s to promote.\n", email)
+		} else {
+			log.Printf("Promoted %s to admin.\n", email)
+		}
+	}
+
 	log.Println("Database has been migrated!")
 }
@@ -0,0 +1,146 @@
+package services
+
+import (
+	"runtime"
+	"time"
+
+	"trade-tracker/core/domain"
+	"trade-tracker/core/integrations/providers"
+	"trade-tracker/core/repositories"
+)
+
+type AdminService interface {
+	ListUsers(search string, page, limit int) (*domain.AdminUserListResponse, error)
+	LockUser(adminID, userID uint64, reason string) error
+	UnlockUser(userID uint64) error
+	SetRole(adminID, userID uint64, role string) error
+
+	GetHealth() *domain.HealthResponse
+}
+
+type adminService struct {
+	userRepo       repositories.UserRepository
+	sessionService SessionService
+	jobService     JobService
+	startedAt      time.Time
+}
+
+func NewAdminService(userRepo repositories.UserRepository, sessionService SessionService, jobService JobService) AdminService {
+	return &adminService{
+		userRepo:       userRepo,
+		sessionService: sessionService,
+		jobService:     jobService,
+		startedAt:      time.Now(),
+	}
+}
+
+func toAdminUser(user domain.User) domain.AdminUserResponse {
+	return domain.AdminUserResponse{
+		ID:          user.ID,
+		Name:        user.Name,
+		Username:    user.Username,
+		Email:       user.Email,
+		Role:        user.Role,
+		Verified:    user.Verified,
+		TOTPEnabled: user.TOTPEnabled,
+		LockedAt:    user.LockedAt,
+		LockReason:  user.LockReason,
+		CreatedAt:   user.CreatedAt,
+	}
+}
+
+func (s *adminService) ListUsers(search string, page, limit int) (*domain.AdminUserListResponse, error) {
+	if page < 1 {
+		page = 1
+	}
+	if limit <= 0 || limit > 100 {
+		limit = 25
+	}
+
+	users, total, err := s.userRepo.ListUsers(search, (page-1)*limit, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &domain.AdminUserListResponse{Users: []domain.AdminUserResponse{}, Total: total, Page: page, Limit: limit}
+	for _, u := range users {
+		res.Users = append(res.Users, toAdminUser(u))
+	}
+	return res, nil
+}
+
+// LockUser blocks logins and ends every active session of the user.
+func (s *adminService) LockUser(adminID, userID uint64, reason string) error {
+	if adminID == userID {
+		return domain.ErrCannotEditSelf
+	}
+
+	user, err := s.userRepo.GetUserByID(userID)
+	if err != nil {
+		return domain.ErrUserNotFound
+	}
+
+	now := time.Now()
+	user.LockedAt = &now
+	user.LockReason = reason
+	if err := s.userRepo.UpdateUser(user, nil); err != nil {
+		return err
+	}
+
+	return s.sessionService.RevokeAllSessions(userID)
+}
+
+func (s *adminService) UnlockUser(userID uint64) error {
+	user, err := s.userRepo.GetUserByID(userID)
+	if err != nil {
+		return domain.ErrUserNotFound
+	}
+
+	user.LockedAt = nil
+	user.LockReason = ""
+	return s.userRepo.UpdateUser(user, nil)
+}
+
+func (s *adminService) SetRole(adminID, userID uint64, role string) error {
+	if adminID == userID && role != domain.RoleAdmin {
+		return domain.ErrCannotEditSelf
+	}
+
+	user, err := s.userRepo.GetUserByID(userID)
+	if err != nil {
+		return domain.ErrUserNotFound
+	}
+
+	user.Role = role
+	return s.userRepo.UpdateUser(user, nil)
+}
+
+func (s *adminService) GetHealth() *domain.HealthResponse {
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+
+	res := &domain.HealthResponse{
+		Status:              "ok",
+		Database:            "ok",
+		UptimeSeconds:       int64(time.Since(s.startedAt).Seconds()),
+		Goroutines:          runtime.NumGoroutine(),
+		MemoryAllocMB:       float64(mem.Alloc) / 1024 / 1024,
+		PriceStoreUpdatedAt: providers.StoreUpdatedAt(),
+		Jobs:                s.jobService.GetJobStatus(),
+	}
+
+	sqlDB, err := s.userRepo.GetDB().DB()
+	if err == nil {
+		err = sqlDB.Ping()
+	}
+	if err != nil {
+		res.Status = "degraded"
+		res.Database = err.Error()
+		return res
+	}
+
+	if count, err := s.userRepo.CountUsers(); err == nil {
+		res.Users = count
+	}
+	return res
+}
@@ -0,0 +1,111 @@
+package services
+
+import (
+	"log"
+	"time"
+
+	"trade-tracker/core/domain"
+	"trade-tracker/core/repositories"
+	"trade-tracker/pkg/utils/auth"
+)
+
+const (
+	maxAPIKeysPerUser = 10
+	// apiKeyTouchInterval limits how often last-used tracking writes to the database.
+	apiKeyTouchInterval = time.Minute
+)
+
+type APIKeyService interface {
+	CreateKey(userID uint64, req domain.CreateAPIKeyReq) (*domain.CreateAPIKeyResponse, error)
+	RevokeKey(keyID uint, userID uint64) error
+	GetKeys(userID uint64) ([]domain.APIKey, error)
+
+	// Authenticate resolves a raw key to its owner and scopes, and records its use.
+	Authenticate(rawKey string, ip string) (uint64, []string, error)
+}
+
+type apiKeyService struct {
+	repo repositories.APIKeyRepository
+}
+
+func NewAPIKeyService(repo repositories.APIKeyRepository) APIKeyService {
+	return &apiKeyService{repo: repo}
+}
+
+func (s *apiKeyService) CreateKey(userID uint64, req domain.CreateAPIKeyReq) (*domain.CreateAPIKeyResponse, error) {
+	existing, err := s.repo.GetUserKeys(userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(existing) >= maxAPIKeysPerUser {
+		return nil, domain.ErrTooManyAPIKeys
+	}
+
+	token, err := auth.GenerateOpaqueToken()
+	if err != nil {
+		return nil, err
+	}
+	rawKey := domain.APIKeyPrefix + token
+
+	scopes := make([]string, 0, len(req.Scopes))
+	seen := make(map[string]bool)
+	for _, scope := range req.Scopes {
+		if !seen[scope] {
+			seen[scope] = true
+			scopes = append(scopes, scope)
+		}
+	}
+
+	key := &domain.APIKey{
+		UserID:  userID,
+		Name:    req.Name,
+		Prefix:  rawKey[:len(domain.APIKeyPrefix)+6],
+		KeyHash: auth.HashToken(rawKey),
+		Scopes:  scopes,
+	}
+	if req.ExpiresInDays > 0 {
+		expiresAt := time.Now().AddDate(0, 0, req.ExpiresInDays)
+		key.ExpiresAt = &expiresAt
+	}
+
+	if err := s.repo.CreateKey(key, nil); err != nil {
+		return nil, err
+	}
+
+	return &domain.CreateAPIKeyResponse{APIKey: *key, Key: rawKey}, nil
+}
+
+func (s *apiKeyService) RevokeKey(keyID uint, userID uint64) error {
+	return s.repo.RevokeKey(keyID, userID, nil)
+}
+
+func (s *apiKeyService) GetKeys(userID uint64) ([]domain.APIKey, error) {
+	keys, err := s.repo.GetUserKeys(userID)
+	if err != nil {
+		return nil, err
+	}
+	if keys == nil {
+		keys = []domain.APIKey{}
+	}
+	return keys, nil
+}
+
+func (s *apiKeyService) Authenticate(rawKey string, ip string) (uint64, []string, error) {
+	key, err := s.repo.GetKeyByHash(auth.HashToken(rawKey))
+	if err != nil {
+		return 0, nil, domain.ErrInvalidToken
+	}
+
+	now := time.Now()
+	if key.RevokedAt != nil || (key.ExpiresAt != nil && now.After(*key.ExpiresAt)) {
+		return 0, nil, domain.ErrInvalidToken
+	}
+
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) > apiKeyTouchInterval || key.LastUsedIP != ip {
+		if err := s.repo.TouchKey(key.ID, truncate(ip, 64), now); err != nil {
+			log.Printf("Error when trying to record API key usage: %v.\n", err)
+		}
+	}
+
+	return key.UserID, key.Scopes, nil
+}
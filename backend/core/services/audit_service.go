@@ -0,0 +1,97 @@
+package services
+
+import (
+	"encoding/json"
+	"trade-tracker/core/domain"
+	"trade-tracker/core/repositories"
+
+	"gorm.io/gorm"
+)
+
+type AuditService interface {
+	// Record must run inside the mutation's transaction so the change and its log commit together.
+	Record(entry AuditEntry, tx *gorm.DB) error
+	GetLogs(userID uint64, filter domain.AuditLogFilter) (*domain.AuditLogListResponse, error)
+}
+
+type AuditEntry struct {
+	Actor      domain.Actor
+	OwnerID    uint64
+	Action     string
+	EntityType string
+	EntityID   uint
+	Before     any
+	After      any
+}
+
+type auditService struct {
+	repo repositories.AuditRepository
+}
+
+func NewAuditService(repo repositories.AuditRepository) AuditService {
+	return &auditService{repo: repo}
+}
+
+func (s *auditService) Record(entry AuditEntry, tx *gorm.DB) error {
+	before, err := snapshot(entry.Before)
+	if err != nil {
+		return err
+	}
+	after, err := snapshot(entry.After)
+	if err != nil {
+		return err
+	}
+
+	ownerID := entry.OwnerID
+	if ownerID == 0 {
+		ownerID = entry.Actor.UserID
+	}
+
+	return s.repo.CreateLog(&domain.AuditLog{
+		ActorID:    entry.Actor.UserID,
+		OwnerID:    ownerID,
+		RequestID:  entry.Actor.RequestID,
+		Action:     entry.Action,
+		EntityType: entry.EntityType,
+		EntityID:   entry.EntityID,
+		Before:     before,
+		After:      after,
+	}, tx)
+}
+
+// snapshot encodes a value for a jsonb column; nil becomes JSON null.
+func snapshot(v any) (string, error) {
+	if v == nil {
+		return "null", nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+func (s *auditService) GetLogs(userID uint64, filter domain.AuditLogFilter) (*domain.AuditLogListResponse, error) {
+	page, limit := filter.Page, filter.Limit
+	if page < 1 {
+		page = 1
+	}
+	if limit <= 0 || limit > 100 {
+		limit = 50
+	}
+
+	logs, total, err := s.repo.GetLogs(userID, filter, (page-1)*limit, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &domain.AuditLogListResponse{Logs: []domain.AuditLogResponse{}, Total: total, Page: page, Limit: limit}
+	for _, l := range logs {
+		res.Logs = append(res.Logs, domain.AuditLogResponse{
+			AuditLog: l,
+			Before:   json.RawMessage(l.Before),
+			After:    json.RawMessage(l.After),
+		})
+	}
+	return res, nil
+}
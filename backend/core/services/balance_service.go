@@ -8,6 +8,7 @@ import (
 
 	"trade-tracker/core/domain"
 	"trade-tracker/core/repositories"
+	"trade-tracker/pkg/utils/format"
 
 	"gorm.io/gorm"
 
@@ -15,55 +16,109 @@ import (
 )
 
 type BalanceService interface {
-	CreateBalance(balance *domain.Balance, trx *gorm.DB) error
-	RemoveBalance(id uint64, userID uint64, trx *gorm.DB) error
-	AdjustBalance(userID uint64, req domain.BalanceUpdateReq) error
-	UpdateBalance(userID uint64, amount float64, assetType string, provider string, accountNo string, tx *gorm.DB) error
+	CreateBalance(actor domain.Actor, balance *domain.Balance, trx *gorm.DB) error
+	RemoveBalance(actor domain.Actor, id uint64, trx *gorm.DB) error
+	AdjustBalance(actor domain.Actor, req domain.BalanceUpdateReq) error
+	RecordDividend(actor domain.Actor, req domain.DividendReq) error
+	// UpdateBalance adds amount to userID's account on behalf of actor.
+	UpdateBalance(actor domain.Actor, userID uint64, amount float64, assetType string, provider string, accountNo string, tx *gorm.DB) error
 
 	GetBalanceByType(userID uint64, balanceType string, provider string, trx *gorm.DB) (float64, error)
 	GetBalances(userID uint64, trx *gorm.DB) (*domain.BalanceResponse, error)
 	GetAccountsByType(userID uint64, assetType string) ([]domain.AccountResponse, error)
 	GetProviderAccount(userID uint64, assetType string, provider string, accountNo string, trx *gorm.DB) (*domain.Balance, error)
-	MigrateBalances(userID uint64, provider string, accountNo string, tx *gorm.DB) error
+	MigrateBalances(actor domain.Actor, provider string, accountNo string, tx *gorm.DB) error
 }
 
 type balanceService struct {
 	repo        repositories.BalanceRepository
 	tranService TransactionService
+	access      AccountAccess
+	audit       AuditService
 }
 
-func NewBalanceService(repo repositories.BalanceRepository, tranService TransactionService) BalanceService {
-	return &balanceService{repo: repo, tranService: tranService}
+func NewBalanceService(repo repositories.BalanceRepository, tranService TransactionService, access AccountAccess, audit AuditService) BalanceService {
+	return &balanceService{repo: repo, tranService: tranService, access: access, audit: audit}
 }
 
-func (s *balanceService) CreateBalance(balance *domain.Balance, trx *gorm.DB) error {
-	return s.repo.CreateBalance(balance, trx)
+func (s *balanceService) record(actor domain.Actor, action string, before, after *domain.Balance, tx *gorm.DB) error {
+	entry := AuditEntry{
+		Actor:      actor,
+		Action:     action,
+		EntityType: domain.AuditEntityBalance,
+		Before:     before,
+		After:      after,
+	}
+	if after != nil {
+		entry.OwnerID, entry.EntityID = after.UserID, after.ID
+	} else {
+		entry.OwnerID, entry.EntityID = before.UserID, before.ID
+	}
+	return s.audit.Record(entry, tx)
 }
 
-func (s *balanceService) RemoveBalance(id uint64, userID uint64, trx *gorm.DB) error {
-	if balance, err := s.repo.GetBalance(id, trx); err != nil || balance.UserID != userID {
+func (s *balanceService) CreateBalance(actor domain.Actor, balance *domain.Balance, trx *gorm.DB) error {
+	if err := s.repo.CreateBalance(balance, trx); err != nil {
+		return err
+	}
+	return s.record(actor, domain.AuditCreate, nil, balance, trx)
+}
+
+func (s *balanceService) RemoveBalance(actor domain.Actor, id uint64, trx *gorm.DB) error {
+	balance, err := s.repo.GetBalance(id, trx)
+	if err != nil || !s.access.CanTrade(actor.UserID, balance.UserID, balance.Provider, balance.AccountNo) {
 		return domain.ErrMismatchInfo
 	}
-	return s.repo.RemoveBalance(id, trx)
+	if err := s.repo.RemoveBalance(id, trx); err != nil {
+		return err
+	}
+	return s.record(actor, domain.AuditDelete, balance, nil, trx)
 }
 
-func (s *balanceService) UpdateBalance(userID uint64, amount float64, assetType string, provider string, accountNo string, tx *gorm.DB) error {
+func (s *balanceService) UpdateBalance(actor domain.Actor, userID uint64, amount float64, assetType string, provider string, accountNo string, tx *gorm.DB) error {
 	if amount < 0 {
 		current, _ := s.repo.GetBalanceByType(userID, assetType, provider, tx)
 		if current < math.Abs(amount) {
 			return domain.ErrInsufficientBalance
 		}
 	}
-	return s.repo.UpdateBalance(&domain.Balance{
+
+	before, err := s.repo.GetProviderAccount(userID, assetType, provider, accountNo, tx)
+	if err != nil {
+		return err
+	}
+
+	if err := s.repo.UpdateBalance(&domain.Balance{
 		UserID:    userID,
 		Amount:    amount,
 		AssetType: assetType,
 		Provider:  provider,
 		AccountNo: accountNo,
-	}, tx)
+	}, tx); err != nil {
+		return err
+	}
+
+	after, err := s.repo.GetProviderAccount(userID, assetType, provider, accountNo, tx)
+	if err != nil {
+		return err
+	}
+
+	action := domain.AuditUpdate
+	if before == nil {
+		action = domain.AuditCreate
+	}
+	return s.record(actor, action, before, after, tx)
 }
 
-func (s *balanceService) AdjustBalance(userID uint64, req domain.BalanceUpdateReq) error {
+func (s *balanceService) AdjustBalance(actor domain.Actor, req domain.BalanceUpdateReq) error {
+	userID := actor.UserID
+	if req.OwnerID != 0 {
+		userID = req.OwnerID
+	}
+	if !s.access.CanTrade(actor.UserID, userID, req.Provider, req.AccountNo) {
+		return domain.ErrMismatchInfo
+	}
+
 	db := s.repo.GetDB()
 
 	return db.Transaction(func(tx *gorm.DB) error {
@@ -76,23 +131,7 @@ func (s *balanceService) AdjustBalance(userID uint64, req domain.BalanceUpdateRe
 			bal = existingAcc.Amount
 		}
 
-		now := time.Now()
-		parsedDate, err := time.Parse("2006-01-02", req.Date)
-		var finalDate time.Time
-
-		if err == nil {
-			if parsedDate.Format("2006-01-02") == now.Format("2006-01-02") {
-				finalDate = now
-			} else {
-				finalDate = time.Date(
-					parsedDate.Year(), parsedDate.Month(), parsedDate.Day(),
-					now.Hour(), now.Minute(), now.Second(), now.Nanosecond(),
-					now.Location(),
-				)
-			}
-		} else {
-			finalDate = now
-		}
+		finalDate := activityDate(req.Date, time.Now())
 
 		var logged float64
 		tType := "cashflow"
@@ -123,7 +162,7 @@ func (s *balanceService) AdjustBalance(userID uint64, req domain.BalanceUpdateRe
 		}
 
 		if data == nil {
-			err := s.repo.CreateBalance(&domain.Balance{
+			err := s.CreateBalance(actor, &domain.Balance{
 				UserID:    userID,
 				Amount:    req.Amount,
 				AssetType: req.AssetType,
@@ -135,7 +174,7 @@ func (s *balanceService) AdjustBalance(userID uint64, req domain.BalanceUpdateRe
 				return err
 			}
 		} else {
-			if err := s.UpdateBalance(userID, logged, req.AssetType, req.Provider, req.AccountNo, tx); err != nil {
+			if err := s.UpdateBalance(actor, userID, logged, req.AssetType, req.Provider, req.AccountNo, tx); err != nil {
 				return err
 			}
 		}
@@ -153,20 +192,95 @@ func (s *balanceService) AdjustBalance(userID uint64, req domain.BalanceUpdateRe
 		}
 
 		return s.tranService.LogActivity(LogActivityParams{
+			Actor: actor,
 			Position: &domain.Position{
 				OwnerID: userID,
 				Ticker:  req.BankSource,
 			},
-			Action:    tType,
-			Title:     title,
-			Price:     req.Amount,
-			Fee:       req.Fee,
-			Quantity:  0,
-			BasePrice: 0,
-			Notes:     note,
-			Date:      finalDate,
-			Provider:  req.Provider,
-			AccountNo: req.AccountNo,
+			Action:        tType,
+			Title:         title,
+			Price:         req.Amount,
+			Fee:           req.Fee,
+			Quantity:      0,
+			BasePrice:     0,
+			Notes:         note,
+			Date:          finalDate,
+			Provider:      req.Provider,
+			AccountNo:     req.AccountNo,
+			AssetType:     req.AssetType,
+			BalanceChange: logged,
+		}, tx)
+	})
+}
+
+// activityDate places a YYYY-MM-DD date at the current time of day, falling back to now.
+func activityDate(date string, now time.Time) time.Time {
+	parsedDate, err := time.Parse("2006-01-02", date)
+	if err != nil || parsedDate.Format("2006-01-02") == now.Format("2006-01-02") {
+		return now
+	}
+	return time.Date(
+		parsedDate.Year(), parsedDate.Month(), parsedDate.Day(),
+		now.Hour(), now.Minute(), now.Second(), now.Nanosecond(),
+		now.Location(),
+	)
+}
+
+func (s *balanceService) RecordDividend(actor domain.Actor, req domain.DividendReq) error {
+	userID := actor.UserID
+	if req.OwnerID != 0 {
+		userID = req.OwnerID
+	}
+	if !s.access.CanTrade(actor.UserID, userID, req.Provider, req.AccountNo) {
+		return domain.ErrMismatchInfo
+	}
+
+	ticker := strings.ToUpper(strings.TrimSpace(req.Ticker))
+	net := req.Amount - req.Tax
+
+	db := s.repo.GetDB()
+	return db.Transaction(func(tx *gorm.DB) error {
+		existing, err := s.repo.GetProviderAccount(userID, "stock_balance", req.Provider, req.AccountNo, tx)
+		if err != nil {
+			return err
+		}
+
+		if existing == nil {
+			err = s.CreateBalance(actor, &domain.Balance{
+				UserID:    userID,
+				Amount:    net,
+				AssetType: "stock_balance",
+				Provider:  req.Provider,
+				AccountNo: req.AccountNo,
+			}, tx)
+		} else {
+			err = s.UpdateBalance(actor, userID, net, "stock_balance", req.Provider, req.AccountNo, tx)
+		}
+		if err != nil {
+			return err
+		}
+
+		note := bluemonday.StrictPolicy().Sanitize(req.Note)
+		if note == "" {
+			note = fmt.Sprintf("Dividend of %s, %s withheld.", ticker, format.FormatNumber(req.Tax))
+		}
+
+		return s.tranService.LogActivity(LogActivityParams{
+			Actor: actor,
+			Position: &domain.Position{
+				OwnerID: userID,
+				Ticker:  ticker,
+			},
+			Action:        "dividend",
+			Title:         "Dividend " + ticker,
+			Price:         req.Amount,
+			Fee:           req.Tax,
+			Notes:         note,
+			Date:          activityDate(req.Date, time.Now()),
+			Provider:      req.Provider,
+			AccountNo:     req.AccountNo,
+			AssetType:     "stock_balance",
+			BalanceChange: net,
 		}, tx)
 	})
 }
@@ -207,7 +321,8 @@ func (s *balanceService) GetProviderAccount(userID uint64, assetType string, pro
 	return s.repo.GetProviderAccount(userID, assetType, provider, accountNo, trx)
 }
 
-func (s *balanceService) MigrateBalances(userID uint64, provider string, accountNo string, tx *gorm.DB) error {
+func (s *balanceService) MigrateBalances(actor domain.Actor, provider string, accountNo string, tx *gorm.DB) error {
+	userID := actor.UserID
 	balances, err := s.repo.GetBalances(userID, tx)
 	if err != nil {
 		return err
@@ -222,19 +337,30 @@ func (s *balanceService) MigrateBalances(userID uint64, provider string, account
 			return err
 		}
 		if targetBal != nil {
+			before := *targetBal
 			targetBal.Amount += legacyBal.Amount
 			if err := s.repo.SaveBalance(targetBal, tx); err != nil {
 				return err
 			}
+			if err := s.record(actor, domain.AuditMigrate, &before, targetBal, tx); err != nil {
+				return err
+			}
 			if err := s.repo.RemoveBalance(uint64(legacyBal.ID), tx); err != nil {
 				return err
 			}
+			if err := s.record(actor, domain.AuditDelete, &legacyBal, nil, tx); err != nil {
+				return err
+			}
 		} else {
+			before := legacyBal
 			legacyBal.Provider = provider
 			legacyBal.AccountNo = accountNo
 			if err := s.repo.SaveBalance(&legacyBal, tx); err != nil {
 				return err
 			}
+			if err := s.record(actor, domain.AuditMigrate, &before, &legacyBal, tx); err != nil {
+				return err
+			}
 		}
 	}
 	return nil
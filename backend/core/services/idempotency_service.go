@@ -0,0 +1,104 @@
+package services
+
+import (
+	"errors"
+	"os"
+	"time"
+	"trade-tracker/core/domain"
+	"trade-tracker/core/repositories"
+
+	"gorm.io/gorm"
+)
+
+const (
+	defaultIdempotencyTTL = 24 * time.Hour
+	// idempotencyLockTimeout frees keys whose first request never finished, e.g. after a crash.
+	idempotencyLockTimeout = time.Minute
+)
+
+type IdempotencyService interface {
+	// Claim reserves key for the request. When the key is already taken it returns the
+	// existing record and false; the caller compares hashes and replays or rejects.
+	Claim(userID uint64, key, requestHash string) (*domain.IdempotencyRecord, bool, error)
+	Complete(id uint, status int, contentType string, body []byte) error
+	Release(id uint) error
+
+	// UseWorkerNonce records a worker nonce until expiresAt and reports false when it was
+	// already used.
+	UseWorkerNonce(nonce string, expiresAt time.Time) (bool, error)
+}
+
+type idempotencyService struct {
+	repo repositories.IdempotencyRepository
+	ttl  time.Duration
+}
+
+func NewIdempotencyService(repo repositories.IdempotencyRepository) IdempotencyService {
+	ttl := defaultIdempotencyTTL
+	if d, err := time.ParseDuration(os.Getenv("IDEMPOTENCY_TTL")); err == nil && d > 0 {
+		ttl = d
+	}
+	return &idempotencyService{repo: repo, ttl: ttl}
+}
+
+func (s *idempotencyService) Claim(userID uint64, key, requestHash string) (*domain.IdempotencyRecord, bool, error) {
+	// Two rounds: the second one runs after a stale record has been removed.
+	for range 2 {
+		now := time.Now()
+		record := &domain.IdempotencyRecord{
+			CreatedAt:   now,
+			ExpiresAt:   now.Add(s.ttl),
+			UserID:      userID,
+			Key:         key,
+			RequestHash: requestHash,
+		}
+		created, err := s.repo.CreateRecord(record)
+		if err != nil {
+			return nil, false, err
+		}
+		if created {
+			return record, true, nil
+		}
+
+		existing, err := s.repo.GetRecord(userID, key)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, false, err
+		}
+
+		expired := now.After(existing.ExpiresAt)
+		abandoned := existing.StatusCode == 0 && now.Sub(existing.CreatedAt) > idempotencyLockTimeout
+		if !expired && !abandoned {
+			return existing, false, nil
+		}
+		if err := s.repo.DeleteRecord(existing.ID); err != nil {
+			return nil, false, err
+		}
+	}
+	return nil, false, domain.ErrIdempotencyInProgress
+}
+
+func (s *idempotencyService) Complete(id uint, status int, contentType string, body []byte) error {
+	return s.repo.CompleteRecord(id, status, contentType, body)
+}
+
+func (s *idempotencyService) Release(id uint) error {
+	return s.repo.DeleteRecord(id)
+}
+
+func (s *idempotencyService) UseWorkerNonce(nonce string, expiresAt time.Time) (bool, error) {
+	// Two rounds: an expired row the purge job has not removed yet does not count as used.
+	for range 2 {
+		created, err := s.repo.CreateWorkerNonce(&domain.WorkerNonce{Nonce: nonce, ExpiresAt: expiresAt})
+		if err != nil || created {
+			return created, err
+		}
+		deleted, err := s.repo.DeleteExpiredWorkerNonces(time.Now())
+		if err != nil || deleted == 0 {
+			return false, err
+		}
+	}
+	return false, nil
+}
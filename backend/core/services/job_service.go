@@ -0,0 +1,46 @@
+package services
+
+import (
+	"time"
+
+	"trade-tracker/core/domain"
+	"trade-tracker/core/repositories"
+	"trade-tracker/core/worker"
+)
+
+type JobService interface {
+	GetJobRuns(jobName string, limit int) ([]domain.JobRun, error)
+	GetJobStatus() []domain.JobStatusResponse
+	RunJob(name string) error
+	TriggerJob(name string, now time.Time) (*domain.JobRun, error)
+}
+
+type jobService struct {
+	repo      repositories.JobRepository
+	scheduler *worker.Scheduler
+}
+
+// NewJobService takes the scheduler holding every job, whether or not it was started.
+func NewJobService(repo repositories.JobRepository, scheduler *worker.Scheduler) JobService {
+	return &jobService{repo: repo, scheduler: scheduler}
+}
+
+func (s *jobService) GetJobRuns(jobName string, limit int) ([]domain.JobRun, error) {
+	if limit <= 0 || limit > 200 {
+		limit = 50
+	}
+	return s.repo.GetRuns(jobName, limit)
+}
+
+func (s *jobService) GetJobStatus() []domain.JobStatusResponse {
+	return s.scheduler.Status()
+}
+
+func (s *jobService) RunJob(name string) error {
+	return s.scheduler.RunNow(name)
+}
+
+// TriggerJob runs a job for an external caller and returns the recorded run.
+func (s *jobService) TriggerJob(name string, now time.Time) (*domain.JobRun, error) {
+	return s.scheduler.Trigger(name, now)
+}
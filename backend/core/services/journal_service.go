@@ -0,0 +1,342 @@
+package services
+
+import (
+	"slices"
+	"sort"
+	"strings"
+
+	"trade-tracker/core/domain"
+	"trade-tracker/core/repositories"
+
+	"github.com/lib/pq"
+	"gorm.io/gorm"
+)
+
+type JournalService interface {
+	AddEntry(actor domain.Actor, req domain.JournalRequest) (*domain.JournalEntry, error)
+	UpdateEntry(actor domain.Actor, id uint, req domain.JournalRequest) (*domain.JournalEntry, error)
+	RemoveEntry(actor domain.Actor, id uint) error
+
+	GetEntries(userID uint64) ([]domain.JournalEntry, error)
+	GetTradeJournal(userID uint64, transactionID uint) (*domain.TradeJournalResponse, error)
+	GetPositionJournal(userID uint64, positionID uint) (*domain.PositionJournalResponse, error)
+	// AnalyzeJournal groups entries by setup or tag and totals the realized PnL of their linked sells.
+	AnalyzeJournal(userID uint64, groupBy string) ([]domain.JournalStat, error)
+}
+
+type journalService struct {
+	repo     repositories.JournalRepository
+	tranRepo repositories.TransactionRepository
+	posRepo  repositories.PositionRepository
+	audit    AuditService
+}
+
+func NewJournalService(repo repositories.JournalRepository, tranRepo repositories.TransactionRepository, posRepo repositories.PositionRepository, audit AuditService) JournalService {
+	return &journalService{repo: repo, tranRepo: tranRepo, posRepo: posRepo, audit: audit}
+}
+
+func normalizeTags(tags []string) pq.StringArray {
+	result := pq.StringArray{}
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag != "" && !slices.Contains(result, tag) {
+			result = append(result, tag)
+		}
+	}
+	return result
+}
+
+func uniqueIDs(ids []uint) []uint {
+	var result []uint
+	for _, id := range ids {
+		if !slices.Contains(result, id) {
+			result = append(result, id)
+		}
+	}
+	return result
+}
+
+func toInt64Array(ids []uint) pq.Int64Array {
+	result := pq.Int64Array{}
+	for _, id := range ids {
+		result = append(result, int64(id))
+	}
+	return result
+}
+
+// newLinks returns the IDs in ids that are not already in linked.
+func newLinks(ids []uint, linked pq.Int64Array) []uint {
+	var result []uint
+	for _, id := range ids {
+		if !slices.Contains(linked, int64(id)) {
+			result = append(result, id)
+		}
+	}
+	return result
+}
+
+// checkLinks makes sure every transaction and position ID belongs to the user.
+func (s *journalService) checkLinks(userID uint64, transactionIDs, positionIDs []uint, tx *gorm.DB) error {
+	if len(transactionIDs) > 0 {
+		found, err := s.tranRepo.GetTransactionsByIDs(userID, transactionIDs, tx)
+		if err != nil {
+			return err
+		}
+		if len(found) != len(transactionIDs) {
+			return domain.ErrJournalTradeAbsent
+		}
+	}
+	if len(positionIDs) > 0 {
+		found, err := s.posRepo.GetPositionsByIDs(userID, positionIDs, tx)
+		if err != nil {
+			return err
+		}
+		if len(found) != len(positionIDs) {
+			return domain.ErrJournalTradeAbsent
+		}
+	}
+	return nil
+}
+
+func applyJournalRequest(entry *domain.JournalEntry, req domain.JournalRequest) {
+	entry.Setup = strings.TrimSpace(req.Setup)
+	entry.Thesis = req.Thesis
+	entry.Emotion = strings.TrimSpace(req.Emotion)
+	entry.Mistakes = req.Mistakes
+	entry.Rating = req.Rating
+	entry.Tags = normalizeTags(req.Tags)
+	entry.TransactionIDs = toInt64Array(uniqueIDs(req.TransactionIDs))
+	entry.PositionIDs = toInt64Array(uniqueIDs(req.PositionIDs))
+}
+
+func (s *journalService) AddEntry(actor domain.Actor, req domain.JournalRequest) (*domain.JournalEntry, error) {
+	if len(req.TransactionIDs) == 0 && len(req.PositionIDs) == 0 {
+		return nil, domain.ErrJournalWithoutTrade
+	}
+
+	entry := &domain.JournalEntry{UserID: actor.UserID}
+	applyJournalRequest(entry, req)
+
+	db := s.repo.GetDB()
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := s.checkLinks(actor.UserID, uniqueIDs(req.TransactionIDs), uniqueIDs(req.PositionIDs), tx); err != nil {
+			return err
+		}
+		if err := s.repo.AddEntry(entry, tx); err != nil {
+			return err
+		}
+		return s.audit.Record(AuditEntry{
+			Actor:      actor,
+			OwnerID:    entry.UserID,
+			Action:     domain.AuditCreate,
+			EntityType: domain.AuditEntityJournal,
+			EntityID:   entry.ID,
+			After:      entry,
+		}, tx)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// UpdateEntry only checks newly added links, so an entry keeps its link to a position that has since closed.
+func (s *journalService) UpdateEntry(actor domain.Actor, id uint, req domain.JournalRequest) (*domain.JournalEntry, error) {
+	if len(req.TransactionIDs) == 0 && len(req.PositionIDs) == 0 {
+		return nil, domain.ErrJournalWithoutTrade
+	}
+
+	var entry *domain.JournalEntry
+	db := s.repo.GetDB()
+	err := db.Transaction(func(tx *gorm.DB) error {
+		current, err := s.repo.GetEntry(id, actor.UserID, tx)
+		if err != nil {
+			return err
+		}
+		before := *current
+
+		added := newLinks(uniqueIDs(req.TransactionIDs), current.TransactionIDs)
+		addedPositions := newLinks(uniqueIDs(req.PositionIDs), current.PositionIDs)
+		if err := s.checkLinks(actor.UserID, added, addedPositions, tx); err != nil {
+			return err
+		}
+
+		applyJournalRequest(current, req)
+		if err := s.repo.UpdateEntry(current, tx); err != nil {
+			return err
+		}
+		entry = current
+		return s.audit.Record(AuditEntry{
+			Actor:      actor,
+			OwnerID:    current.UserID,
+			Action:     domain.AuditUpdate,
+			EntityType: domain.AuditEntityJournal,
+			EntityID:   current.ID,
+			Before:     before,
+			After:      current,
+		}, tx)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+func (s *journalService) RemoveEntry(actor domain.Actor, id uint) error {
+	db := s.repo.GetDB()
+	return db.Transaction(func(tx *gorm.DB) error {
+		entry, err := s.repo.GetEntry(id, actor.UserID, tx)
+		if err != nil {
+			return err
+		}
+		if err := s.repo.RemoveEntry(id, actor.UserID, tx); err != nil {
+			return err
+		}
+		return s.audit.Record(AuditEntry{
+			Actor:      actor,
+			OwnerID:    entry.UserID,
+			Action:     domain.AuditDelete,
+			EntityType: domain.AuditEntityJournal,
+			EntityID:   id,
+			Before:     entry,
+		}, tx)
+	})
+}
+
+func (s *journalService) GetEntries(userID uint64) ([]domain.JournalEntry, error) {
+	return s.repo.GetEntries(userID)
+}
+
+func (s *journalService) GetTradeJournal(userID uint64, transactionID uint) (*domain.TradeJournalResponse, error) {
+	trx, err := s.tranRepo.GetTransactionByID(transactionID, nil)
+	if err != nil || trx.OwnerID != userID {
+		return nil, domain.ErrItemNotFound
+	}
+
+	entries, err := s.repo.GetEntriesByTransaction(userID, transactionID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &domain.TradeJournalResponse{
+		Transaction: domain.TransactionResponse{Transaction: *trx},
+		Journal:     entries,
+	}
+	if responses := toTransactionResponses([]domain.Transaction{*trx}); len(responses) == 1 {
+		result.Transaction = responses[0]
+	}
+	return result, nil
+}
+
+func (s *journalService) GetPositionJournal(userID uint64, positionID uint) (*domain.PositionJournalResponse, error) {
+	entries, err := s.repo.GetEntriesByPosition(userID, positionID)
+	if err != nil {
+		return nil, err
+	}
+
+	positions, err := s.posRepo.GetPositionsByIDs(userID, []uint{positionID}, nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(positions) == 0 && len(entries) == 0 {
+		return nil, domain.ErrItemNotFound
+	}
+
+	result := &domain.PositionJournalResponse{Journal: entries}
+	if len(positions) == 1 {
+		result.Position = &positions[0]
+	}
+	return result, nil
+}
+
+type journalGroup struct {
+	stat      domain.JournalStat
+	ratingSum int
+	rated     int
+	counted   map[int64]bool
+}
+
+func (s *journalService) AnalyzeJournal(userID uint64, groupBy string) ([]domain.JournalStat, error) {
+	groupBy = strings.ToLower(strings.TrimSpace(groupBy))
+	if groupBy == "" {
+		groupBy = domain.JournalGroupSetup
+	}
+	if groupBy != domain.JournalGroupSetup && groupBy != domain.JournalGroupTag {
+		return nil, domain.ErrInvalidJournalGroup
+	}
+
+	entries, err := s.repo.GetEntries(userID)
+	if err != nil {
+		return nil, err
+	}
+	trans, err := s.tranRepo.GetTransactions(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	sells := map[int64]float64{}
+	for _, t := range toTransactionResponses(trans) {
+		if t.TransactionType == "sell" {
+			sells[int64(t.ID)] = t.RealizedPnl
+		}
+	}
+
+	groups := map[string]*journalGroup{}
+	for _, entry := range entries {
+		keys := []string{entry.Setup}
+		if groupBy == domain.JournalGroupTag {
+			keys = entry.Tags
+			if len(keys) == 0 {
+				keys = []string{""}
+			}
+		}
+
+		for _, key := range keys {
+			g, ok := groups[key]
+			if !ok {
+				g = &journalGroup{stat: domain.JournalStat{Key: key}, counted: map[int64]bool{}}
+				groups[key] = g
+			}
+
+			g.stat.Entries++
+			if entry.Rating > 0 {
+				g.ratingSum += entry.Rating
+				g.rated++
+			}
+
+			for _, id := range entry.TransactionIDs {
+				pnl, isSell := sells[id]
+				if !isSell || g.counted[id] {
+					continue
+				}
+				g.counted[id] = true
+				g.stat.Trades++
+				g.stat.RealizedPnL += pnl
+				if pnl > 0 {
+					g.stat.Wins++
+				} else if pnl < 0 {
+					g.stat.Losses++
+				}
+			}
+		}
+	}
+
+	stats := make([]domain.JournalStat, 0, len(groups))
+	for _, g := range groups {
+		if g.stat.Trades > 0 {
+			g.stat.WinRate = float64(g.stat.Wins) / float64(g.stat.Trades) * 100
+			g.stat.AvgPnL = g.stat.RealizedPnL / float64(g.stat.Trades)
+		}
+		if g.rated > 0 {
+			g.stat.AvgRating = float64(g.ratingSum) / float64(g.rated)
+		}
+		stats = append(stats, g.stat)
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].RealizedPnL != stats[j].RealizedPnL {
+			return stats[i].RealizedPnL > stats[j].RealizedPnL
+		}
+		return stats[i].Key < stats[j].Key
+	})
+	return stats, nil
+}
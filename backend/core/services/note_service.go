@@ -1,73 +1,509 @@
 package services
 
 import (
+	"crypto/rand"
+	"encoding/hex"
+	"log"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+
 	"trade-tracker/core/domain"
+	"trade-tracker/core/integrations/storage"
 	repository "trade-tracker/core/repositories"
+	"trade-tracker/pkg/utils/imaging"
 
 	"gorm.io/gorm"
 )
 
+const (
+	MaxNoteImageSize  = 3 << 20
+	maxImagesPerNote  = 5
+	noteThumbnailSize = 320
+)
+
+var imageKeyPattern = regexp.MustCompile(`^[0-9a-f]{32}(_thumb)?\.(jpg|png|gif)$`)
+
 type NoteService interface {
-	AddNote(note *domain.Note) error
-	RemoveNote(id uint, userID uint64) error
-	UpdateNote(note *domain.Note) error
+	AddNote(actor domain.Actor, note *domain.Note) error
+	// RemoveNote moves a note to the trash, where it can be restored until the retention ends.
+	RemoveNote(actor domain.Actor, id uint) error
+	RestoreNote(actor domain.Actor, id uint) error
+	// PurgeNote permanently deletes a note in the trash with its images.
+	PurgeNote(actor domain.Actor, id uint) error
+	GetTrash(userID uint64, page, limit int) (*domain.NoteListResponse, error)
+	// PurgeTrash permanently deletes notes trashed before the given time.
+	PurgeTrash(before time.Time) (int, error)
+	UpdateNote(actor domain.Actor, note *domain.Note) error
 
 	GetNotes(userID uint64) ([]domain.NoteResponse, error)
+	SearchNotes(userID uint64, filter domain.NoteFilter) (*domain.NoteListResponse, error)
+
+	AddImage(actor domain.Actor, noteID uint, data []byte) (*domain.NoteImageResponse, error)
+	RemoveImage(actor domain.Actor, noteID uint, imageID uint) error
+	// RemoveUserImages deletes the image rows of a user inside tx, for account deletion, and
+	// returns them. Pass them to DeleteImageObjects once tx has committed.
+	RemoveUserImages(userID uint64, tx *gorm.DB) ([]domain.NoteImage, error)
+	DeleteImageObjects(images []domain.NoteImage)
+	// GetImageObject returns a stored image or thumbnail and its content type.
+	GetImageObject(key string) ([]byte, string, error)
 }
 
 type noteService struct {
-	repo repository.NoteRepository
+	repo  repository.NoteRepository
+	audit AuditService
+	store storage.Storage
 }
 
-func NewNoteService(repo repository.NoteRepository) NoteService {
-	return &noteService{repo: repo}
+func NewNoteService(repo repository.NoteRepository, audit AuditService, store storage.Storage) NoteService {
+	return &noteService{repo: repo, audit: audit, store: store}
 }
 
-func (s *noteService) AddNote(note *domain.Note) error {
+func (s *noteService) AddNote(actor domain.Actor, note *domain.Note) error {
+	note.Tags = normalizeTags(note.Tags)
 	db := s.repo.GetDB()
 	return db.Transaction(func(tx *gorm.DB) error {
-		return s.repo.AddNote(note, tx)
+		if err := s.repo.AddNote(note, tx); err != nil {
+			return err
+		}
+		return s.audit.Record(AuditEntry{
+			Actor:      actor,
+			OwnerID:    note.UserID,
+			Action:     domain.AuditCreate,
+			EntityType: domain.AuditEntityNote,
+			EntityID:   note.ID,
+			After:      note,
+		}, tx)
 	})
 }
 
-func (s *noteService) RemoveNote(id uint, userID uint64) error {
+func (s *noteService) RemoveNote(actor domain.Actor, id uint) error {
 	db := s.repo.GetDB()
 	return db.Transaction(func(tx *gorm.DB) error {
-		if note, err := s.repo.GetNote(id, tx); err != nil || note.UserID != userID {
+		note, err := s.repo.GetNote(id, tx)
+		if err != nil || note.UserID != actor.UserID {
 			return domain.ErrMismatchInfo
 		}
-		return s.repo.RemoveNote(id, tx)
+		if err := s.repo.RemoveNote(id, tx); err != nil {
+			return err
+		}
+		return s.audit.Record(AuditEntry{
+			Actor:      actor,
+			OwnerID:    note.UserID,
+			Action:     domain.AuditDelete,
+			EntityType: domain.AuditEntityNote,
+			EntityID:   id,
+			Before:     note,
+		}, tx)
 	})
 }
 
-func (s *noteService) UpdateNote(note *domain.Note) error {
+func (s *noteService) RestoreNote(actor domain.Actor, id uint) error {
 	db := s.repo.GetDB()
 	return db.Transaction(func(tx *gorm.DB) error {
-		if note2, err := s.repo.GetNote(note.ID, tx); err != nil || note2.UserID != note.UserID {
+		note, err := s.repo.GetDeletedNote(id, tx)
+		if err != nil || note.UserID != actor.UserID {
+			return domain.ErrItemNotFound
+		}
+		if err := s.repo.RestoreNote(id, tx); err != nil {
+			return err
+		}
+		return s.audit.Record(AuditEntry{
+			Actor:      actor,
+			OwnerID:    note.UserID,
+			Action:     domain.AuditRestore,
+			EntityType: domain.AuditEntityNote,
+			EntityID:   id,
+			After:      note,
+		}, tx)
+	})
+}
+
+// purgeNote removes the note and its image rows; the caller deletes the returned images' objects
+// after the commit, so a failed purge never leaves rows pointing at missing files.
+func (s *noteService) purgeNote(note *domain.Note, tx *gorm.DB) ([]domain.NoteImage, error) {
+	images, err := s.repo.RemoveNoteImages(note.ID, tx)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.PurgeNote(note.ID, tx); err != nil {
+		return nil, err
+	}
+	return images, nil
+}
+
+func (s *noteService) PurgeNote(actor domain.Actor, id uint) error {
+	var images []domain.NoteImage
+	db := s.repo.GetDB()
+	err := db.Transaction(func(tx *gorm.DB) error {
+		note, err := s.repo.GetDeletedNote(id, tx)
+		if err != nil || note.UserID != actor.UserID {
+			return domain.ErrItemNotFound
+		}
+		if images, err = s.purgeNote(note, tx); err != nil {
+			return err
+		}
+		return s.audit.Record(AuditEntry{
+			Actor:      actor,
+			OwnerID:    note.UserID,
+			Action:     domain.AuditPurge,
+			EntityType: domain.AuditEntityNote,
+			EntityID:   id,
+			Before:     note,
+		}, tx)
+	})
+	if err != nil {
+		return err
+	}
+
+	s.deleteObjects(images)
+	return nil
+}
+
+func (s *noteService) GetTrash(userID uint64, page, limit int) (*domain.NoteListResponse, error) {
+	if page < 1 {
+		page = 1
+	}
+	if limit <= 0 || limit > 100 {
+		limit = 50
+	}
+
+	notes, total, err := s.repo.GetDeletedNotes(userID, (page-1)*limit, limit)
+	if err != nil {
+		return nil, err
+	}
+	responses, err := s.toNoteResponses(notes)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &domain.NoteListResponse{Notes: responses, Total: total, Page: page, Limit: limit}
+	if res.Notes == nil {
+		res.Notes = []domain.NoteResponse{}
+	}
+	return res, nil
+}
+
+// PurgeTrash works in batches so one run never holds a long transaction.
+func (s *noteService) PurgeTrash(before time.Time) (int, error) {
+	purged := 0
+	for {
+		notes, err := s.repo.GetExpiredNotes(before, 100)
+		if err != nil || len(notes) == 0 {
+			return purged, err
+		}
+
+		for i := range notes {
+			var images []domain.NoteImage
+			err := s.repo.GetDB().Transaction(func(tx *gorm.DB) error {
+				var err error
+				images, err = s.purgeNote(&notes[i], tx)
+				return err
+			})
+			if err != nil {
+				return purged, err
+			}
+			s.deleteObjects(images)
+			purged++
+		}
+	}
+}
+
+func (s *noteService) UpdateNote(actor domain.Actor, note *domain.Note) error {
+	note.Tags = normalizeTags(note.Tags)
+	db := s.repo.GetDB()
+	return db.Transaction(func(tx *gorm.DB) error {
+		before, err := s.repo.GetNote(note.ID, tx)
+		if err != nil || before.UserID != note.UserID {
 			return domain.ErrMismatchInfo
 		}
-		return s.repo.UpdateNote(note, tx)
+		if err := s.repo.UpdateNote(note, tx); err != nil {
+			return err
+		}
+		return s.audit.Record(AuditEntry{
+			Actor:      actor,
+			OwnerID:    note.UserID,
+			Action:     domain.AuditUpdate,
+			EntityType: domain.AuditEntityNote,
+			EntityID:   note.ID,
+			Before:     before,
+			After:      note,
+		}, tx)
 	})
 }
 
 func (s *noteService) GetNotes(userID uint64) ([]domain.NoteResponse, error) {
-	var myNotes []domain.NoteResponse
-
 	notes, err := s.repo.GetNotes(userID)
 	if err != nil {
 		return nil, err
 	}
+	return s.toNoteResponses(notes)
+}
+
+func parseNoteSearch(filter domain.NoteFilter, now time.Time) (domain.NoteSearch, error) {
+	search := domain.NoteSearch{
+		Query:    strings.TrimSpace(filter.Query),
+		Category: strings.TrimSpace(filter.Category),
+		Tags:     normalizeTags(strings.Split(filter.Tags, ",")),
+	}
+
+	var err error
+	if search.From, search.To, err = parseDateRange(filter.From, filter.To, now); err != nil {
+		return search, err
+	}
+
+	search.Sort = strings.ToLower(strings.TrimSpace(filter.Sort))
+	switch search.Sort {
+	case "":
+		search.Sort = domain.NoteSortUpdated
+		if search.Query != "" {
+			search.Sort = domain.NoteSortRelevance
+		}
+	case domain.NoteSortUpdated, domain.NoteSortCreated, domain.NoteSortTitle:
+	case domain.NoteSortRelevance:
+		if search.Query == "" {
+			search.Sort = domain.NoteSortUpdated
+		}
+	default:
+		return search, domain.ErrInvalidNoteSort
+	}
+
+	switch strings.ToLower(strings.TrimSpace(filter.Order)) {
+	case "":
+		search.Desc = search.Sort != domain.NoteSortTitle
+	case "asc":
+		search.Desc = false
+	case "desc":
+		search.Desc = true
+	default:
+		return search, domain.ErrInvalidNoteSort
+	}
+	return search, nil
+}
+
+func (s *noteService) SearchNotes(userID uint64, filter domain.NoteFilter) (*domain.NoteListResponse, error) {
+	search, err := parseNoteSearch(filter, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	page, limit := filter.Page, filter.Limit
+	if page < 1 {
+		page = 1
+	}
+	if limit <= 0 || limit > 100 {
+		limit = 50
+	}
+
+	notes, total, err := s.repo.SearchNotes(userID, search, (page-1)*limit, limit)
+	if err != nil {
+		return nil, err
+	}
+	responses, err := s.toNoteResponses(notes)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &domain.NoteListResponse{Notes: responses, Total: total, Page: page, Limit: limit}
+	if res.Notes == nil {
+		res.Notes = []domain.NoteResponse{}
+	}
+	return res, nil
+}
+
+func (s *noteService) toNoteResponses(notes []domain.Note) ([]domain.NoteResponse, error) {
+	var myNotes []domain.NoteResponse
+
+	noteIDs := make([]uint, 0, len(notes))
+	for _, note := range notes {
+		noteIDs = append(noteIDs, note.ID)
+	}
+	images := map[uint][]domain.NoteImageResponse{}
+	if len(noteIDs) > 0 {
+		stored, err := s.repo.GetNoteImages(noteIDs, nil)
+		if err != nil {
+			return nil, err
+		}
+		for _, image := range stored {
+			images[image.NoteID] = append(images[image.NoteID], s.toImageResponse(image))
+		}
+	}
 
 	for _, note := range notes {
 		myNotes = append(myNotes, domain.NoteResponse{
 			ID:          note.BaseModel.ID,
+			CreatedAt:   note.CreatedAt,
 			UpdatedAt:   note.UpdatedAt,
 			Title:       note.Title,
 			Description: note.Description,
 			Category:    note.Category,
+			Tags:        note.Tags,
 			ImageURL:    note.ImageURL,
+			Images:      images[note.ID],
+			DeletedAt:   deletedAt(note.DeletedAt),
 		})
 	}
 
 	return myNotes, nil
 }
+
+func (s *noteService) toImageResponse(image domain.NoteImage) domain.NoteImageResponse {
+	return domain.NoteImageResponse{
+		ID:           image.ID,
+		URL:          s.store.URL(image.Key),
+		ThumbnailURL: s.store.URL(image.ThumbnailKey),
+		ContentType:  image.ContentType,
+		Size:         image.Size,
+		Width:        image.Width,
+		Height:       image.Height,
+	}
+}
+
+func (s *noteService) deleteObjects(images []domain.NoteImage) {
+	for _, image := range images {
+		for _, key := range []string{image.Key, image.ThumbnailKey} {
+			if err := s.store.Delete(key); err != nil {
+				log.Printf("Error when trying to delete image object %s: %v.\n", key, err)
+			}
+		}
+	}
+}
+
+func newImageKey() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// AddImage validates the upload, stores it with a thumbnail and links it to the note.
+// The objects are written first and removed again if the database insert fails.
+func (s *noteService) AddImage(actor domain.Actor, noteID uint, data []byte) (*domain.NoteImageResponse, error) {
+	if len(data) > MaxNoteImageSize {
+		return nil, domain.ErrImageTooLarge
+	}
+	info, err := imaging.Inspect(data)
+	if err == imaging.ErrTooManyPixels {
+		return nil, domain.ErrImageTooLarge
+	}
+	if err != nil {
+		return nil, domain.ErrUnsupportedImage
+	}
+
+	note, err := s.repo.GetNote(noteID, nil)
+	if err != nil || note.UserID != actor.UserID {
+		return nil, domain.ErrMismatchInfo
+	}
+	existing, err := s.repo.GetNoteImages([]uint{noteID}, nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(existing) >= maxImagesPerNote {
+		return nil, domain.ErrTooManyImages
+	}
+
+	thumbnail, err := imaging.Thumbnail(data, noteThumbnailSize)
+	if err != nil {
+		return nil, domain.ErrUnsupportedImage
+	}
+
+	name, err := newImageKey()
+	if err != nil {
+		return nil, err
+	}
+	image := &domain.NoteImage{
+		NoteID:       noteID,
+		UserID:       actor.UserID,
+		Key:          name + "." + info.Extension,
+		ThumbnailKey: name + "_thumb.jpg",
+		ContentType:  info.ContentType,
+		Size:         int64(len(data)),
+		Width:        info.Width,
+		Height:       info.Height,
+	}
+
+	if err := s.store.Put(image.Key, data, image.ContentType); err != nil {
+		return nil, err
+	}
+	if err := s.store.Put(image.ThumbnailKey, thumbnail, "image/jpeg"); err != nil {
+		s.deleteObjects([]domain.NoteImage{*image})
+		return nil, err
+	}
+
+	db := s.repo.GetDB()
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if err := s.repo.AddImage(image, tx); err != nil {
+			return err
+		}
+		return s.audit.Record(AuditEntry{
+			Actor:      actor,
+			OwnerID:    actor.UserID,
+			Action:     domain.AuditCreate,
+			EntityType: domain.AuditEntityNoteImage,
+			EntityID:   image.ID,
+			After:      image,
+		}, tx)
+	})
+	if err != nil {
+		s.deleteObjects([]domain.NoteImage{*image})
+		return nil, err
+	}
+
+	response := s.toImageResponse(*image)
+	return &response, nil
+}
+
+func (s *noteService) RemoveImage(actor domain.Actor, noteID uint, imageID uint) error {
+	var image *domain.NoteImage
+	db := s.repo.GetDB()
+	err := db.Transaction(func(tx *gorm.DB) error {
+		note, err := s.repo.GetNote(noteID, tx)
+		if err != nil || note.UserID != actor.UserID {
+			return domain.ErrMismatchInfo
+		}
+		if image, err = s.repo.RemoveImage(imageID, noteID, tx); err != nil {
+			return err
+		}
+		return s.audit.Record(AuditEntry{
+			Actor:      actor,
+			OwnerID:    actor.UserID,
+			Action:     domain.AuditDelete,
+			EntityType: domain.AuditEntityNoteImage,
+			EntityID:   imageID,
+			Before:     image,
+		}, tx)
+	})
+	if err != nil {
+		return err
+	}
+
+	s.deleteObjects([]domain.NoteImage{*image})
+	return nil
+}
+
+func (s *noteService) RemoveUserImages(userID uint64, tx *gorm.DB) ([]domain.NoteImage, error) {
+	return s.repo.RemoveUserImages(userID, tx)
+}
+
+func (s *noteService) DeleteImageObjects(images []domain.NoteImage) {
+	s.deleteObjects(images)
+}
+
+func (s *noteService) GetImageObject(key string) ([]byte, string, error) {
+	if !imageKeyPattern.MatchString(key) {
+		return nil, "", domain.ErrImageNotFound
+	}
+
+	data, err := s.store.Get(key)
+	if err == storage.ErrObjectNotFound {
+		return nil, "", domain.ErrImageNotFound
+	}
+	if err != nil {
+		return nil, "", err
+	}
+
+	contentType := map[string]string{".jpg": "image/jpeg", ".png": "image/png", ".gif": "image/gif"}[path.Ext(key)]
+	return data, contentType, nil
+}
@@ -14,18 +14,21 @@ import (
 )
 
 type PositionService interface {
-	AddPosition(directionType string, pos *domain.Position, fee float64) error
+	// AddPosition trades on pos.OwnerID's account. The actor must be the owner or a trader member.
+	AddPosition(actor domain.Actor, directionType string, pos *domain.Position, fee float64) error
 
 	GetPositions(userID uint64) ([]domain.Position, error)
 	GetPortfolio(userID uint64) (*domain.PortfolioResponse, error)
 	GetTickerCurrentPrice(ticker string) (float64, error)
-	MigratePositions(userID uint64, provider string, accountNo string) error
+	MigratePositions(actor domain.Actor, provider string, accountNo string) error
 }
 
 type positionService struct {
 	repo     repositories.PositionRepository
 	uRepo    repositories.UserRepository
 	provider providers.PriceProvider
+	access   AccountAccess
+	audit    AuditService
 
 	balService         BalanceService
 	transactionService TransactionService
@@ -37,17 +40,37 @@ func NewPositionService(
 	provider providers.PriceProvider,
 	transactionService TransactionService,
 	balService BalanceService,
+	access AccountAccess,
+	audit AuditService,
 ) PositionService {
 	return &positionService{
 		repo:               repo,
 		uRepo:              uRepo,
 		provider:           provider,
+		access:             access,
+		audit:              audit,
 		transactionService: transactionService,
 		balService:         balService,
 	}
 }
 
-func (s *positionService) handleSellMode(existing *domain.Position, sellData *domain.Position, fee float64, tx *gorm.DB) error {
+func (s *positionService) record(actor domain.Actor, action string, before, after *domain.Position, tx *gorm.DB) error {
+	entry := AuditEntry{
+		Actor:      actor,
+		Action:     action,
+		EntityType: domain.AuditEntityPosition,
+		Before:     before,
+		After:      after,
+	}
+	if after != nil {
+		entry.OwnerID, entry.EntityID = after.OwnerID, after.ID
+	} else {
+		entry.OwnerID, entry.EntityID = before.OwnerID, before.ID
+	}
+	return s.audit.Record(entry, tx)
+}
+
+func (s *positionService) handleSellMode(actor domain.Actor, existing *domain.Position, sellData *domain.Position, fee float64, tx *gorm.DB) error {
 	if existing == nil || existing.ID == 0 || existing.TotalQty < sellData.TotalQty {
 		return domain.ErrInsufficientAmount
 	}
@@ -61,10 +84,11 @@ func (s *positionService) handleSellMode(existing *domain.Position, sellData *do
 		basePrice = existing.InvestedTotal
 	}
 
-	if err := s.balService.UpdateBalance(existing.OwnerID, sellData.InvestedTotal-fee, "stock_balance", sellData.Provider, sellData.AccountNo, tx); err != nil {
+	if err := s.balService.UpdateBalance(actor, existing.OwnerID, sellData.InvestedTotal-fee, "stock_balance", sellData.Provider, sellData.AccountNo, tx); err != nil {
 		return domain.ErrInternalServerError
 	}
 
+	before := *existing
 	existing.InvestedTotal -= basePrice
 	existing.TotalQty -= sellData.TotalQty
 
@@ -72,27 +96,36 @@ func (s *positionService) handleSellMode(existing *domain.Position, sellData *do
 		if err := s.repo.RemovePosition(existing.ID, tx); err != nil {
 			return err
 		}
+		if err := s.record(actor, domain.AuditSell, &before, nil, tx); err != nil {
+			return err
+		}
 	} else {
 		if err := s.repo.UpdatePosition(existing, tx); err != nil {
 			return err
 		}
+		if err := s.record(actor, domain.AuditSell, &before, existing, tx); err != nil {
+			return err
+		}
 	}
 
 	return s.transactionService.LogActivity(LogActivityParams{
-		Position:  existing,
-		Quantity:  sellData.TotalQty,
-		Price:     sellData.InvestedTotal,
-		Fee:       fee,
-		BasePrice: basePrice,
-		Action:    "sell",
-		Notes:     fmt.Sprintf("Sold %.f lot of %s for %s.", sellData.TotalQty, sellData.Ticker, format.FormatNumber(sellData.InvestedTotal)),
-		Title:     "",
-		Provider:  sellData.Provider,
-		AccountNo: sellData.AccountNo,
+		Actor:         actor,
+		Position:      existing,
+		Quantity:      sellData.TotalQty,
+		Price:         sellData.InvestedTotal,
+		Fee:           fee,
+		BasePrice:     basePrice,
+		Action:        "sell",
+		Notes:         fmt.Sprintf("Sold %.f lot of %s for %s.", sellData.TotalQty, sellData.Ticker, format.FormatNumber(sellData.InvestedTotal)),
+		Title:         "",
+		Provider:      sellData.Provider,
+		AccountNo:     sellData.AccountNo,
+		AssetType:     "stock_balance",
+		BalanceChange: sellData.InvestedTotal - fee,
 	}, tx)
 }
 
-func (s *positionService) handleBuyMode(existing *domain.Position, buyData *domain.Position, fee float64, tx *gorm.DB) error {
+func (s *positionService) handleBuyMode(actor domain.Actor, existing *domain.Position, buyData *domain.Position, fee float64, tx *gorm.DB) error {
 	accBal, err := s.balService.GetProviderAccount(buyData.OwnerID, "stock_balance", buyData.Provider, buyData.AccountNo, tx)
 	if err != nil {
 		return err
@@ -106,7 +139,7 @@ func (s *positionService) handleBuyMode(existing *domain.Position, buyData *doma
 		return domain.ErrInsufficientBalance
 	}
 
-	if err := s.balService.UpdateBalance(buyData.OwnerID, -(buyData.InvestedTotal + fee), "stock_balance", buyData.Provider, buyData.AccountNo, tx); err != nil {
+	if err := s.balService.UpdateBalance(actor, buyData.OwnerID, -(buyData.InvestedTotal + fee), "stock_balance", buyData.Provider, buyData.AccountNo, tx); err != nil {
 		return err
 	}
 
@@ -115,33 +148,47 @@ func (s *positionService) handleBuyMode(existing *domain.Position, buyData *doma
 			return domain.ErrMismatchInfo
 		}
 
+		before := *existing
 		existing.TotalQty += buyData.TotalQty
 		existing.InvestedTotal += buyData.InvestedTotal
 
 		if err := s.repo.UpdatePosition(existing, tx); err != nil {
 			return err
 		}
+		if err := s.record(actor, domain.AuditBuy, &before, existing, tx); err != nil {
+			return err
+		}
 	} else {
 		if err := s.repo.AddPosition(buyData, tx); err != nil {
 			return err
 		}
+		if err := s.record(actor, domain.AuditBuy, nil, buyData, tx); err != nil {
+			return err
+		}
 	}
 
 	return s.transactionService.LogActivity(LogActivityParams{
-		Position:  buyData,
-		Quantity:  buyData.TotalQty,
-		Price:     buyData.InvestedTotal + fee,
-		Fee:       fee,
-		BasePrice: buyData.InvestedTotal,
-		Action:    "buy",
-		Notes:     fmt.Sprintf("Bought %.f lot of %s for %s.", buyData.TotalQty, buyData.Ticker, format.FormatNumber(buyData.InvestedTotal)),
-		Title:     "",
-		Provider:  buyData.Provider,
-		AccountNo: buyData.AccountNo,
+		Actor:         actor,
+		Position:      buyData,
+		Quantity:      buyData.TotalQty,
+		Price:         buyData.InvestedTotal + fee,
+		Fee:           fee,
+		BasePrice:     buyData.InvestedTotal,
+		Action:        "buy",
+		Notes:         fmt.Sprintf("Bought %.f lot of %s for %s.", buyData.TotalQty, buyData.Ticker, format.FormatNumber(buyData.InvestedTotal)),
+		Title:         "",
+		Provider:      buyData.Provider,
+		AccountNo:     buyData.AccountNo,
+		AssetType:     "stock_balance",
+		BalanceChange: -(buyData.InvestedTotal + fee),
 	}, tx)
 }
 
-func (s *positionService) AddPosition(directionType string, pos *domain.Position, fee float64) error {
+func (s *positionService) AddPosition(actor domain.Actor, directionType string, pos *domain.Position, fee float64) error {
+	if !s.access.CanTrade(actor.UserID, pos.OwnerID, pos.Provider, pos.AccountNo) {
+		return domain.ErrMismatchInfo
+	}
+
 	directionType = strings.ToLower(directionType)
 
 	pos.PositionType = strings.ToLower(pos.PositionType)
@@ -171,10 +218,10 @@ func (s *positionService) AddPosition(directionType string, pos *domain.Position
 		}
 
 		if directionType == "sell" {
-			return s.handleSellMode(existing, pos, fee, tx)
+			return s.handleSellMode(actor, existing, pos, fee, tx)
 		}
 
-		return s.handleBuyMode(existing, pos, fee, tx)
+		return s.handleBuyMode(actor, existing, pos, fee, tx)
 	})
 }
 
@@ -193,12 +240,13 @@ func (s *positionService) GetPortfolio(userID uint64) (*domain.PortfolioResponse
 		tickers = append(tickers, p.Ticker)
 	}
 
-	prices, _ := s.provider.GetBatchPrices(tickers)
+	quotes, _ := s.provider.GetBatchQuotes(tickers)
 
 	var totalEquity float64
 	var portfolio []domain.PortfolioItem
 	for _, p := range positions {
-		currentPrice := prices[p.Ticker] * p.TotalQty // price per lot (only for IDX now)
+		quote := quotes[p.Ticker]
+		currentPrice := quote.Price * p.TotalQty // price per lot (only for IDX now)
 		totalEquity += currentPrice
 
 		unrealizedPnL := (currentPrice - p.InvestedTotal)
@@ -217,6 +265,9 @@ func (s *positionService) GetPortfolio(userID uint64) (*domain.PortfolioResponse
 			UpdatedAt:          p.UpdatedAt,
 			Provider:           p.Provider,
 			AccountNo:          p.AccountNo,
+			PriceSource:        quote.Source,
+			PriceUpdatedAt:     quote.UpdatedAt,
+			PriceStale:         quote.Stale,
 		})
 	}
 
@@ -230,7 +281,8 @@ func (s *positionService) GetTickerCurrentPrice(ticker string) (float64, error)
 	return s.provider.GetCurrentPrice(ticker)
 }
 
-func (s *positionService) MigratePositions(userID uint64, provider string, accountNo string) error {
+func (s *positionService) MigratePositions(actor domain.Actor, provider string, accountNo string) error {
+	userID := actor.UserID
 	db := s.repo.GetDB()
 	return db.Transaction(func(tx *gorm.DB) error {
 		positions, err := s.repo.GetPositions(userID)
@@ -244,32 +296,43 @@ func (s *positionService) MigratePositions(userID uint64, provider string, accou
 			}
 			existing, err := s.repo.GetPosByTicker(userID, legacy.Ticker, provider, accountNo, tx)
 			if err == nil {
+				before := *existing
 				existing.TotalQty += legacy.TotalQty
 				existing.InvestedTotal += legacy.InvestedTotal
 				if err := s.repo.UpdatePosition(existing, tx); err != nil {
 					return err
 				}
+				if err := s.record(actor, domain.AuditMigrate, &before, existing, tx); err != nil {
+					return err
+				}
 				if err := s.repo.RemovePosition(legacy.ID, tx); err != nil {
 					return err
 				}
+				if err := s.record(actor, domain.AuditDelete, &legacy, nil, tx); err != nil {
+					return err
+				}
 			} else if errors.Is(err, gorm.ErrRecordNotFound) {
+				before := legacy
 				legacy.Provider = provider
 				legacy.AccountNo = accountNo
 				if err := s.repo.UpdatePosition(&legacy, tx); err != nil {
 					return err
 				}
+				if err := s.record(actor, domain.AuditMigrate, &before, &legacy, tx); err != nil {
+					return err
+				}
 			} else {
 				return err
 			}
 		}
 
 		// Migrate legacy transactions (no provider and account_no, not "income" or "expense") to the new provider + account_no
-		if err := s.transactionService.MigrateTradingTransactions(userID, provider, accountNo, tx); err != nil {
+		if err := s.transactionService.MigrateTradingTransactions(actor, provider, accountNo, tx); err != nil {
 			return err
 		}
 
 		// Migrate Balance Records
-		if err := s.balService.MigrateBalances(userID, provider, accountNo, tx); err != nil {
+		if err := s.balService.MigrateBalances(actor, provider, accountNo, tx); err != nil {
 			return err
 		}
 
@@ -0,0 +1,86 @@
+package services
+
+import (
+	"sort"
+
+	"trade-tracker/core/domain"
+	"trade-tracker/pkg/utils/export"
+)
+
+// ExportRecords streams transactions and holdings selected by filter to w and closes it.
+// Only the transactions and portfolio sheets apply; the others are Excel-only.
+func (s *reportService) ExportRecords(userID uint64, filter domain.ReportFilter, w export.Writer) error {
+	if wantsSheet(filter, domain.SheetTransactions) {
+		txs, err := s.tService.GetLocalTransactions(userID)
+		if err != nil {
+			return err
+		}
+		sort.SliceStable(txs, func(i, j int) bool { return txs[i].CreatedAt.Before(txs[j].CreatedAt) })
+
+		for _, t := range txs {
+			if !inDateRange(filter, t.CreatedAt) || !matchesAccount(filter, t.Provider, t.AccountNo) {
+				continue
+			}
+			if err := w.WriteTransaction(toExportTransaction(t)); err != nil {
+				return err
+			}
+		}
+	}
+
+	if wantsSheet(filter, domain.SheetPortfolio) {
+		portfolio, err := s.pService.GetPortfolio(userID)
+		if err != nil {
+			return err
+		}
+		for _, p := range portfolio.Items {
+			if !matchesAccount(filter, p.Provider, p.AccountNo) {
+				continue
+			}
+			var avg float64
+			if p.TotalQty > 0 {
+				avg = p.InvestedTotal / p.TotalQty
+			}
+			if err := w.WriteHolding(export.Holding{
+				Provider:      p.Provider,
+				AccountNo:     p.AccountNo,
+				Ticker:        p.Ticker,
+				Quantity:      p.TotalQty,
+				AvgPrice:      avg,
+				Cost:          p.InvestedTotal,
+				MarketValue:   p.CurrentMarketPrice,
+				UnrealizedPnL: p.UnrealizedPnL,
+				PriceAsOf:     p.PriceUpdatedAt,
+			}); err != nil {
+				return err
+			}
+		}
+	}
+
+	return w.Close()
+}
+
+func toExportTransaction(t domain.TransactionResponse) export.Transaction {
+	balance, change := balanceEffect(t.Transaction)
+	unitPrice := t.EntryPriceUnit
+	if t.TransactionType == "sell" {
+		unitPrice = t.SellPriceUnit
+	}
+	return export.Transaction{
+		ID:            t.ID,
+		Date:          t.CreatedAt,
+		Provider:      t.Provider,
+		AccountNo:     t.AccountNo,
+		Type:          t.TransactionType,
+		Ticker:        t.Ticker,
+		Quantity:      t.Quantity,
+		UnitPrice:     unitPrice,
+		Amount:        t.Price,
+		Fee:           t.TransactionFee,
+		CostBasis:     t.BasePrice,
+		RealizedPnL:   t.RealizedPnl,
+		Balance:       balance,
+		BalanceChange: change,
+		Title:         t.Title,
+		Notes:         t.Notes,
+	}
+}
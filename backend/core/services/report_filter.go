@@ -0,0 +1,287 @@
+package services
+
+import (
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"trade-tracker/core/domain"
+	"trade-tracker/pkg/utils/excel"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// ParseReportFilter reads the /report/get query. from and to are inclusive YYYY-MM-DD dates,
+// accounts is a comma-separated list of provider or provider:account_no, and sheets a
+// comma-separated list of domain.ReportSheets.
+func ParseReportFilter(from, to, accounts, sheets, interval string, now time.Time) (domain.ReportFilter, error) {
+	var filter domain.ReportFilter
+
+	var err error
+	if filter.From, filter.To, err = parseDateRange(from, to, now); err != nil {
+		return filter, err
+	}
+
+	for _, item := range strings.Split(accounts, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		provider, accountNo, _ := strings.Cut(item, ":")
+		provider, accountNo = strings.TrimSpace(provider), strings.TrimSpace(accountNo)
+		if provider == "" {
+			return filter, domain.ErrInvalidAccountList
+		}
+		filter.Accounts = append(filter.Accounts, domain.ReportAccount{Provider: provider, AccountNo: accountNo})
+	}
+
+	for _, sheet := range strings.Split(sheets, ",") {
+		sheet = strings.ToLower(strings.TrimSpace(sheet))
+		if sheet == "" || slices.Contains(filter.Sheets, sheet) {
+			continue
+		}
+		if !slices.Contains(domain.ReportSheets, sheet) {
+			return filter, domain.ErrUnknownSheet
+		}
+		filter.Sheets = append(filter.Sheets, sheet)
+	}
+
+	switch interval = strings.ToLower(strings.TrimSpace(interval)); interval {
+	case "":
+		filter.Interval = domain.PeriodMonthly
+	case domain.PeriodMonthly, domain.PeriodAnnual:
+		filter.Interval = interval
+	default:
+		return filter, domain.ErrInvalidInterval
+	}
+
+	return filter, nil
+}
+
+// parseDateRange reads inclusive YYYY-MM-DD dates into [start, end). Empty dates stay zero.
+func parseDateRange(from, to string, now time.Time) (time.Time, time.Time, error) {
+	var start, end time.Time
+	if from = strings.TrimSpace(from); from != "" {
+		t, err := time.ParseInLocation("2006-01-02", from, now.Location())
+		if err != nil {
+			return start, end, domain.ErrInvalidDateRange
+		}
+		start = t
+	}
+	if to = strings.TrimSpace(to); to != "" {
+		t, err := time.ParseInLocation("2006-01-02", to, now.Location())
+		if err != nil {
+			return start, end, domain.ErrInvalidDateRange
+		}
+		end = t.AddDate(0, 0, 1)
+	}
+	if !start.IsZero() && !end.IsZero() && !start.Before(end) {
+		return start, end, domain.ErrInvalidDateRange
+	}
+	return start, end, nil
+}
+
+func wantsSheet(filter domain.ReportFilter, sheet string) bool {
+	return len(filter.Sheets) == 0 || slices.Contains(filter.Sheets, sheet)
+}
+
+func inDateRange(filter domain.ReportFilter, t time.Time) bool {
+	if !filter.From.IsZero() && t.Before(filter.From) {
+		return false
+	}
+	return filter.To.IsZero() || t.Before(filter.To)
+}
+
+func matchesAccount(filter domain.ReportFilter, provider, accountNo string) bool {
+	if len(filter.Accounts) == 0 {
+		return true
+	}
+	for _, a := range filter.Accounts {
+		if strings.EqualFold(a.Provider, provider) && (a.AccountNo == "" || a.AccountNo == accountNo) {
+			return true
+		}
+	}
+	return false
+}
+
+// balanceEffect returns the balance a transaction moved and the signed amount it moved it by.
+// Transactions logged before AssetType existed are inferred from their type; manual
+// adjustments among them only stored the target amount, so they report no change.
+func balanceEffect(t domain.Transaction) (string, float64) {
+	if t.AssetType != "" {
+		return t.AssetType, t.BalanceChange
+	}
+
+	switch t.TransactionType {
+	case "buy":
+		return "stock_balance", -t.Price
+	case "sell":
+		return "stock_balance", t.Price - t.TransactionFee
+	case "income":
+		return "cash_balance", t.Price
+	case "expense":
+		return "cash_balance", -(t.Price + t.TransactionFee)
+	case "dividend":
+		return "stock_balance", t.Price - t.TransactionFee
+	case "cashflow":
+		if strings.HasPrefix(t.Notes, "Rem ") {
+			return "stock_balance", -(t.Price + t.TransactionFee)
+		}
+		return "stock_balance", t.Price
+	}
+	return "", 0
+}
+
+type balanceKey struct {
+	Provider  string
+	AccountNo string
+	AssetType string
+}
+
+type balanceMove struct {
+	At     time.Time
+	Change float64
+}
+
+// periodStarts splits [from, to) at month or year boundaries.
+func periodStarts(from, to time.Time, interval string) []time.Time {
+	starts := []time.Time{from}
+	next := time.Date(from.Year(), from.Month(), 1, 0, 0, 0, 0, from.Location())
+	for {
+		if interval == domain.PeriodAnnual {
+			next = time.Date(next.Year()+1, 1, 1, 0, 0, 0, 0, next.Location())
+		} else {
+			next = next.AddDate(0, 1, 0)
+		}
+		if !next.Before(to) {
+			return starts
+		}
+		starts = append(starts, next)
+	}
+}
+
+// exportBalances writes opening and closing balances of every selected account per period.
+// Balances are walked back from today's amounts using the change each transaction made.
+func (s *reportService) exportBalances(f *excelize.File, userID uint64, filter domain.ReportFilter, txs []domain.TransactionResponse) error {
+	sectionName := "Balances"
+	f.NewSheet(sectionName)
+
+	writer := excel.NewWriter(f, sectionName, 1)
+	writer.WriteHeader([]interface{}{"My Account Balances"})
+
+	startRow := writer.CurrentRow
+
+	for col := 4; col <= 7; col++ {
+		writer.SetFormat(col, excel.FormatCurrency)
+	}
+
+	header := []interface{}{"Account", "Balance", "Period", "Opening", "Inflows", "Outflows", "Closing"}
+	writer.WriteHeader(header)
+
+	current := map[balanceKey]float64{}
+	for _, assetType := range []string{"cash_balance", "stock_balance"} {
+		accounts, err := s.bService.GetAccountsByType(userID, assetType)
+		if err != nil {
+			return err
+		}
+		for _, a := range accounts {
+			if matchesAccount(filter, a.ProviderName, a.AccountNo) {
+				current[balanceKey{a.ProviderName, a.AccountNo, assetType}] = a.Amount
+			}
+		}
+	}
+
+	moves := map[balanceKey][]balanceMove{}
+	earliest := time.Now()
+	for _, t := range txs {
+		if t.Provider == "" || !matchesAccount(filter, t.Provider, t.AccountNo) {
+			continue
+		}
+		assetType, change := balanceEffect(t.Transaction)
+		if assetType == "" {
+			continue
+		}
+		key := balanceKey{t.Provider, t.AccountNo, assetType}
+		if _, ok := current[key]; !ok {
+			current[key] = 0
+		}
+		moves[key] = append(moves[key], balanceMove{At: t.CreatedAt, Change: change})
+		if t.CreatedAt.Before(earliest) {
+			earliest = t.CreatedAt
+		}
+	}
+
+	from, to := filter.From, filter.To
+	if from.IsZero() {
+		from = time.Date(earliest.Year(), earliest.Month(), 1, 0, 0, 0, 0, earliest.Location())
+	}
+	if to.IsZero() {
+		to = time.Now()
+	}
+
+	keys := make([]balanceKey, 0, len(current))
+	for k := range current {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		a, b := keys[i], keys[j]
+		if a.Provider != b.Provider {
+			return a.Provider < b.Provider
+		}
+		if a.AccountNo != b.AccountNo {
+			return a.AccountNo < b.AccountNo
+		}
+		return a.AssetType < b.AssetType
+	})
+
+	labelFormat := "2006-01"
+	if filter.Interval == domain.PeriodAnnual {
+		labelFormat = "2006"
+	}
+	starts := periodStarts(from, to, filter.Interval)
+
+	for _, key := range keys {
+		balanceAt := func(t time.Time) float64 {
+			amount := current[key]
+			for _, m := range moves[key] {
+				if !m.At.Before(t) {
+					amount -= m.Change
+				}
+			}
+			return amount
+		}
+
+		for i, start := range starts {
+			end := to
+			if i+1 < len(starts) {
+				end = starts[i+1]
+			}
+
+			var inflows, outflows float64
+			for _, m := range moves[key] {
+				if m.At.Before(start) || !m.At.Before(end) {
+					continue
+				}
+				if m.Change > 0 {
+					inflows += m.Change
+				} else {
+					outflows -= m.Change
+				}
+			}
+
+			writer.WriteRow([]interface{}{
+				accountLabel(key.Provider, key.AccountNo),
+				strings.ToUpper(key.AssetType),
+				start.Format(labelFormat),
+				balanceAt(start),
+				inflows,
+				outflows,
+				balanceAt(end),
+			})
+		}
+	}
+
+	writer.BuildTable(sectionName, startRow, len(header))
+	return nil
+}
@@ -0,0 +1,247 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"trade-tracker/core/domain"
+	"trade-tracker/core/integrations/mailer"
+	"trade-tracker/core/repositories"
+	"trade-tracker/pkg/utils/export"
+)
+
+const (
+	maxSchedulesPerUser = 10
+	// scheduleSendHour is the local hour scheduled reports go out, after the previous period has closed.
+	scheduleSendHour = 7
+	// schedulesPerRun caps how many reports one delivery run renders.
+	schedulesPerRun = 50
+	// scheduleRetryAfter is the wait after the first failed delivery; it doubles with every failure.
+	scheduleRetryAfter = 15 * time.Minute
+	// maxScheduleFailures is how often a period is attempted before it is dropped.
+	maxScheduleFailures = 5
+)
+
+type ReportScheduleService interface {
+	CreateSchedule(userID uint64, req domain.ReportScheduleReq) (*domain.ReportSchedule, error)
+	UpdateSchedule(userID uint64, id uint, req domain.ReportScheduleReq) (*domain.ReportSchedule, error)
+	DeleteSchedule(userID uint64, id uint) error
+	GetSchedules(userID uint64) ([]domain.ReportSchedule, error)
+
+	// DeliverDue renders and emails every active schedule whose next run is at or before now.
+	// It stops between schedules once ctx is done.
+	DeliverDue(ctx context.Context, now time.Time) (int, error)
+}
+
+type reportScheduleService struct {
+	repo     repositories.ReportScheduleRepository
+	userRepo repositories.UserRepository
+	reports  ReportService
+	mailer   mailer.Mailer
+}
+
+func NewReportScheduleService(repo repositories.ReportScheduleRepository, userRepo repositories.UserRepository, reports ReportService, mail mailer.Mailer) ReportScheduleService {
+	return &reportScheduleService{repo: repo, userRepo: userRepo, reports: reports, mailer: mail}
+}
+
+// nextScheduleRun returns the first send time after t: Monday for weekly schedules, the 1st for monthly ones.
+func nextScheduleRun(frequency string, t time.Time) time.Time {
+	if frequency == domain.PeriodWeekly {
+		run := time.Date(t.Year(), t.Month(), t.Day(), scheduleSendHour, 0, 0, 0, t.Location())
+		run = run.AddDate(0, 0, (8-int(run.Weekday()))%7)
+		if !run.After(t) {
+			run = run.AddDate(0, 0, 7)
+		}
+		return run
+	}
+
+	run := time.Date(t.Year(), t.Month(), 1, scheduleSendHour, 0, 0, 0, t.Location())
+	if !run.After(t) {
+		run = run.AddDate(0, 1, 0)
+	}
+	return run
+}
+
+// scheduleRetry returns when to try again after the given number of failed deliveries,
+// never later than the next regular run.
+func scheduleRetry(frequency string, failures int, now time.Time) time.Time {
+	next := nextScheduleRun(frequency, now)
+	if failures >= maxScheduleFailures {
+		return next
+	}
+	if retry := now.Add(scheduleRetryAfter << (failures - 1)); retry.Before(next) {
+		return retry
+	}
+	return next
+}
+
+// schedulePeriod is the last full week or month before t.
+func schedulePeriod(frequency string, t time.Time) domain.ReportPeriod {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	if frequency == domain.PeriodWeekly {
+		end := day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+		start := end.AddDate(0, 0, -7)
+		return domain.ReportPeriod{Kind: domain.PeriodWeekly, Label: "Week of " + start.Format("2 Jan 2006"), Start: start, End: end}
+	}
+
+	end := time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+	start := end.AddDate(0, -1, 0)
+	return domain.ReportPeriod{Kind: domain.PeriodMonthly, Label: start.Format("January 2006"), Start: start, End: end}
+}
+
+func (s *reportScheduleService) CreateSchedule(userID uint64, req domain.ReportScheduleReq) (*domain.ReportSchedule, error) {
+	existing, err := s.repo.GetUserSchedules(userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(existing) >= maxSchedulesPerUser {
+		return nil, domain.ErrTooManySchedules
+	}
+	if _, err := ParseReportFilter("", "", req.Accounts, req.Sheets, "", time.Now()); err != nil {
+		return nil, err
+	}
+
+	schedule := &domain.ReportSchedule{
+		UserID:    userID,
+		Frequency: req.Frequency,
+		Format:    req.Format,
+		Accounts:  req.Accounts,
+		Sheets:    req.Sheets,
+		Active:    req.Active == nil || *req.Active,
+		NextRunAt: nextScheduleRun(req.Frequency, time.Now()),
+	}
+	if err := s.repo.CreateSchedule(schedule, nil); err != nil {
+		return nil, err
+	}
+	return schedule, nil
+}
+
+func (s *reportScheduleService) UpdateSchedule(userID uint64, id uint, req domain.ReportScheduleReq) (*domain.ReportSchedule, error) {
+	schedule, err := s.repo.GetSchedule(id, userID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := ParseReportFilter("", "", req.Accounts, req.Sheets, "", time.Now()); err != nil {
+		return nil, err
+	}
+
+	if schedule.Frequency != req.Frequency {
+		schedule.NextRunAt = nextScheduleRun(req.Frequency, time.Now())
+	}
+	schedule.Frequency = req.Frequency
+	schedule.Format = req.Format
+	schedule.Accounts = req.Accounts
+	schedule.Sheets = req.Sheets
+	if req.Active != nil {
+		if *req.Active && !schedule.Active {
+			schedule.NextRunAt = nextScheduleRun(req.Frequency, time.Now())
+		}
+		schedule.Active = *req.Active
+	}
+
+	if err := s.repo.SaveSchedule(schedule, nil); err != nil {
+		return nil, err
+	}
+	return schedule, nil
+}
+
+func (s *reportScheduleService) DeleteSchedule(userID uint64, id uint) error {
+	return s.repo.DeleteSchedule(id, userID, nil)
+}
+
+func (s *reportScheduleService) GetSchedules(userID uint64) ([]domain.ReportSchedule, error) {
+	return s.repo.GetUserSchedules(userID)
+}
+
+// DeliverDue sends each due schedule once, for the latest closed period, so a long outage does
+// not produce a backlog of emails. A failed delivery is retried with a doubling wait, so failing
+// schedules cannot fill every run; after maxScheduleFailures the period is dropped and the
+// schedule waits for its next regular run.
+func (s *reportScheduleService) DeliverDue(ctx context.Context, now time.Time) (int, error) {
+	due, err := s.repo.GetDueSchedules(now, schedulesPerRun)
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for i := range due {
+		if err := ctx.Err(); err != nil {
+			return sent, err
+		}
+		schedule := &due[i]
+		if err := s.deliver(schedule, now); err != nil {
+			log.Printf("Report schedule %d failed: %v", schedule.ID, err)
+			schedule.LastError = err.Error()
+			schedule.Failures++
+			schedule.NextRunAt = scheduleRetry(schedule.Frequency, schedule.Failures, now)
+			if schedule.Failures >= maxScheduleFailures {
+				schedule.Failures = 0
+			}
+		} else {
+			sent++
+			sentAt := now
+			schedule.LastSentAt = &sentAt
+			schedule.LastError = ""
+			schedule.Failures = 0
+			schedule.NextRunAt = nextScheduleRun(schedule.Frequency, now)
+		}
+		if err := s.repo.SaveSchedule(schedule, nil); err != nil {
+			return sent, err
+		}
+	}
+	return sent, nil
+}
+
+func (s *reportScheduleService) deliver(schedule *domain.ReportSchedule, now time.Time) error {
+	user, err := s.userRepo.GetUserByID(schedule.UserID)
+	if err != nil {
+		return err
+	}
+
+	period := schedulePeriod(schedule.Frequency, now)
+	filter, err := ParseReportFilter("", "", schedule.Accounts, schedule.Sheets, "", now)
+	if err != nil {
+		return err
+	}
+	filter.From, filter.To = period.Start, period.End
+
+	var buf bytes.Buffer
+	switch schedule.Format {
+	case "pdf":
+		doc, err := s.reports.ExportStatement(schedule.UserID, period)
+		if err != nil {
+			return err
+		}
+		if err := doc.Write(&buf); err != nil {
+			return err
+		}
+	case "xlsx":
+		file, err := s.reports.ExportProfile(schedule.UserID, filter)
+		if err != nil {
+			return err
+		}
+		if err := file.Write(&buf); err != nil {
+			return err
+		}
+	default:
+		exportFormat, ok := export.Lookup(schedule.Format)
+		if !ok {
+			return fmt.Errorf("unknown report format %q", schedule.Format)
+		}
+		if err := s.reports.ExportRecords(schedule.UserID, filter, exportFormat.New(&buf)); err != nil {
+			return err
+		}
+	}
+
+	fileName := fmt.Sprintf("TPT_Report_%s.%s", period.Start.Format("2006-01-02"), schedule.Format)
+	return s.mailer.Send(mailer.Message{
+		To:      user.Email,
+		Subject: fmt.Sprintf("Your %s report: %s", schedule.Frequency, period.Label),
+		Body: fmt.Sprintf("Hi %s,\n\nYour %s report for %s to %s is attached.\n\nYou can change or turn off this schedule in your report settings.\n",
+			user.Name, schedule.Frequency, period.Start.Format("2 Jan 2006"), period.End.AddDate(0, 0, -1).Format("2 Jan 2006")),
+		Attachments: map[string][]byte{fileName: buf.Bytes()},
+	})
+}
@@ -0,0 +1,49 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"trade-tracker/core/domain"
+)
+
+func TestScheduleRetry(t *testing.T) {
+	// Wednesday; the next weekly run is Monday 07:00, the next monthly one 1 April 07:00
+	now := time.Date(2026, 3, 11, 7, 0, 0, 0, time.UTC)
+	nextWeekly := time.Date(2026, 3, 16, 7, 0, 0, 0, time.UTC)
+	nextMonthly := time.Date(2026, 4, 1, 7, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		frequency string
+		failures  int
+		want      time.Time
+	}{
+		{"first failure", domain.PeriodWeekly, 1, now.Add(15 * time.Minute)},
+		{"second failure doubles", domain.PeriodWeekly, 2, now.Add(30 * time.Minute)},
+		{"fourth failure", domain.PeriodMonthly, 4, now.Add(2 * time.Hour)},
+		{"last failure drops the period", domain.PeriodWeekly, maxScheduleFailures, nextWeekly},
+		{"monthly drops to next month", domain.PeriodMonthly, maxScheduleFailures, nextMonthly},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := scheduleRetry(tt.frequency, tt.failures, now); !got.Equal(tt.want) {
+				t.Errorf("scheduleRetry(%s, %d) = %v, want %v", tt.frequency, tt.failures, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScheduleRetryStopsAtNextRun(t *testing.T) {
+	// Sunday 23:00: a retry may not run past Monday's regular delivery
+	now := time.Date(2026, 3, 15, 23, 0, 0, 0, time.UTC)
+	next := time.Date(2026, 3, 16, 7, 0, 0, 0, time.UTC)
+
+	if got := scheduleRetry(domain.PeriodWeekly, 4, now); !got.Before(next) {
+		t.Errorf("retry after 4 failures = %v, want before %v", got, next)
+	}
+	if got := scheduleRetry(domain.PeriodWeekly, 4, now.Add(6*time.Hour)); !got.Equal(next) {
+		t.Errorf("retry close to the next run = %v, want %v", got, next)
+	}
+}
@@ -2,34 +2,39 @@ package services
 
 import (
 	"fmt"
-	"math"
 	"strings"
 
-	"trade-tracker/core/integrations/providers"
+	"trade-tracker/core/domain"
+	"trade-tracker/pkg/utils/calendar"
 	"trade-tracker/pkg/utils/excel"
-	"trade-tracker/pkg/utils/format"
+	"trade-tracker/pkg/utils/export"
+	"trade-tracker/pkg/utils/pdf"
 
 	"github.com/xuri/excelize/v2"
 )
 
 type ReportService interface {
-	ExportProfile(userID uint64) (*excelize.File, error)
+	ExportProfile(userID uint64, filter domain.ReportFilter) (*excelize.File, error)
+	ExportStatement(userID uint64, period domain.ReportPeriod) (*pdf.Document, error)
+	TaxReport(userID uint64, year int) (*domain.TaxReport, error)
+	ExportTaxExcel(userID uint64, year int) (*excelize.File, error)
+	ExportTaxCSV(userID uint64, year int) ([]byte, error)
+	ExportRecords(userID uint64, filter domain.ReportFilter, w export.Writer) error
 }
 
 type reportService struct {
 	pService PositionService
 	uService UserService
 	tService TransactionService
-
-	provider providers.PriceProvider
+	bService BalanceService
+	exchange *calendar.Exchange
 }
 
-func NewReportService(pService PositionService, uService UserService,
-	tService TransactionService, provider providers.PriceProvider) ReportService {
-	return &reportService{pService: pService, uService: uService, tService: tService, provider: provider}
+func NewReportService(pService PositionService, uService UserService, tService TransactionService, bService BalanceService, exchange *calendar.Exchange) ReportService {
+	return &reportService{pService: pService, uService: uService, tService: tService, bService: bService, exchange: exchange}
 }
 
-func (s *reportService) exportFinancialLog(f *excelize.File, userID uint64) error {
+func (s *reportService) exportFinancialLog(f *excelize.File, filter domain.ReportFilter, txs []domain.TransactionResponse) error {
 	sectionName := "Financial"
 	f.NewSheet(sectionName)
 
@@ -45,15 +50,13 @@ func (s *reportService) exportFinancialLog(f *excelize.File, userID uint64) erro
 	header := []interface{}{"ID", "Date", "Source", "Amount", "Fee", "Flow type", "Note"}
 	writer.WriteHeader(header)
 
-	txs, err := s.tService.GetLocalTransactions(userID)
-	if err != nil {
-		return err
-	}
-
 	for _, t := range txs {
 		if t.TransactionType != "income" && t.TransactionType != "expense" {
 			continue
 		}
+		if !inDateRange(filter, t.CreatedAt) || !matchesAccount(filter, t.Provider, t.AccountNo) {
+			continue
+		}
 		writer.WriteRow([]interface{}{
 			fmt.Sprintf("#%d", t.ID),
 			t.CreatedAt,
@@ -69,7 +72,7 @@ func (s *reportService) exportFinancialLog(f *excelize.File, userID uint64) erro
 	return nil
 }
 
-func (s *reportService) exportTransactions(f *excelize.File, userID uint64) error {
+func (s *reportService) exportTransactions(f *excelize.File, filter domain.ReportFilter, txs []domain.TransactionResponse) error {
 	sectionName := "Transactions"
 	f.NewSheet(sectionName)
 
@@ -78,32 +81,51 @@ func (s *reportService) exportTransactions(f *excelize.File, userID uint64) erro
 
 	startRow := writer.CurrentRow
 
-	header := []interface{}{"ID", "Date", "Ticker", "Amount", "Fee", "Action"}
+	writer.SetFormat(2, excel.FormatDate)     // 2nd col: date
+	writer.SetFormat(4, excel.FormatNumber)   // 4th col: quantity
+	writer.SetFormat(5, excel.FormatCurrency) // 5th col: amt
+	writer.SetFormat(6, excel.FormatCurrency) // 6th col: fee
+	writer.SetFormat(8, excel.FormatCurrency) // 8th col: realized pnl
+
+	header := []interface{}{"ID", "Date", "Ticker", "Quantity", "Amount", "Fee", "Action", "Realized PnL"}
 	writer.WriteHeader(header)
 
-	txs, err := s.tService.GetLocalTransactions(userID)
-	if err != nil {
-		return err
-	}
 	for _, t := range txs {
 		if t.TransactionType != "buy" && t.TransactionType != "sell" {
 			continue
 		}
+		if !inDateRange(filter, t.CreatedAt) || !matchesAccount(filter, t.Provider, t.AccountNo) {
+			continue
+		}
 		writer.WriteRow([]interface{}{
 			fmt.Sprintf("#%d", t.ID),
 			t.CreatedAt,
 			t.Ticker,
-			format.FormatCurrency(t.Price),
-			format.FormatCurrency(t.TransactionFee),
+			t.Quantity,
+			t.Price,
+			t.TransactionFee,
 			strings.ToUpper(t.TransactionType),
+			t.RealizedPnl,
 		})
 	}
 
+	lastRow := writer.CurrentRow - 1
 	writer.BuildTable(sectionName, startRow, len(header))
+
+	if lastRow > startRow {
+		first := startRow + 1
+		writer.WriteRow([]interface{}{
+			"Total", nil, nil, nil,
+			excel.Formula("SUM(" + excel.ColumnRange(5, first, lastRow) + ")"),
+			excel.Formula("SUM(" + excel.ColumnRange(6, first, lastRow) + ")"),
+			nil,
+			excel.Formula("SUM(" + excel.ColumnRange(8, first, lastRow) + ")"),
+		})
+	}
 	return nil
 }
 
-func (s *reportService) exportPositions(f *excelize.File, userID uint64) error {
+func (s *reportService) exportPositions(f *excelize.File, userID uint64, filter domain.ReportFilter) error {
 	sectionName := "Portfolio"
 	f.NewSheet(sectionName)
 
@@ -111,6 +133,14 @@ func (s *reportService) exportPositions(f *excelize.File, userID uint64) error {
 	writer.WriteHeader([]interface{}{"My Open Positions"})
 
 	startRow := writer.CurrentRow
+
+	writer.SetFormat(3, excel.FormatCurrency)
+	writer.SetFormat(4, excel.FormatCurrency)
+	writer.SetFormat(5, excel.FormatNumber)
+	writer.SetFormat(6, excel.FormatCurrency)
+	writer.SetFormat(7, excel.FormatCurrency)
+	writer.SetFormat(8, excel.FormatPercent)
+
 	header := []interface{}{
 		"No", "Ticker", "AvgPrice", "Invested Amount",
 		"Quantity", "Market Value", "PnL Unrealized", "PnL Unrealized (Percentage)",
@@ -118,18 +148,6 @@ func (s *reportService) exportPositions(f *excelize.File, userID uint64) error {
 
 	writer.WriteHeader(header)
 
-	pos, err := s.pService.GetPositions(userID)
-	if err != nil {
-		return err
-	}
-	tickers := []string{}
-
-	for _, p := range pos {
-		tickers = append(tickers, p.Ticker)
-	}
-
-	var currentValue float64
-
 	portfolio, err := s.pService.GetPortfolio(userID)
 	if err != nil {
 		return err
@@ -138,65 +156,104 @@ func (s *reportService) exportPositions(f *excelize.File, userID uint64) error {
 		return fmt.Errorf("portfolio is empty")
 	}
 
-	prices, _ := s.provider.GetBatchPrices(tickers)
-	for i, p := range pos {
-		currentPrice := prices[p.Ticker] * p.TotalQty
-		if currentPrice <= 0 || p.InvestedTotal <= 0 {
+	for i, p := range portfolio.Items {
+		currentPrice := p.CurrentMarketPrice
+		if currentPrice <= 0 || p.InvestedTotal <= 0 || !matchesAccount(filter, p.Provider, p.AccountNo) {
 			continue
 		}
-		currentValue += currentPrice
 
 		delta := currentPrice - p.InvestedTotal
-		pnlPercent := math.Abs((delta / p.InvestedTotal) * 100)
-
-		percentStr := format.FormatNumber(pnlPercent)
-		if delta < 0 {
-			percentStr = "(" + percentStr + ")"
-		}
 		writer.WriteRow([]interface{}{
 			i + 1,
 			p.Ticker,
-			format.FormatCurrency(p.InvestedTotal / p.TotalQty),
-			format.FormatCurrency(p.InvestedTotal),
-			format.FormatNumber(p.TotalQty),
-			format.FormatCurrency(currentPrice),
-			format.FormatCurrency(delta),
-			percentStr,
+			p.InvestedTotal / p.TotalQty,
+			p.InvestedTotal,
+			p.TotalQty,
+			currentPrice,
+			delta,
+			delta / p.InvestedTotal,
 		})
 	}
 
+	lastRow := writer.CurrentRow - 1
 	writer.BuildTable(sectionName, startRow, len(header))
 	writer.SkipRow()
 
+	invested := excel.Formula("SUM(" + excel.ColumnRange(4, startRow+1, lastRow) + ")")
+	market := excel.Formula("SUM(" + excel.ColumnRange(6, startRow+1, lastRow) + ")")
+	if lastRow <= startRow {
+		invested, market = "0", "0"
+	}
+
 	header2 := []interface{}{"NAME", "AMOUNT"}
 	startRow2 := writer.CurrentRow
 
 	writer.WriteHeader(header2)
 
-	writer.WriteRow([]interface{}{"Total invested amount", format.FormatCurrency(portfolio.TotalEquity)})
-	writer.WriteRow([]interface{}{"Market value", format.FormatCurrency(currentValue)})
+	writer.ColMapping = map[int]int{2: excel.FormatCurrency}
+	writer.WriteRow([]interface{}{"Total invested amount", invested})
+	writer.WriteRow([]interface{}{"Market value", market})
 
 	writer.BuildTable(sectionName+"_2", startRow2, len(header2))
 	return nil
 }
 
-func (s *reportService) ExportProfile(userID uint64) (*excelize.File, error) {
+func (s *reportService) ExportProfile(userID uint64, filter domain.ReportFilter) (*excelize.File, error) {
 	f := excelize.NewFile()
 
-	if err := s.exportTransactions(f, userID); err != nil {
+	txs, err := s.tService.GetLocalTransactions(userID)
+	if err != nil {
 		return nil, err
 	}
-	if err := s.exportPositions(f, userID); err != nil {
-		return nil, err
+
+	first := ""
+	if wantsSheet(filter, domain.SheetTransactions) {
+		if err := s.exportTransactions(f, filter, txs); err != nil {
+			return nil, err
+		}
+		first = "Transactions"
 	}
-	if err := s.exportFinancialLog(f, userID); err != nil {
-		return nil, err
+	if wantsSheet(filter, domain.SheetClosedTrades) {
+		if err := s.exportClosedTrades(f, filter, txs); err != nil {
+			return nil, err
+		}
+		if first == "" {
+			first = "Closed Trades"
+		}
+	}
+	if wantsSheet(filter, domain.SheetPortfolio) {
+		if err := s.exportPositions(f, userID, filter); err != nil {
+			return nil, err
+		}
+		if first == "" {
+			first = "Portfolio"
+		}
+	}
+	if wantsSheet(filter, domain.SheetFinancial) {
+		if err := s.exportFinancialLog(f, filter, txs); err != nil {
+			return nil, err
+		}
+		if first == "" {
+			first = "Financial"
+		}
+	}
+	if wantsSheet(filter, domain.SheetBalances) {
+		if err := s.exportBalances(f, userID, filter, txs); err != nil {
+			return nil, err
+		}
+		if first == "" {
+			first = "Balances"
+		}
 	}
 
-	index, _ := f.GetSheetIndex("Transactions")
+	index, _ := f.GetSheetIndex(first)
 
 	f.SetActiveSheet(index)
 	f.DeleteSheet("Sheet1")
 
+	// Totals are formulas without cached values; make viewers compute them on open.
+	fullCalc := true
+	f.SetCalcProps(&excelize.CalcPropsOptions{FullCalcOnLoad: &fullCalc})
+
 	return f, nil
 }
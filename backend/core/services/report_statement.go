@@ -0,0 +1,307 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"trade-tracker/core/domain"
+	"trade-tracker/pkg/utils/format"
+	"trade-tracker/pkg/utils/pdf"
+)
+
+// ParseReportPeriod accepts "YYYY-MM", "YYYY", or "monthly" / "annual" for the period containing now.
+func ParseReportPeriod(value string, now time.Time) (domain.ReportPeriod, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	switch value {
+	case "", domain.PeriodMonthly:
+		value = now.Format("2006-01")
+	case domain.PeriodAnnual:
+		value = now.Format("2006")
+	}
+
+	if t, err := time.ParseInLocation("2006-01", value, now.Location()); err == nil {
+		return domain.ReportPeriod{Kind: domain.PeriodMonthly, Label: t.Format("January 2006"), Start: t, End: t.AddDate(0, 1, 0)}, nil
+	}
+	if t, err := time.ParseInLocation("2006", value, now.Location()); err == nil {
+		return domain.ReportPeriod{Kind: domain.PeriodAnnual, Label: t.Format("2006"), Start: t, End: t.AddDate(1, 0, 0)}, nil
+	}
+	return domain.ReportPeriod{}, domain.ErrInvalidPeriod
+}
+
+type statementHolding struct {
+	Ticker    string
+	Account   string
+	Qty       float64
+	Cost      float64
+	Value     float64
+	HasMarket bool
+}
+
+func accountLabel(provider, accountNo string) string {
+	if provider == "" {
+		return "-"
+	}
+	return strings.ToUpper(provider) + " " + accountNo
+}
+
+// holdingsAt rebuilds open positions at cost from buy and sell transactions made before t.
+func holdingsAt(trades []domain.TransactionResponse, t time.Time) []statementHolding {
+	index := map[string]*statementHolding{}
+	var keys []string
+	for _, tr := range trades {
+		if !tr.CreatedAt.Before(t) {
+			continue
+		}
+		key := tr.Ticker + "|" + tr.Provider + "|" + tr.AccountNo
+		h, ok := index[key]
+		if !ok {
+			h = &statementHolding{Ticker: tr.Ticker, Account: accountLabel(tr.Provider, tr.AccountNo)}
+			index[key] = h
+			keys = append(keys, key)
+		}
+		if tr.TransactionType == "buy" {
+			h.Qty += tr.Quantity
+			h.Cost += tr.BasePrice
+		} else {
+			h.Qty -= tr.Quantity
+			h.Cost -= tr.BasePrice
+		}
+	}
+
+	sort.Strings(keys)
+	var result []statementHolding
+	for _, k := range keys {
+		if h := index[k]; h.Qty > 0 {
+			result = append(result, *h)
+		}
+	}
+	return result
+}
+
+// investedCurve returns one point per day: cost basis of open positions plus cumulative realized PnL.
+// It is not equity: cash balances are left out, and positions stay at cost because past prices
+// are not stored.
+func investedCurve(trades []domain.TransactionResponse, start, end time.Time) ([]string, []float64) {
+	var labels []string
+	var values []float64
+
+	i := 0
+	var cost, realized float64
+	for day := start; day.Before(end); day = day.AddDate(0, 0, 1) {
+		next := day.AddDate(0, 0, 1)
+		for ; i < len(trades) && trades[i].CreatedAt.Before(next); i++ {
+			tr := trades[i]
+			if tr.TransactionType == "buy" {
+				cost += tr.BasePrice
+			} else {
+				cost -= tr.BasePrice
+				realized += tr.RealizedPnl
+			}
+		}
+		labels = append(labels, day.Format("02 Jan 2006"))
+		values = append(values, cost+realized)
+	}
+	return labels, values
+}
+
+func (s *reportService) ExportStatement(userID uint64, period domain.ReportPeriod) (*pdf.Document, error) {
+	profile, err := s.uService.GetProfile(userID)
+	if err != nil {
+		return nil, err
+	}
+	txs, err := s.tService.GetLocalTransactions(userID)
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(txs, func(i, j int) bool { return txs[i].CreatedAt.Before(txs[j].CreatedAt) })
+
+	now := time.Now()
+	current := now.Before(period.End)
+	asOf := period.End.AddDate(0, 0, -1)
+	if current {
+		asOf = now
+	}
+
+	var trades, sells, flows []domain.TransactionResponse
+	for _, t := range txs {
+		inPeriod := !t.CreatedAt.Before(period.Start) && t.CreatedAt.Before(period.End)
+		switch t.TransactionType {
+		case "buy", "sell":
+			trades = append(trades, t)
+			if inPeriod && t.TransactionType == "sell" {
+				sells = append(sells, t)
+			}
+		default:
+			if inPeriod {
+				flows = append(flows, t)
+			}
+		}
+	}
+
+	// Past periods are valued at cost since historical quotes are not stored;
+	// the running period uses the live portfolio.
+	var holdings []statementHolding
+	if current && profile.Portfolio != nil {
+		for _, p := range profile.Portfolio.Items {
+			holdings = append(holdings, statementHolding{
+				Ticker:    p.Ticker,
+				Account:   accountLabel(p.Provider, p.AccountNo),
+				Qty:       p.TotalQty,
+				Cost:      p.InvestedTotal,
+				Value:     p.CurrentMarketPrice,
+				HasMarket: true,
+			})
+		}
+	} else {
+		holdings = holdingsAt(trades, period.End)
+	}
+
+	doc := pdf.New(fmt.Sprintf("Portfolio Statement %s", period.Label))
+	w := pdf.NewWriter(doc)
+	w.Footer = fmt.Sprintf("Trade Tracker - generated %s", now.Format("02 Jan 2006 15:04"))
+
+	kind := "Monthly"
+	switch period.Kind {
+	case domain.PeriodAnnual:
+		kind = "Annual"
+	case domain.PeriodWeekly:
+		kind = "Weekly"
+	}
+	w.Title(fmt.Sprintf("%s Statement - %s", kind, period.Label),
+		fmt.Sprintf("%s (@%s) | %s to %s", profile.Name, profile.Username,
+			period.Start.Format("02 Jan 2006"), period.End.AddDate(0, 0, -1).Format("02 Jan 2006")))
+
+	var costTotal, valueTotal float64
+	for _, h := range holdings {
+		costTotal += h.Cost
+		valueTotal += h.Value
+	}
+	var realized, tradeFees float64
+	for _, t := range sells {
+		realized += t.RealizedPnl
+	}
+	tradeCount := 0
+	for _, t := range trades {
+		if !t.CreatedAt.Before(period.Start) && t.CreatedAt.Before(period.End) {
+			tradeFees += t.TransactionFee
+			tradeCount++
+		}
+	}
+	var inflow, outflow float64
+	for _, t := range flows {
+		switch t.TransactionType {
+		case "income":
+			inflow += t.Price
+		case "expense":
+			outflow += t.Price + t.TransactionFee
+		}
+	}
+
+	marketValue, unrealized := "n/a", "n/a"
+	if current {
+		marketValue = format.FormatCurrency(valueTotal)
+		unrealized = format.FormatCurrency(valueTotal - costTotal)
+	}
+
+	w.Heading("Summary")
+	w.KeyValues([][2]string{
+		{"Holdings at cost", format.FormatCurrency(costTotal)},
+		{"Realized PnL", format.FormatCurrency(realized)},
+		{"Market value", marketValue},
+		{"Unrealized PnL", unrealized},
+		{"Trades", fmt.Sprintf("%d", tradeCount)},
+		{"Trading fees", format.FormatCurrency(tradeFees)},
+		{"Cash income", format.FormatCurrency(inflow)},
+		{"Cash expenses", format.FormatCurrency(-outflow)},
+	})
+
+	w.Heading(fmt.Sprintf("Holdings as of %s", asOf.Format("02 Jan 2006")))
+	var holdingRows [][]string
+	for _, h := range holdings {
+		value, pnl := "-", "-"
+		if h.HasMarket {
+			value = format.FormatCurrency(h.Value)
+			pnl = format.FormatCurrency(h.Value - h.Cost)
+		}
+		holdingRows = append(holdingRows, []string{
+			h.Ticker, h.Account, format.FormatNumber(h.Qty),
+			format.FormatCurrency(h.Cost / h.Qty), format.FormatCurrency(h.Cost), value, pnl,
+		})
+	}
+	holdingFooter := []string{"Total", "", "", "", format.FormatCurrency(costTotal), marketValue, unrealized}
+	w.Table([]pdf.Column{
+		{Title: "Ticker", Weight: 1},
+		{Title: "Account", Weight: 1.6},
+		{Title: "Quantity", Weight: 1.1, Align: pdf.AlignRight},
+		{Title: "Avg Cost", Weight: 1.3, Align: pdf.AlignRight},
+		{Title: "Cost Basis", Weight: 1.6, Align: pdf.AlignRight},
+		{Title: "Market Value", Weight: 1.6, Align: pdf.AlignRight},
+		{Title: "Unrealized", Weight: 1.5, Align: pdf.AlignRight},
+	}, holdingRows, holdingFooter)
+	if !current {
+		w.Text("Past periods are shown at cost because historical market prices are not stored.", pdf.Gray)
+	}
+
+	w.Heading("Realized PnL")
+	var sellRows [][]string
+	var proceeds, costs, fees float64
+	for _, t := range sells {
+		proceeds += t.Price
+		costs += t.BasePrice
+		fees += t.TransactionFee
+		sellRows = append(sellRows, []string{
+			t.CreatedAt.Format("02 Jan 2006"), t.Ticker, accountLabel(t.Provider, t.AccountNo), format.FormatNumber(t.Quantity),
+			format.FormatCurrency(t.Price), format.FormatCurrency(t.BasePrice), format.FormatCurrency(t.TransactionFee), format.FormatCurrency(t.RealizedPnl),
+		})
+	}
+	w.Table([]pdf.Column{
+		{Title: "Date", Weight: 1.2},
+		{Title: "Ticker", Weight: 0.9},
+		{Title: "Account", Weight: 1.4},
+		{Title: "Quantity", Weight: 1, Align: pdf.AlignRight},
+		{Title: "Proceeds", Weight: 1.5, Align: pdf.AlignRight},
+		{Title: "Cost", Weight: 1.5, Align: pdf.AlignRight},
+		{Title: "Fee", Weight: 1.1, Align: pdf.AlignRight},
+		{Title: "PnL", Weight: 1.4, Align: pdf.AlignRight},
+	}, sellRows, []string{"Total", "", "", "", format.FormatCurrency(proceeds), format.FormatCurrency(costs), format.FormatCurrency(fees), format.FormatCurrency(realized)})
+
+	w.Heading("Cash Flows")
+	var flowRows [][]string
+	for _, t := range flows {
+		amount := t.Price
+		if t.TransactionType == "expense" {
+			amount = -t.Price
+		}
+		title := t.Title
+		if title == "" {
+			title = t.Ticker
+		}
+		flowRows = append(flowRows, []string{
+			t.CreatedAt.Format("02 Jan 2006"), strings.ToUpper(t.TransactionType), title,
+			accountLabel(t.Provider, t.AccountNo), format.FormatCurrency(amount), format.FormatCurrency(t.TransactionFee),
+		})
+	}
+	w.Table([]pdf.Column{
+		{Title: "Date", Weight: 1.1},
+		{Title: "Type", Weight: 1},
+		{Title: "Title", Weight: 2.4},
+		{Title: "Account", Weight: 1.4},
+		{Title: "Amount", Weight: 1.5, Align: pdf.AlignRight},
+		{Title: "Fee", Weight: 1.1, Align: pdf.AlignRight},
+	}, flowRows, []string{"Net income - expenses", "", "", "", format.FormatCurrency(inflow - outflow), ""})
+	w.Text("Cashflow and adjust entries move stock balances and are listed without a sign.", pdf.Gray)
+
+	w.Heading("Invested Capital + Realized PnL")
+	w.Text("Cost basis of open positions plus cumulative realized PnL, at the end of each day. Cash balances and market prices are not included.", pdf.Gray)
+	chartEnd := period.End
+	if current {
+		chartEnd = now
+	}
+	labels, values := investedCurve(trades, period.Start, chartEnd)
+	w.LineChart(labels, values, 180, format.FormatCurrency)
+
+	w.Finish()
+	return doc, nil
+}
@@ -0,0 +1,258 @@
+package services
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"trade-tracker/core/domain"
+	"trade-tracker/pkg/utils/excel"
+	"trade-tracker/pkg/utils/export"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// saleFinalTaxRate is the 0.1% final income tax on the gross value of IDX share sales.
+// Brokers withhold it as part of the sell fee; the report shows it separately for the SPT.
+const saleFinalTaxRate = 0.001
+
+// ParseTaxYear accepts "YYYY", defaulting to the previous year, which is the one being filed.
+func ParseTaxYear(value string, now time.Time) (int, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return now.Year() - 1, nil
+	}
+	year, err := strconv.Atoi(value)
+	if err != nil || year < 1900 || year > now.Year() {
+		return 0, domain.ErrInvalidTaxYear
+	}
+	return year, nil
+}
+
+func (s *reportService) TaxReport(userID uint64, year int) (*domain.TaxReport, error) {
+	txs, err := s.tService.GetLocalTransactions(userID)
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(txs, func(i, j int) bool { return txs[i].CreatedAt.Before(txs[j].CreatedAt) })
+
+	// the tax year follows the IDX calendar, not the server's zone (UTC on Vercel)
+	start := time.Date(year, 1, 1, 0, 0, 0, 0, s.exchange.Location)
+	end := start.AddDate(1, 0, 0)
+
+	report := &domain.TaxReport{Year: year, Total: domain.TaxAccountSummary{Account: "TOTAL"}}
+	index := map[string]*domain.TaxAccountSummary{}
+	var order []string
+	summary := func(t domain.TransactionResponse) *domain.TaxAccountSummary {
+		label := accountLabel(t.Provider, t.AccountNo)
+		if _, ok := index[label]; !ok {
+			index[label] = &domain.TaxAccountSummary{Account: label}
+			order = append(order, label)
+		}
+		return index[label]
+	}
+
+	var trades []domain.TransactionResponse
+	for _, t := range txs {
+		if t.TransactionType == "buy" || t.TransactionType == "sell" {
+			trades = append(trades, t)
+		}
+		if t.CreatedAt.Before(start) || !t.CreatedAt.Before(end) {
+			continue
+		}
+
+		switch t.TransactionType {
+		case "buy":
+			summary(t).Fees += t.TransactionFee
+		case "sell":
+			acc := summary(t)
+			finalTax := t.Price * saleFinalTaxRate
+			acc.SellProceeds += t.Price
+			acc.CostBasis += t.BasePrice
+			acc.Fees += t.TransactionFee
+			acc.RealizedGain += t.RealizedPnl
+			acc.SaleFinalTax += finalTax
+			report.Sales = append(report.Sales, domain.TaxSale{
+				Date:     t.CreatedAt,
+				Account:  acc.Account,
+				Ticker:   t.Ticker,
+				Quantity: t.Quantity,
+				Proceeds: t.Price,
+				Cost:     t.BasePrice,
+				Fee:      t.TransactionFee,
+				Gain:     t.RealizedPnl,
+				FinalTax: finalTax,
+			})
+		case "dividend":
+			acc := summary(t)
+			acc.DividendGross += t.Price
+			acc.DividendTax += t.TransactionFee
+			acc.DividendNet += t.Price - t.TransactionFee
+			report.Dividends = append(report.Dividends, domain.TaxDividend{
+				Date:    t.CreatedAt,
+				Account: acc.Account,
+				Ticker:  t.Ticker,
+				Gross:   t.Price,
+				Tax:     t.TransactionFee,
+				Net:     t.Price - t.TransactionFee,
+			})
+		}
+	}
+
+	sort.Strings(order)
+	for _, label := range order {
+		acc := *index[label]
+		report.Accounts = append(report.Accounts, acc)
+
+		report.Total.SellProceeds += acc.SellProceeds
+		report.Total.CostBasis += acc.CostBasis
+		report.Total.Fees += acc.Fees
+		report.Total.RealizedGain += acc.RealizedGain
+		report.Total.SaleFinalTax += acc.SaleFinalTax
+		report.Total.DividendGross += acc.DividendGross
+		report.Total.DividendTax += acc.DividendTax
+		report.Total.DividendNet += acc.DividendNet
+	}
+
+	for _, h := range holdingsAt(trades, end) {
+		report.Holdings = append(report.Holdings, domain.TaxHolding{
+			Account:  h.Account,
+			Ticker:   h.Ticker,
+			Quantity: h.Qty,
+			Cost:     h.Cost,
+		})
+	}
+
+	return report, nil
+}
+
+var taxSummaryHeader = []interface{}{
+	"Account", "Sell Proceeds", "Cost Basis", "Fees", "Realized Gain",
+	"Final Tax on Sales (0.1%)", "Dividends (Gross)", "Dividend Tax Withheld", "Dividends (Net)",
+}
+
+func taxSummaryRow(a domain.TaxAccountSummary) []interface{} {
+	return []interface{}{
+		a.Account, a.SellProceeds, a.CostBasis, a.Fees, a.RealizedGain,
+		a.SaleFinalTax, a.DividendGross, a.DividendTax, a.DividendNet,
+	}
+}
+
+func (s *reportService) ExportTaxExcel(userID uint64, year int) (*excelize.File, error) {
+	report, err := s.TaxReport(userID, year)
+	if err != nil {
+		return nil, err
+	}
+
+	f := excelize.NewFile()
+
+	sectionName := "Tax Summary"
+	f.NewSheet(sectionName)
+	writer := excel.NewWriter(f, sectionName, 1)
+	writer.WriteHeader([]interface{}{fmt.Sprintf("Tax Summary %d", year)})
+	startRow := writer.CurrentRow
+	for col := 2; col <= len(taxSummaryHeader); col++ {
+		writer.SetFormat(col, excel.FormatCurrency)
+	}
+	writer.WriteHeader(taxSummaryHeader)
+	for _, a := range report.Accounts {
+		writer.WriteRow(taxSummaryRow(a))
+	}
+	writer.WriteRow(taxSummaryRow(report.Total))
+	writer.BuildTable("TaxSummary", startRow, len(taxSummaryHeader))
+
+	sectionName = "Realized Gains"
+	f.NewSheet(sectionName)
+	writer = excel.NewWriter(f, sectionName, 1)
+	writer.WriteHeader([]interface{}{fmt.Sprintf("Sales in %d", year)})
+	startRow = writer.CurrentRow
+	writer.SetFormat(1, excel.FormatDate)
+	writer.SetFormat(4, excel.FormatNumber)
+	for col := 5; col <= 9; col++ {
+		writer.SetFormat(col, excel.FormatCurrency)
+	}
+	header := []interface{}{"Date", "Account", "Ticker", "Quantity", "Proceeds", "Cost", "Fee", "Gain", "Final Tax (0.1%)"}
+	writer.WriteHeader(header)
+	for _, sale := range report.Sales {
+		writer.WriteRow([]interface{}{sale.Date, sale.Account, sale.Ticker, sale.Quantity, sale.Proceeds, sale.Cost, sale.Fee, sale.Gain, sale.FinalTax})
+	}
+	writer.BuildTable("RealizedGains", startRow, len(header))
+
+	sectionName = "Dividends"
+	f.NewSheet(sectionName)
+	writer = excel.NewWriter(f, sectionName, 1)
+	writer.WriteHeader([]interface{}{fmt.Sprintf("Dividends in %d", year)})
+	startRow = writer.CurrentRow
+	writer.SetFormat(1, excel.FormatDate)
+	for col := 4; col <= 6; col++ {
+		writer.SetFormat(col, excel.FormatCurrency)
+	}
+	header = []interface{}{"Date", "Account", "Ticker", "Gross", "Tax Withheld", "Net"}
+	writer.WriteHeader(header)
+	for _, d := range report.Dividends {
+		writer.WriteRow([]interface{}{d.Date, d.Account, d.Ticker, d.Gross, d.Tax, d.Net})
+	}
+	writer.BuildTable("Dividends", startRow, len(header))
+
+	sectionName = "Holdings"
+	f.NewSheet(sectionName)
+	writer = excel.NewWriter(f, sectionName, 1)
+	writer.WriteHeader([]interface{}{fmt.Sprintf("Holdings at Cost on 31 Dec %d", year)})
+	startRow = writer.CurrentRow
+	writer.SetFormat(3, excel.FormatNumber)
+	writer.SetFormat(4, excel.FormatCurrency)
+	header = []interface{}{"Account", "Ticker", "Quantity", "Cost"}
+	writer.WriteHeader(header)
+	for _, h := range report.Holdings {
+		writer.WriteRow([]interface{}{h.Account, h.Ticker, h.Quantity, h.Cost})
+	}
+	writer.BuildTable("Holdings", startRow, len(header))
+
+	index, _ := f.GetSheetIndex("Tax Summary")
+	f.SetActiveSheet(index)
+	f.DeleteSheet("Sheet1")
+
+	return f, nil
+}
+
+// ExportTaxCSV writes the per-account summary with a final TOTAL row.
+func (s *reportService) ExportTaxCSV(userID uint64, year int) ([]byte, error) {
+	report, err := s.TaxReport(userID, year)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+
+	header := []string{"Year"}
+	for _, h := range taxSummaryHeader {
+		header = append(header, h.(string))
+	}
+	if err := w.Write(header); err != nil {
+		return nil, err
+	}
+
+	rows := append(report.Accounts, report.Total)
+	for _, a := range rows {
+		record := []string{strconv.Itoa(year)}
+		for _, v := range taxSummaryRow(a) {
+			switch v := v.(type) {
+			case float64:
+				record = append(record, strconv.FormatFloat(v, 'f', 2, 64))
+			default:
+				record = append(record, export.SafeCell(fmt.Sprint(v)))
+			}
+		}
+		if err := w.Write(record); err != nil {
+			return nil, err
+		}
+	}
+
+	w.Flush()
+	return buf.Bytes(), w.Error()
+}
@@ -0,0 +1,164 @@
+package services
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"trade-tracker/core/domain"
+	"trade-tracker/pkg/utils/excel"
+
+	"github.com/xuri/excelize/v2"
+)
+
+type closedTrade struct {
+	Ticker    string
+	Provider  string
+	AccountNo string
+	EntryDate time.Time
+	ExitDate  time.Time
+	Quantity  float64
+	// EntryPrice is the position's average cost per unit at the time of the sell.
+	EntryPrice float64
+	ExitPrice  float64
+	// Cost and Proceeds are the sell's recorded cost basis and net proceeds apportioned to
+	// Quantity, so PnL adds up to the sell's realized PnL.
+	Cost     float64
+	Proceeds float64
+}
+
+func (t closedTrade) PnL() float64 {
+	return t.Proceeds - t.Cost
+}
+
+type openLot struct {
+	Date time.Time
+	Qty  float64
+}
+
+// closedTrades pairs every sell with the earliest open buys of the same ticker and account (FIFO).
+// A sell that closes several lots yields one trade per lot. The lots only supply entry dates and
+// quantities: cost and PnL come from the sell's average-cost BasePrice and RealizedPnl, so the
+// sheet agrees with realized PnL everywhere else. The part of a sell without a matching buy is skipped.
+func closedTrades(txs []domain.TransactionResponse) []closedTrade {
+	sorted := make([]domain.TransactionResponse, len(txs))
+	copy(sorted, txs)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].CreatedAt.Before(sorted[j].CreatedAt) })
+
+	lots := map[string][]openLot{}
+	var trades []closedTrade
+	for _, t := range sorted {
+		if t.Quantity <= 0 {
+			continue
+		}
+		key := t.Ticker + "|" + t.Provider + "|" + t.AccountNo
+
+		switch t.TransactionType {
+		case "buy":
+			lots[key] = append(lots[key], openLot{Date: t.CreatedAt, Qty: t.Quantity})
+		case "sell":
+			unitPrice := t.Price / t.Quantity
+			unitCost := t.BasePrice / t.Quantity
+			unitProceeds := (t.Price - t.TransactionFee) / t.Quantity
+			remaining := t.Quantity
+			queue := lots[key]
+			for remaining > 0 && len(queue) > 0 {
+				lot := &queue[0]
+				qty := math.Min(remaining, lot.Qty)
+				trades = append(trades, closedTrade{
+					Ticker:     t.Ticker,
+					Provider:   t.Provider,
+					AccountNo:  t.AccountNo,
+					EntryDate:  lot.Date,
+					ExitDate:   t.CreatedAt,
+					Quantity:   qty,
+					EntryPrice: unitCost,
+					ExitPrice:  unitPrice,
+					Cost:       qty * unitCost,
+					Proceeds:   qty * unitProceeds,
+				})
+				lot.Qty -= qty
+				remaining -= qty
+				if lot.Qty <= 1e-9 {
+					queue = queue[1:]
+				}
+			}
+			lots[key] = queue
+		}
+	}
+	return trades
+}
+
+func holdingDays(entry, exit time.Time) int {
+	return int(exit.Sub(entry).Hours() / 24)
+}
+
+func (s *reportService) exportClosedTrades(f *excelize.File, filter domain.ReportFilter, txs []domain.TransactionResponse) error {
+	sectionName := "Closed Trades"
+	f.NewSheet(sectionName)
+
+	writer := excel.NewWriter(f, sectionName, 1)
+	writer.WriteHeader([]interface{}{"My Closed Trades"})
+
+	startRow := writer.CurrentRow
+
+	writer.SetFormat(3, excel.FormatDate)
+	writer.SetFormat(4, excel.FormatDate)
+	writer.SetFormat(6, excel.FormatNumber)
+	for col := 7; col <= 11; col++ {
+		writer.SetFormat(col, excel.FormatCurrency)
+	}
+	writer.SetFormat(12, excel.FormatPercent)
+
+	header := []interface{}{
+		"Ticker", "Account", "Entry Date", "Exit Date", "Holding Days", "Quantity",
+		"Avg Cost", "Exit Price", "Cost", "Proceeds", "PnL", "Return",
+	}
+	writer.WriteHeader(header)
+
+	for _, t := range closedTrades(txs) {
+		if !inDateRange(filter, t.ExitDate) || !matchesAccount(filter, t.Provider, t.AccountNo) {
+			continue
+		}
+		var ret float64
+		if t.Cost > 0 {
+			ret = t.PnL() / t.Cost
+		}
+		writer.WriteRow([]interface{}{
+			t.Ticker,
+			accountLabel(t.Provider, t.AccountNo),
+			t.EntryDate,
+			t.ExitDate,
+			holdingDays(t.EntryDate, t.ExitDate),
+			t.Quantity,
+			t.EntryPrice,
+			t.ExitPrice,
+			t.Cost,
+			t.Proceeds,
+			t.PnL(),
+			ret,
+		})
+	}
+
+	lastRow := writer.CurrentRow - 1
+	writer.BuildTable("ClosedTrades", startRow, len(header))
+
+	if lastRow > startRow {
+		first := startRow + 1
+		writer.WriteRow([]interface{}{
+			"Total", nil, nil, nil,
+			excel.Formula("AVERAGE(" + excel.ColumnRange(5, first, lastRow) + ")"),
+			nil, nil, nil,
+			excel.Formula("SUM(" + excel.ColumnRange(9, first, lastRow) + ")"),
+			excel.Formula("SUM(" + excel.ColumnRange(10, first, lastRow) + ")"),
+			excel.Formula("SUM(" + excel.ColumnRange(11, first, lastRow) + ")"),
+			excel.Formula("IFERROR(" + cellName(11, lastRow+1) + "/" + cellName(9, lastRow+1) + ",0)"),
+		})
+	}
+	return nil
+}
+
+func cellName(col, row int) string {
+	name, _ := excelize.CoordinatesToCellName(col, row)
+	return name
+}
@@ -0,0 +1,104 @@
+package services
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"trade-tracker/core/domain"
+)
+
+func trade(kind string, day int, qty, price, basePrice, fee float64) domain.TransactionResponse {
+	t := domain.TransactionResponse{}
+	t.TransactionType = kind
+	t.Ticker = "BBCA"
+	t.Provider = "Stockbit"
+	t.AccountNo = "123"
+	t.CreatedAt = time.Date(2026, 1, day, 9, 0, 0, 0, time.UTC)
+	t.Quantity = qty
+	t.Price = price
+	t.BasePrice = basePrice
+	t.TransactionFee = fee
+	if kind == "sell" {
+		t.RealizedPnl = price - basePrice - fee
+	}
+	return t
+}
+
+func TestClosedTradesMatchRealizedPnL(t *testing.T) {
+	tests := []struct {
+		name       string
+		txs        []domain.TransactionResponse
+		wantTrades int
+		wantPnL    float64
+	}{
+		{
+			name: "single lot",
+			txs: []domain.TransactionResponse{
+				trade("buy", 1, 100, 1000, 1000, 1),
+				trade("sell", 5, 100, 1200, 1000, 2),
+			},
+			wantTrades: 1,
+			wantPnL:    198,
+		},
+		{
+			name: "sell across two lots at average cost",
+			txs: []domain.TransactionResponse{
+				trade("buy", 1, 100, 1000, 1000, 0),
+				trade("buy", 2, 100, 2000, 2000, 0),
+				// average cost 15 per unit, so 150 units cost 2250
+				trade("sell", 3, 150, 3000, 2250, 3),
+			},
+			wantTrades: 2,
+			wantPnL:    747,
+		},
+		{
+			name: "sell without buys is skipped",
+			txs: []domain.TransactionResponse{
+				trade("sell", 3, 10, 100, 50, 0),
+			},
+			wantTrades: 0,
+			wantPnL:    0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			trades := closedTrades(tt.txs)
+			if len(trades) != tt.wantTrades {
+				t.Fatalf("trades = %d, want %d", len(trades), tt.wantTrades)
+			}
+
+			var pnl float64
+			for _, tr := range trades {
+				pnl += tr.PnL()
+			}
+			if math.Abs(pnl-tt.wantPnL) > 1e-9 {
+				t.Errorf("total PnL = %v, want %v", pnl, tt.wantPnL)
+			}
+		})
+	}
+}
+
+func TestClosedTradesKeepLotEntryDates(t *testing.T) {
+	trades := closedTrades([]domain.TransactionResponse{
+		trade("buy", 1, 100, 1000, 1000, 0),
+		trade("buy", 2, 100, 2000, 2000, 0),
+		trade("sell", 3, 150, 3000, 2250, 0),
+	})
+	if len(trades) != 2 {
+		t.Fatalf("trades = %d, want 2", len(trades))
+	}
+
+	if trades[0].EntryDate.Day() != 1 || trades[0].Quantity != 100 {
+		t.Errorf("first trade = day %d qty %v, want day 1 qty 100", trades[0].EntryDate.Day(), trades[0].Quantity)
+	}
+	if trades[1].EntryDate.Day() != 2 || trades[1].Quantity != 50 {
+		t.Errorf("second trade = day %d qty %v, want day 2 qty 50", trades[1].EntryDate.Day(), trades[1].Quantity)
+	}
+	for _, tr := range trades {
+		if tr.EntryPrice != 15 {
+			t.Errorf("EntryPrice = %v, want the average cost 15", tr.EntryPrice)
+		}
+	}
+}
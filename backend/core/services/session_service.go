@@ -0,0 +1,203 @@
+package services
+
+import (
+	"errors"
+	"log"
+	"time"
+
+	"trade-tracker/core/domain"
+	"trade-tracker/core/repositories"
+	"trade-tracker/pkg/utils/auth"
+
+	"gorm.io/gorm"
+)
+
+// errSessionReused lets RefreshSession commit the revocation before reporting the reuse.
+var errSessionReused = errors.New("refresh token reused")
+
+type SessionService interface {
+	CreateSession(userID uint64, userAgent string, ip string) (*domain.AuthTokens, error)
+	RefreshSession(refreshToken string, userAgent string, ip string) (*domain.AuthTokens, error)
+	RevokeSession(sessionID uint, userID uint64) error
+	RevokeByRefreshToken(refreshToken string) error
+	RevokeAllSessions(userID uint64) error
+	RevokeOtherSessions(userID uint64, currentSessionID uint) error
+
+	GetSessions(userID uint64, currentSessionID uint) ([]domain.SessionResponse, error)
+	IsSessionActive(sessionID uint, userID uint64) bool
+}
+
+type sessionService struct {
+	repo repositories.SessionRepository
+}
+
+func NewSessionService(repo repositories.SessionRepository) SessionService {
+	return &sessionService{repo: repo}
+}
+
+func truncate(value string, max int) string {
+	if len(value) > max {
+		return value[:max]
+	}
+	return value
+}
+
+func (s *sessionService) issueTokens(session *domain.Session) (*domain.AuthTokens, error) {
+	refreshToken, err := auth.GenerateOpaqueToken()
+	if err != nil {
+		return nil, err
+	}
+
+	if session.RefreshTokenHash != "" {
+		session.PreviousTokenHash = session.RefreshTokenHash
+	}
+	session.RefreshTokenHash = auth.HashToken(refreshToken)
+	session.ExpiresAt = time.Now().Add(auth.RefreshTokenTTL)
+	session.LastUsedAt = time.Now()
+
+	return &domain.AuthTokens{
+		RefreshToken:     refreshToken,
+		RefreshExpiresAt: session.ExpiresAt,
+	}, nil
+}
+
+func (s *sessionService) signAccessToken(session *domain.Session, tokens *domain.AuthTokens) error {
+	accessToken, expiresAt, err := auth.GenerateToken(uint(session.UserID), session.ID)
+	if err != nil {
+		return err
+	}
+
+	tokens.SessionID = session.ID
+	tokens.AccessToken = accessToken
+	tokens.AccessExpiresAt = expiresAt
+	return nil
+}
+
+func (s *sessionService) CreateSession(userID uint64, userAgent string, ip string) (*domain.AuthTokens, error) {
+	session := &domain.Session{
+		UserID:    userID,
+		UserAgent: truncate(userAgent, 255),
+		IP:        truncate(ip, 64),
+	}
+
+	tokens, err := s.issueTokens(session)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.CreateSession(session, nil); err != nil {
+		return nil, err
+	}
+
+	if err := s.signAccessToken(session, tokens); err != nil {
+		return nil, err
+	}
+	return tokens, nil
+}
+
+// RefreshSession rotates the refresh token. Presenting an already rotated token means it
+// leaked, so the whole session is revoked.
+func (s *sessionService) RefreshSession(refreshToken string, userAgent string, ip string) (*domain.AuthTokens, error) {
+	if refreshToken == "" {
+		return nil, domain.ErrInvalidToken
+	}
+	hash := auth.HashToken(refreshToken)
+
+	var session *domain.Session
+	var tokens *domain.AuthTokens
+	err := s.repo.GetDB().Transaction(func(tx *gorm.DB) error {
+		var err error
+		session, err = s.repo.GetSessionByTokenHash(hash, tx)
+		if err != nil {
+			if !errors.Is(err, gorm.ErrRecordNotFound) {
+				return err
+			}
+
+			if reused, err := s.repo.GetSessionByPreviousHash(hash, tx); err == nil && reused.RevokedAt == nil {
+				log.Printf("[SESSION]: Refresh token reuse detected on session %d, revoking it.\n", reused.ID)
+				if err := s.repo.RevokeSession(reused.ID, reused.UserID, tx); err != nil {
+					return err
+				}
+				return errSessionReused
+			}
+			return domain.ErrInvalidToken
+		}
+
+		if session.RevokedAt != nil {
+			return domain.ErrSessionRevoked
+		}
+		if time.Now().After(session.ExpiresAt) {
+			return domain.ErrInvalidToken
+		}
+
+		tokens, err = s.issueTokens(session)
+		if err != nil {
+			return err
+		}
+		session.UserAgent = truncate(userAgent, 255)
+		session.IP = truncate(ip, 64)
+
+		return s.repo.UpdateSession(session, tx)
+	})
+
+	if errors.Is(err, errSessionReused) {
+		return nil, domain.ErrSessionRevoked
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.signAccessToken(session, tokens); err != nil {
+		return nil, err
+	}
+	return tokens, nil
+}
+
+func (s *sessionService) RevokeSession(sessionID uint, userID uint64) error {
+	return s.repo.RevokeSession(sessionID, userID, nil)
+}
+
+func (s *sessionService) RevokeByRefreshToken(refreshToken string) error {
+	session, err := s.repo.GetSessionByTokenHash(auth.HashToken(refreshToken), nil)
+	if err != nil {
+		return domain.ErrInvalidToken
+	}
+	return s.repo.RevokeSession(session.ID, session.UserID, nil)
+}
+
+func (s *sessionService) RevokeAllSessions(userID uint64) error {
+	return s.repo.RevokeUserSessions(userID, nil)
+}
+
+func (s *sessionService) RevokeOtherSessions(userID uint64, currentSessionID uint) error {
+	return s.repo.RevokeOtherSessions(userID, currentSessionID, nil)
+}
+
+func (s *sessionService) GetSessions(userID uint64, currentSessionID uint) ([]domain.SessionResponse, error) {
+	sessions, err := s.repo.GetActiveSessions(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := []domain.SessionResponse{}
+	for _, session := range sessions {
+		result = append(result, domain.SessionResponse{
+			ID:         session.ID,
+			UserAgent:  session.UserAgent,
+			IP:         session.IP,
+			CreatedAt:  session.CreatedAt,
+			LastUsedAt: session.LastUsedAt,
+			ExpiresAt:  session.ExpiresAt,
+			Current:    session.ID == currentSessionID,
+		})
+	}
+	return result, nil
+}
+
+func (s *sessionService) IsSessionActive(sessionID uint, userID uint64) bool {
+	session, err := s.repo.GetSessionByID(sessionID, nil)
+	if err != nil {
+		return false
+	}
+	return session.UserID == userID && session.RevokedAt == nil && time.Now().Before(session.ExpiresAt)
+}
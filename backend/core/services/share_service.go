@@ -0,0 +1,200 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"trade-tracker/core/domain"
+	"trade-tracker/core/repositories"
+
+	"gorm.io/gorm"
+)
+
+// AccountAccess answers whether actorID may act on one provider account owned by ownerID.
+// Owners always have full access.
+type AccountAccess interface {
+	CanView(actorID, ownerID uint64, provider, accountNo string) bool
+	CanTrade(actorID, ownerID uint64, provider, accountNo string) bool
+}
+
+type ShareService interface {
+	AccountAccess
+
+	Invite(ownerID uint64, req domain.ShareInviteReq) (*domain.ShareResponse, error)
+	Accept(memberID uint64, shareID uint) error
+	UpdateRole(ownerID uint64, shareID uint, role string) error
+	Remove(userID uint64, shareID uint) error
+
+	GetShares(userID uint64) (*domain.ShareListResponse, error)
+	GetSharedAccount(memberID uint64, shareID uint) (*domain.SharedAccountResponse, error)
+}
+
+type shareService struct {
+	repo     repositories.ShareRepository
+	userRepo repositories.UserRepository
+	posRepo  repositories.PositionRepository
+	tranRepo repositories.TransactionRepository
+	balRepo  repositories.BalanceRepository
+}
+
+func NewShareService(
+	repo repositories.ShareRepository,
+	userRepo repositories.UserRepository,
+	posRepo repositories.PositionRepository,
+	tranRepo repositories.TransactionRepository,
+	balRepo repositories.BalanceRepository,
+) ShareService {
+	return &shareService{repo: repo, userRepo: userRepo, posRepo: posRepo, tranRepo: tranRepo, balRepo: balRepo}
+}
+
+func toShareResponse(m domain.AccountMember) domain.ShareResponse {
+	return domain.ShareResponse{
+		ID:            m.ID,
+		OwnerID:       m.OwnerID,
+		OwnerUsername: m.Owner.Username,
+		MemberID:      m.MemberID,
+		MemberName:    m.Member.Username,
+		Provider:      m.Provider,
+		AccountNo:     m.AccountNo,
+		Role:          m.Role,
+		AcceptedAt:    m.AcceptedAt,
+		CreatedAt:     m.CreatedAt,
+	}
+}
+
+func (s *shareService) CanView(actorID, ownerID uint64, provider, accountNo string) bool {
+	if actorID == ownerID {
+		return true
+	}
+	_, err := s.repo.GetAcceptedMember(ownerID, actorID, provider, accountNo)
+	return err == nil
+}
+
+func (s *shareService) CanTrade(actorID, ownerID uint64, provider, accountNo string) bool {
+	if actorID == ownerID {
+		return true
+	}
+	member, err := s.repo.GetAcceptedMember(ownerID, actorID, provider, accountNo)
+	return err == nil && member.Role == domain.MemberRoleTrader
+}
+
+func (s *shareService) Invite(ownerID uint64, req domain.ShareInviteReq) (*domain.ShareResponse, error) {
+	identifier := strings.ToLower(req.Identifier)
+	invitee, err := s.userRepo.GetUserByUsernameOrEmail(identifier, identifier, nil)
+	if err != nil {
+		return nil, domain.ErrUserNotFound
+	}
+	if uint64(invitee.ID) == ownerID {
+		return nil, domain.ErrShareSelf
+	}
+
+	balances, err := s.balRepo.GetAccountBalances(ownerID, req.Provider, req.AccountNo)
+	if err != nil {
+		return nil, err
+	}
+	if len(balances) == 0 {
+		return nil, domain.ErrShareAccountAbsent
+	}
+
+	member := &domain.AccountMember{
+		OwnerID:   ownerID,
+		MemberID:  uint64(invitee.ID),
+		Provider:  req.Provider,
+		AccountNo: req.AccountNo,
+		Role:      req.Role,
+	}
+	if err := s.repo.CreateMember(member, nil); err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) || strings.Contains(err.Error(), "duplicate key") || strings.Contains(err.Error(), "23505") {
+			return nil, domain.ErrAlreadyShared
+		}
+		return nil, err
+	}
+
+	created, err := s.repo.GetMember(member.ID)
+	if err != nil {
+		return nil, err
+	}
+	res := toShareResponse(*created)
+	return &res, nil
+}
+
+func (s *shareService) Accept(memberID uint64, shareID uint) error {
+	member, err := s.repo.GetMember(shareID)
+	if err != nil || member.MemberID != memberID {
+		return domain.ErrItemNotFound
+	}
+	if member.AcceptedAt != nil {
+		return nil
+	}
+
+	now := time.Now()
+	member.AcceptedAt = &now
+	return s.repo.UpdateMember(member, nil)
+}
+
+func (s *shareService) UpdateRole(ownerID uint64, shareID uint, role string) error {
+	member, err := s.repo.GetMember(shareID)
+	if err != nil || member.OwnerID != ownerID {
+		return domain.ErrItemNotFound
+	}
+
+	member.Role = role
+	return s.repo.UpdateMember(member, nil)
+}
+
+// Remove lets the owner revoke a share and the member leave it.
+func (s *shareService) Remove(userID uint64, shareID uint) error {
+	member, err := s.repo.GetMember(shareID)
+	if err != nil || (member.OwnerID != userID && member.MemberID != userID) {
+		return domain.ErrItemNotFound
+	}
+	return s.repo.RemoveMember(shareID, nil)
+}
+
+func (s *shareService) GetShares(userID uint64) (*domain.ShareListResponse, error) {
+	owned, err := s.repo.GetOwnedMembers(userID)
+	if err != nil {
+		return nil, err
+	}
+	shared, err := s.repo.GetMemberships(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &domain.ShareListResponse{Owned: []domain.ShareResponse{}, Shared: []domain.ShareResponse{}}
+	for _, m := range owned {
+		res.Owned = append(res.Owned, toShareResponse(m))
+	}
+	for _, m := range shared {
+		res.Shared = append(res.Shared, toShareResponse(m))
+	}
+	return res, nil
+}
+
+func (s *shareService) GetSharedAccount(memberID uint64, shareID uint) (*domain.SharedAccountResponse, error) {
+	member, err := s.repo.GetMember(shareID)
+	if err != nil || member.MemberID != memberID || member.AcceptedAt == nil {
+		return nil, domain.ErrItemNotFound
+	}
+
+	balances, err := s.balRepo.GetAccountBalances(member.OwnerID, member.Provider, member.AccountNo)
+	if err != nil {
+		return nil, err
+	}
+	positions, err := s.posRepo.GetAccountPositions(member.OwnerID, member.Provider, member.AccountNo)
+	if err != nil {
+		return nil, err
+	}
+	trans, err := s.tranRepo.GetAccountTransactions(member.OwnerID, member.Provider, member.AccountNo)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.SharedAccountResponse{
+		Share:        toShareResponse(*member),
+		Balances:     balances,
+		Positions:    positions,
+		Transactions: toTransactionResponses(trans),
+	}, nil
+}
@@ -11,17 +11,28 @@ import (
 type TransactionService interface {
 	LogActivity(params LogActivityParams, txx *gorm.DB) error
 	GetLocalTransactions(userID uint64) ([]domain.TransactionResponse, error)
-	UpdateTransaction(id uint, userID uint64, req domain.TransactionUpdateReq) error
-	MigrateTransactions(userID uint64, provider string, accountNo string, transactionIDs []uint) error
-	MigrateTradingTransactions(userID uint64, provider string, accountNo string, tx *gorm.DB) error
+	UpdateTransaction(actor domain.Actor, id uint, req domain.TransactionUpdateReq) error
+	MigrateTransactions(actor domain.Actor, provider string, accountNo string, transactionIDs []uint) error
+	MigrateTradingTransactions(actor domain.Actor, provider string, accountNo string, tx *gorm.DB) error
+
+	// RemoveTransaction moves a manual income, expense or dividend to the trash and reverses its
+	// effect on the account balance; RestoreTransaction applies it again.
+	RemoveTransaction(actor domain.Actor, id uint) error
+	RestoreTransaction(actor domain.Actor, id uint) error
+	GetTrash(userID uint64) ([]domain.TransactionResponse, error)
+	// PurgeTrash permanently deletes transactions trashed before the given time.
+	PurgeTrash(before time.Time) (int, error)
 }
 
 type transactionService struct {
 	repo    repositories.TransactionRepository
 	balRepo repositories.BalanceRepository
+	access  AccountAccess
+	audit   AuditService
 }
 
 type LogActivityParams struct {
+	Actor     domain.Actor
 	Position  *domain.Position
 	Quantity  float64
 	Price     float64
@@ -33,10 +44,13 @@ type LogActivityParams struct {
 	Date      time.Time
 	Provider  string
 	AccountNo string
+	// AssetType and BalanceChange describe the balance movement the activity caused.
+	AssetType     string
+	BalanceChange float64
 }
 
-func NewTransactionService(repo repositories.TransactionRepository, balRepo repositories.BalanceRepository) TransactionService {
-	return &transactionService{repo: repo, balRepo: balRepo}
+func NewTransactionService(repo repositories.TransactionRepository, balRepo repositories.BalanceRepository, access AccountAccess, audit AuditService) TransactionService {
+	return &transactionService{repo: repo, balRepo: balRepo, access: access, audit: audit}
 }
 
 func (s *transactionService) LogActivity(params LogActivityParams, tx *gorm.DB) error {
@@ -55,6 +69,8 @@ func (s *transactionService) LogActivity(params LogActivityParams, tx *gorm.DB)
 		Title:           params.Title,
 		Provider:        params.Provider,
 		AccountNo:       params.AccountNo,
+		AssetType:       params.AssetType,
+		BalanceChange:   params.BalanceChange,
 	}
 
 	err := s.repo.AddTransaction(log, tx)
@@ -62,16 +78,27 @@ func (s *transactionService) LogActivity(params LogActivityParams, tx *gorm.DB)
 		return err
 	}
 
-	return nil
+	return s.audit.Record(AuditEntry{
+		Actor:      params.Actor,
+		OwnerID:    log.OwnerID,
+		Action:     domain.AuditCreate,
+		EntityType: domain.AuditEntityTransaction,
+		EntityID:   log.ID,
+		After:      log,
+	}, tx)
 }
 
 func (s *transactionService) GetLocalTransactions(userID uint64) ([]domain.TransactionResponse, error) {
-	var result []domain.TransactionResponse
 	trans, err := s.repo.GetTransactions(userID)
 	if err != nil {
 		return nil, err
 	}
 
+	return toTransactionResponses(trans), nil
+}
+
+func toTransactionResponses(trans []domain.Transaction) []domain.TransactionResponse {
+	var result []domain.TransactionResponse
 	for _, t := range trans {
 		isTrade := t.TransactionType == "sell" || t.TransactionType == "buy"
 		if isTrade && t.Quantity <= 0 {
@@ -100,10 +127,10 @@ func (s *transactionService) GetLocalTransactions(userID uint64) ([]domain.Trans
 		})
 	}
 
-	return result, nil
+	return result
 }
 
-func (s *transactionService) UpdateTransaction(id uint, userID uint64, req domain.TransactionUpdateReq) error {
+func (s *transactionService) UpdateTransaction(actor domain.Actor, id uint, req domain.TransactionUpdateReq) error {
 	db := s.repo.GetDB()
 
 	return db.Transaction(func(tx *gorm.DB) error {
@@ -112,9 +139,10 @@ func (s *transactionService) UpdateTransaction(id uint, userID uint64, req domai
 			return domain.ErrItemNotFound
 		}
 
-		if trx.OwnerID != userID || (trx.TransactionType != "income" && trx.TransactionType != "expense") {
+		if !s.access.CanTrade(actor.UserID, trx.OwnerID, trx.Provider, trx.AccountNo) || (trx.TransactionType != "income" && trx.TransactionType != "expense") {
 			return domain.ErrMismatchInfo
 		}
+		before := *trx
 
 		if req.ReverseMode {
 			if trx.TransactionType == "income" {
@@ -129,35 +157,47 @@ func (s *transactionService) UpdateTransaction(id uint, userID uint64, req domai
 			delta *= -1
 		}
 
-		bal, err := s.balRepo.GetBalanceByType(userID, "cash_balance", trx.Provider, tx)
-		if err != nil {
-			return err
-		}
-
-		if bal+delta < 0 {
-			return domain.ErrInsufficientBalance
-		}
-
-		if err := s.balRepo.UpdateBalance(&domain.Balance{
-			UserID:    userID,
-			AssetType: "cash_balance",
-			Amount:    delta,
-			Provider:  trx.Provider,
-		}, tx); err != nil {
+		// same balance row as RemoveTransaction and RestoreTransaction: provider and account number
+		if err := s.applyCashChange(actor, trx, "cash_balance", delta, tx); err != nil {
 			return err
 		}
 
+		_, change := balanceEffect(before)
+		trx.AssetType = "cash_balance"
+		trx.BalanceChange = change + delta
 		trx.Title = req.Title
 		trx.Notes = req.Notes
 		trx.Price = req.Price
 
-		return s.repo.UpdateTransaction(trx, tx)
+		if err := s.repo.UpdateTransaction(trx, tx); err != nil {
+			return err
+		}
+
+		return s.audit.Record(AuditEntry{
+			Actor:      actor,
+			OwnerID:    trx.OwnerID,
+			Action:     domain.AuditUpdate,
+			EntityType: domain.AuditEntityTransaction,
+			EntityID:   trx.ID,
+			Before:     before,
+			After:      trx,
+		}, tx)
 	})
 }
 
-func (s *transactionService) MigrateTransactions(userID uint64, provider string, accountNo string, transactionIDs []uint) error {
+func (s *transactionService) MigrateTransactions(actor domain.Actor, provider string, accountNo string, transactionIDs []uint) error {
+	userID := actor.UserID
 	db := s.repo.GetDB()
 	return db.Transaction(func(tx *gorm.DB) error {
+		legacy, err := s.repo.GetTransactionsByIDsAndTypes(userID, transactionIDs, []string{"income", "expense"}, tx)
+		if err != nil {
+			return err
+		}
+		before := make(map[uint]domain.Transaction, len(legacy))
+		for _, t := range legacy {
+			before[t.ID] = t
+		}
+
 		if err := s.repo.MigrateNonTradingTransactions(userID, provider, accountNo, transactionIDs, tx); err != nil {
 			return err
 		}
@@ -182,20 +222,28 @@ func (s *transactionService) MigrateTransactions(userID uint64, provider string,
 			var defaultBal domain.Balance
 			err := tx.Where("user_id = ? AND asset_type = ? AND (provider = ? OR provider IS NULL)", userID, "cash_balance", "").First(&defaultBal).Error
 			if err == nil {
+				balBefore := defaultBal
 				defaultBal.Amount -= totalAmount
 				if err := tx.Save(&defaultBal).Error; err != nil {
 					return err
 				}
+				if err := s.recordBalance(actor, domain.AuditMigrate, &balBefore, &defaultBal, tx); err != nil {
+					return err
+				}
 			}
 
 			// Add to target balance
 			var targetBal domain.Balance
 			err = tx.Where("user_id = ? AND asset_type = ? AND provider = ? AND account_no = ?", userID, "cash_balance", provider, accountNo).First(&targetBal).Error
 			if err == nil {
+				balBefore := targetBal
 				targetBal.Amount += totalAmount
 				if err := tx.Save(&targetBal).Error; err != nil {
 					return err
 				}
+				if err := s.recordBalance(actor, domain.AuditMigrate, &balBefore, &targetBal, tx); err != nil {
+					return err
+				}
 			} else if gorm.ErrRecordNotFound == err {
 				newBal := domain.Balance{
 					UserID:    userID,
@@ -207,15 +255,175 @@ func (s *transactionService) MigrateTransactions(userID uint64, provider string,
 				if err := tx.Create(&newBal).Error; err != nil {
 					return err
 				}
+				if err := s.recordBalance(actor, domain.AuditCreate, nil, &newBal, tx); err != nil {
+					return err
+				}
 			} else {
 				return err
 			}
 		}
 
+		for _, after := range txs {
+			if err := s.audit.Record(AuditEntry{
+				Actor:      actor,
+				Action:     domain.AuditMigrate,
+				EntityType: domain.AuditEntityTransaction,
+				EntityID:   after.ID,
+				Before:     before[after.ID],
+				After:      after,
+			}, tx); err != nil {
+				return err
+			}
+		}
+
 		return nil
 	})
 }
 
-func (s *transactionService) MigrateTradingTransactions(userID uint64, provider string, accountNo string, tx *gorm.DB) error {
-	return s.repo.MigrateTradingTransactions(userID, provider, accountNo, tx)
+func (s *transactionService) MigrateTradingTransactions(actor domain.Actor, provider string, accountNo string, tx *gorm.DB) error {
+	if err := s.repo.MigrateTradingTransactions(actor.UserID, provider, accountNo, tx); err != nil {
+		return err
+	}
+
+	return s.audit.Record(AuditEntry{
+		Actor:      actor,
+		Action:     domain.AuditMigrate,
+		EntityType: domain.AuditEntityTransaction,
+		After:      map[string]string{"provider": provider, "account_no": accountNo, "scope": "trading"},
+	}, tx)
+}
+
+func (s *transactionService) recordBalance(actor domain.Actor, action string, before, after *domain.Balance, tx *gorm.DB) error {
+	return s.audit.Record(AuditEntry{
+		Actor:      actor,
+		OwnerID:    after.UserID,
+		Action:     action,
+		EntityType: domain.AuditEntityBalance,
+		EntityID:   after.ID,
+		Before:     before,
+		After:      after,
+	}, tx)
+}
+
+// isTrashable reports whether a transaction can go to the trash. Manual income, expenses and
+// dividends only moved a balance; trades cannot, because positions are built from them.
+func isTrashable(t *domain.Transaction) bool {
+	return t.TransactionType == "income" || t.TransactionType == "expense" || t.TransactionType == "dividend"
+}
+
+// applyCashChange moves the transaction's account balance by change, refusing to take it below zero.
+// Every path that changes a recorded transaction goes through it, so they all hit the balance
+// keyed by the transaction's provider and account number.
+func (s *transactionService) applyCashChange(actor domain.Actor, trx *domain.Transaction, assetType string, change float64, tx *gorm.DB) error {
+	balBefore, err := s.balRepo.GetProviderAccount(trx.OwnerID, assetType, trx.Provider, trx.AccountNo, tx)
+	if err != nil {
+		return err
+	}
+	var current float64
+	if balBefore != nil {
+		current = balBefore.Amount
+	}
+	if current+change < 0 {
+		return domain.ErrInsufficientBalance
+	}
+
+	if err := s.balRepo.UpdateBalance(&domain.Balance{
+		UserID:    trx.OwnerID,
+		AssetType: assetType,
+		Amount:    change,
+		Provider:  trx.Provider,
+		AccountNo: trx.AccountNo,
+	}, tx); err != nil {
+		return err
+	}
+
+	balAfter, err := s.balRepo.GetProviderAccount(trx.OwnerID, assetType, trx.Provider, trx.AccountNo, tx)
+	if err != nil {
+		return err
+	}
+	action := domain.AuditUpdate
+	if balBefore == nil {
+		action = domain.AuditCreate
+	}
+	return s.recordBalance(actor, action, balBefore, balAfter, tx)
+}
+
+func (s *transactionService) RemoveTransaction(actor domain.Actor, id uint) error {
+	db := s.repo.GetDB()
+	return db.Transaction(func(tx *gorm.DB) error {
+		trx, err := s.repo.GetTransactionByID(id, tx)
+		if err != nil {
+			return domain.ErrItemNotFound
+		}
+		if !s.access.CanTrade(actor.UserID, trx.OwnerID, trx.Provider, trx.AccountNo) || !isTrashable(trx) {
+			return domain.ErrMismatchInfo
+		}
+
+		if assetType, change := balanceEffect(*trx); change != 0 {
+			if err := s.applyCashChange(actor, trx, assetType, -change, tx); err != nil {
+				return err
+			}
+		}
+		if err := s.repo.RemoveTransaction(id, tx); err != nil {
+			return err
+		}
+
+		return s.audit.Record(AuditEntry{
+			Actor:      actor,
+			OwnerID:    trx.OwnerID,
+			Action:     domain.AuditDelete,
+			EntityType: domain.AuditEntityTransaction,
+			EntityID:   trx.ID,
+			Before:     trx,
+		}, tx)
+	})
+}
+
+func (s *transactionService) RestoreTransaction(actor domain.Actor, id uint) error {
+	db := s.repo.GetDB()
+	return db.Transaction(func(tx *gorm.DB) error {
+		trx, err := s.repo.GetDeletedTransaction(id, tx)
+		if err != nil {
+			return domain.ErrItemNotFound
+		}
+		if !s.access.CanTrade(actor.UserID, trx.OwnerID, trx.Provider, trx.AccountNo) || !isTrashable(trx) {
+			return domain.ErrMismatchInfo
+		}
+
+		if assetType, change := balanceEffect(*trx); change != 0 {
+			if err := s.applyCashChange(actor, trx, assetType, change, tx); err != nil {
+				return err
+			}
+		}
+		if err := s.repo.RestoreTransaction(id, tx); err != nil {
+			return err
+		}
+
+		return s.audit.Record(AuditEntry{
+			Actor:      actor,
+			OwnerID:    trx.OwnerID,
+			Action:     domain.AuditRestore,
+			EntityType: domain.AuditEntityTransaction,
+			EntityID:   trx.ID,
+			After:      trx,
+		}, tx)
+	})
+}
+
+func (s *transactionService) GetTrash(userID uint64) ([]domain.TransactionResponse, error) {
+	trans, err := s.repo.GetDeletedTransactions(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := toTransactionResponses(trans)
+	for i := range result {
+		result[i].DeletedAt = deletedAt(result[i].Transaction.DeletedAt)
+	}
+	return result, nil
+}
+
+func (s *transactionService) PurgeTrash(before time.Time) (int, error) {
+	purged, err := s.repo.PurgeDeletedTransactions(before)
+	return int(purged), err
 }
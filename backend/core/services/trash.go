@@ -0,0 +1,25 @@
+package services
+
+import (
+	"os"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const defaultTrashRetention = 30 * 24 * time.Hour
+
+// TrashRetention is how long deleted notes and cash transactions stay restorable, set by TRASH_RETENTION.
+func TrashRetention() time.Duration {
+	if d, err := time.ParseDuration(os.Getenv("TRASH_RETENTION")); err == nil && d > 0 {
+		return d
+	}
+	return defaultTrashRetention
+}
+
+func deletedAt(d gorm.DeletedAt) *time.Time {
+	if !d.Valid {
+		return nil
+	}
+	return &d.Time
+}
@@ -0,0 +1,267 @@
+package services
+
+import (
+	"crypto/rand"
+	"errors"
+	"strings"
+	"time"
+
+	"trade-tracker/core/domain"
+	"trade-tracker/core/repositories"
+	"trade-tracker/pkg/utils/auth"
+	"trade-tracker/pkg/utils/hash"
+	"trade-tracker/pkg/utils/totp"
+
+	"gorm.io/gorm"
+)
+
+const (
+	totpIssuer        = "Trade Tracker"
+	recoveryCodeCount = 10
+	recoveryAlphabet  = "abcdefghjkmnpqrstuvwxyz23456789"
+)
+
+type TwoFactorService interface {
+	Setup(userID uint64) (*domain.TwoFactorSetupResponse, error)
+	Enable(userID uint64, code string) ([]string, error)
+	Disable(userID uint64, password, code string) error
+	RegenerateRecoveryCodes(userID uint64, code string) ([]string, error)
+	// CheckCode verifies a TOTP or recovery code, and passes when 2FA is not enabled.
+	CheckCode(userID uint64, code string) error
+
+	// CreateChallenge issues the short-lived token a client exchanges, with a code, for a session.
+	CreateChallenge(userID uint64) (string, error)
+	// VerifyChallenge also returns the user ID with ErrInvalidTwoFactorCode, so the failure can be counted.
+	VerifyChallenge(mfaToken, code string) (uint64, error)
+}
+
+type twoFactorService struct {
+	userRepo repositories.UserRepository
+	codeRepo repositories.RecoveryCodeRepository
+}
+
+func NewTwoFactorService(userRepo repositories.UserRepository, codeRepo repositories.RecoveryCodeRepository) TwoFactorService {
+	return &twoFactorService{userRepo: userRepo, codeRepo: codeRepo}
+}
+
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToLower(strings.TrimSpace(code))
+	return strings.NewReplacer("-", "", " ", "").Replace(code)
+}
+
+// generateRecoveryCodes returns the plain codes (shown once, formatted "xxxxx-xxxxx") and their hashes.
+func generateRecoveryCodes() ([]string, []string, error) {
+	codes := make([]string, recoveryCodeCount)
+	hashes := make([]string, recoveryCodeCount)
+
+	for i := range codes {
+		buf := make([]byte, 10)
+		if _, err := rand.Read(buf); err != nil {
+			return nil, nil, err
+		}
+		for j, b := range buf {
+			buf[j] = recoveryAlphabet[int(b)%len(recoveryAlphabet)]
+		}
+
+		codes[i] = string(buf[:5]) + "-" + string(buf[5:])
+		hashes[i] = auth.HashToken(normalizeRecoveryCode(codes[i]))
+	}
+
+	return codes, hashes, nil
+}
+
+// checkTOTP validates a TOTP code and burns its time step so the same code cannot be replayed.
+func (s *twoFactorService) checkTOTP(user *domain.User, code string) (bool, error) {
+	step, ok := totp.Validate(code, user.TOTPSecret, time.Now())
+	if !ok {
+		return false, nil
+	}
+	return s.userRepo.AdvanceTOTPStep(uint64(user.ID), step, nil)
+}
+
+// checkCode accepts a TOTP code or an unused recovery code.
+func (s *twoFactorService) checkCode(user *domain.User, code string) error {
+	if ok, err := s.checkTOTP(user, code); err != nil {
+		return err
+	} else if ok {
+		return nil
+	}
+
+	used, err := s.codeRepo.UseCode(uint64(user.ID), auth.HashToken(normalizeRecoveryCode(code)), nil)
+	if err != nil {
+		return err
+	}
+	if !used {
+		return domain.ErrInvalidTwoFactorCode
+	}
+	return nil
+}
+
+func (s *twoFactorService) Setup(userID uint64) (*domain.TwoFactorSetupResponse, error) {
+	user, err := s.userRepo.GetUserByID(userID)
+	if err != nil {
+		return nil, domain.ErrUserNotFound
+	}
+	if user.TOTPEnabled {
+		return nil, domain.ErrTwoFactorAlreadyEnabled
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	user.TOTPSecret = secret
+	if err := s.userRepo.UpdateUser(user, nil); err != nil {
+		return nil, err
+	}
+
+	return &domain.TwoFactorSetupResponse{
+		Secret:          secret,
+		ProvisioningURI: totp.ProvisioningURI(secret, user.Email, totpIssuer),
+	}, nil
+}
+
+func (s *twoFactorService) Enable(userID uint64, code string) ([]string, error) {
+	user, err := s.userRepo.GetUserByID(userID)
+	if err != nil {
+		return nil, domain.ErrUserNotFound
+	}
+	if user.TOTPEnabled {
+		return nil, domain.ErrTwoFactorAlreadyEnabled
+	}
+	if user.TOTPSecret == "" {
+		return nil, domain.ErrTwoFactorNotSetup
+	}
+
+	step, ok := totp.Validate(code, user.TOTPSecret, time.Now())
+	if !ok {
+		return nil, domain.ErrInvalidTwoFactorCode
+	}
+
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.userRepo.GetDB().Transaction(func(tx *gorm.DB) error {
+		user.TOTPEnabled = true
+		user.TOTPLastStep = step
+		if err := s.userRepo.UpdateUser(user, tx); err != nil {
+			return err
+		}
+		return s.codeRepo.ReplaceCodes(userID, hashes, tx)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return codes, nil
+}
+
+func (s *twoFactorService) Disable(userID uint64, password, code string) error {
+	user, err := s.userRepo.GetUserByID(userID)
+	if err != nil {
+		return domain.ErrUserNotFound
+	}
+	if !user.TOTPEnabled {
+		return domain.ErrTwoFactorNotEnabled
+	}
+
+	if isCorrect, err := hash.VerifyPassword(password, user.Password); err != nil || !isCorrect {
+		return domain.ErrWrongCredential
+	}
+	if err := s.checkCode(user, code); err != nil {
+		return err
+	}
+
+	return s.userRepo.GetDB().Transaction(func(tx *gorm.DB) error {
+		user, err := s.userRepo.GetUserByID(userID)
+		if err != nil {
+			return err
+		}
+
+		user.TOTPEnabled = false
+		user.TOTPSecret = ""
+		user.TOTPLastStep = 0
+		if err := s.userRepo.UpdateUser(user, tx); err != nil {
+			return err
+		}
+		return s.codeRepo.DeleteCodes(userID, tx)
+	})
+}
+
+func (s *twoFactorService) RegenerateRecoveryCodes(userID uint64, code string) ([]string, error) {
+	user, err := s.userRepo.GetUserByID(userID)
+	if err != nil {
+		return nil, domain.ErrUserNotFound
+	}
+	if !user.TOTPEnabled {
+		return nil, domain.ErrTwoFactorNotEnabled
+	}
+
+	if ok, err := s.checkTOTP(user, code); err != nil {
+		return nil, err
+	} else if !ok {
+		return nil, domain.ErrInvalidTwoFactorCode
+	}
+
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.codeRepo.ReplaceCodes(userID, hashes, nil); err != nil {
+		return nil, err
+	}
+
+	return codes, nil
+}
+
+func (s *twoFactorService) CheckCode(userID uint64, code string) error {
+	user, err := s.userRepo.GetUserByID(userID)
+	if err != nil {
+		return domain.ErrUserNotFound
+	}
+	if !user.TOTPEnabled {
+		return nil
+	}
+	if code == "" {
+		return domain.ErrInvalidTwoFactorCode
+	}
+	return s.checkCode(user, code)
+}
+
+func (s *twoFactorService) CreateChallenge(userID uint64) (string, error) {
+	user, err := s.userRepo.GetUserByID(userID)
+	if err != nil {
+		return "", domain.ErrUserNotFound
+	}
+
+	return auth.GenerateActionToken(user.ID, auth.PurposeLoginTwoStep, auth.Fingerprint(user.Password), auth.LoginTwoStepTTL)
+}
+
+func (s *twoFactorService) VerifyChallenge(mfaToken, code string) (uint64, error) {
+	claims, err := auth.ParseActionToken(mfaToken, auth.PurposeLoginTwoStep)
+	if err != nil {
+		return 0, domain.ErrInvalidToken
+	}
+
+	user, err := s.userRepo.GetUserByID(uint64(claims.UserID))
+	if err != nil || claims.Fingerprint != auth.Fingerprint(user.Password) || !user.TOTPEnabled {
+		return 0, domain.ErrInvalidToken
+	}
+	if user.LockedUntil != nil && time.Now().Before(*user.LockedUntil) {
+		return 0, domain.ErrTooManyAttempts
+	}
+	if user.LockedAt != nil {
+		return 0, domain.ErrAccountLocked
+	}
+
+	if err := s.checkCode(user, code); err != nil {
+		if errors.Is(err, domain.ErrInvalidTwoFactorCode) {
+			return uint64(user.ID), err
+		}
+		return 0, err
+	}
+	return uint64(user.ID), nil
+}
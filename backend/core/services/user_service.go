@@ -1,9 +1,17 @@
 package services
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
 	"strings"
+	"time"
 	"trade-tracker/core/domain"
+	"trade-tracker/core/integrations/mailer"
 	"trade-tracker/core/repositories"
+	"trade-tracker/pkg/utils/auth"
 	"trade-tracker/pkg/utils/hash"
 
 	"gorm.io/gorm"
@@ -12,20 +20,53 @@ import (
 type UserService interface {
 	CreateUser(user *domain.User) error
 
-	Login(identifier, password string) (*domain.User, error)
+	Login(identifier, password, ip, userAgent string) (*domain.User, error)
+	RecordLogin(userID uint64, ip, userAgent string)
+	RecordFailedLogin(userID uint64, ip, userAgent, reason string)
+	GetLoginHistory(userID uint64, limit int) ([]domain.LoginEvent, error)
 	GetUserByUsernameOrEmail(username, email string) (*domain.User, error)
 	GetProfile(userID uint64) (*domain.UserProfileResponse, error)
+
+	SendVerificationEmail(userID uint64) error
+	VerifyEmail(token string) error
+	IsVerified(userID uint64) bool
+	RequestPasswordReset(email string) error
+	ResetPassword(token, password string) (uint64, error)
+
+	UpdateProfile(userID uint64, req domain.UpdateProfileReq) error
+	ChangePassword(userID uint64, currentPassword, newPassword string) error
+	ExportAccount(userID uint64) (*domain.AccountExport, error)
+	DeleteAccount(userID uint64, password, confirm string) error
+
+	GetAccess(userID uint64) (role string, locked bool, err error)
 }
 
+const (
+	loginLockThreshold = 5
+	loginLockBase      = time.Minute
+	loginLockMax       = time.Hour
+)
+
 type userService struct {
 	repo        repositories.UserRepository
+	loginRepo   repositories.LoginEventRepository
 	posService  PositionService
 	tranService TransactionService
 	balService  BalanceService
+	noteService NoteService
+	mailer      mailer.Mailer
+}
+
+func NewUserService(repo repositories.UserRepository, loginRepo repositories.LoginEventRepository, posService PositionService, tranService TransactionService, balService BalanceService, noteService NoteService, mailer mailer.Mailer) UserService {
+	return &userService{repo: repo, loginRepo: loginRepo, posService: posService, tranService: tranService, balService: balService, noteService: noteService, mailer: mailer}
 }
 
-func NewUserService(repo repositories.UserRepository, posService PositionService, tranService TransactionService, balService BalanceService) UserService {
-	return &userService{repo: repo, posService: posService, tranService: tranService, balService: balService}
+func appURL(path, token string) string {
+	base := os.Getenv("APP_URL")
+	if base == "" {
+		base = "http://localhost:3000"
+	}
+	return fmt.Sprintf("%s%s?token=%s", strings.TrimRight(base, "/"), path, token)
 }
 
 func (s *userService) CreateUser(user *domain.User) error {
@@ -39,7 +80,7 @@ func (s *userService) CreateUser(user *domain.User) error {
 	user.Password = hashed
 
 	db := s.repo.GetDB()
-	return db.Transaction(func(tx *gorm.DB) error {
+	err = db.Transaction(func(tx *gorm.DB) error {
 		if _, err := s.repo.GetUserByUsernameOrEmail(user.Username, user.Email, tx); err == nil {
 			return domain.ErrAlreadyExist
 		}
@@ -57,33 +98,155 @@ func (s *userService) CreateUser(user *domain.User) error {
 		}
 
 		for _, b := range defaultBalances {
-			if err := s.balService.CreateBalance(&b, tx); err != nil {
+			if err := s.balService.CreateBalance(domain.Actor{UserID: uint64(user.ID)}, &b, tx); err != nil {
 				return err
 			}
 		}
 
 		return nil
 	})
+	if err != nil {
+		return err
+	}
+
+	if err := s.SendVerificationEmail(uint64(user.ID)); err != nil {
+		log.Printf("Error when trying to send verification email: %v.\n", err)
+	}
+	return nil
 }
 
-func (s *userService) Login(identifier, password string) (*domain.User, error) {
+// loginLockDuration doubles the lock for every failure past the threshold, up to loginLockMax.
+func loginLockDuration(failures int) time.Duration {
+	if failures < loginLockThreshold {
+		return 0
+	}
+	d := loginLockBase
+	for i := loginLockThreshold; i < failures && d < loginLockMax; i++ {
+		d *= 2
+	}
+	return min(d, loginLockMax)
+}
+
+func (s *userService) Login(identifier, password, ip, userAgent string) (*domain.User, error) {
 	identifier = strings.ToLower(identifier)
 
 	user, err := s.repo.GetUserByUsernameOrEmail(identifier, identifier, nil)
 	if err != nil {
 		return nil, domain.ErrWrongCredential
 	}
+	userID := uint64(user.ID)
+
+	if user.LockedUntil != nil && time.Now().Before(*user.LockedUntil) {
+		s.recordEvent(userID, ip, userAgent, false, domain.LoginReasonTemporaryLock, false)
+		return nil, domain.ErrTooManyAttempts
+	}
 
 	if isCorrect, err := hash.VerifyPassword(password, user.Password); err != nil || !isCorrect {
+		s.RecordFailedLogin(userID, ip, userAgent, domain.LoginReasonWrongPassword)
 		return nil, domain.ErrWrongCredential
 	}
 
-	resp := &domain.User{}
+	if user.LockedAt != nil {
+		s.recordEvent(userID, ip, userAgent, false, domain.LoginReasonAccountLocked, false)
+		return nil, domain.ErrAccountLocked
+	}
+
+	// The failure counter is only reset by RecordLogin, so wrong second factors keep counting.
+	resp := &domain.User{TOTPEnabled: user.TOTPEnabled}
 	resp.ID = user.ID
 
 	return resp, nil
 }
 
+// RecordFailedLogin stores a failed password or second factor and locks the account
+// once the failures in a row pass the threshold.
+func (s *userService) RecordFailedLogin(userID uint64, ip, userAgent, reason string) {
+	s.recordEvent(userID, ip, userAgent, false, reason, false)
+
+	failures, err := s.repo.RegisterFailedLogin(userID)
+	if err != nil {
+		log.Printf("Error when trying to count failed login for user %d: %v.\n", userID, err)
+	} else if lock := loginLockDuration(failures); lock > 0 {
+		until := time.Now().Add(lock)
+		if err := s.repo.SetLoginLock(userID, &until); err != nil {
+			log.Printf("Error when trying to lock user %d: %v.\n", userID, err)
+		}
+	}
+}
+
+// RecordLogin stores a successful login once every factor has been checked, clears the
+// failure counter, and emails the user when it comes from a device that has not signed in before.
+func (s *userService) RecordLogin(userID uint64, ip, userAgent string) {
+	if err := s.repo.ResetFailedLogins(userID); err != nil {
+		log.Printf("Error when trying to reset failed logins for user %d: %v.\n", userID, err)
+	}
+
+	seen, err := s.loginRepo.HasSuccessfulLogin(userID, ip, userAgent)
+	if err != nil {
+		log.Printf("Error when trying to check login history for user %d: %v.\n", userID, err)
+		seen = true
+	}
+
+	var first bool
+	if !seen {
+		history, err := s.loginRepo.GetUserEvents(userID, 50)
+		first = err == nil && !hasSuccess(history)
+	}
+
+	newDevice := !seen && !first
+	s.recordEvent(userID, ip, userAgent, true, domain.LoginReasonSuccess, newDevice)
+	if !newDevice {
+		return
+	}
+
+	user, err := s.repo.GetUserByID(userID)
+	if err != nil {
+		return
+	}
+	err = s.mailer.Send(mailer.Message{
+		To:      user.Email,
+		Subject: "New sign-in to your account",
+		Body: fmt.Sprintf("Hi %s,\n\nYour account was just signed in from a new device.\n\nTime: %s\nIP address: %s\nDevice: %s\n\nIf this was not you, change your password and revoke your sessions right away.\n",
+			user.Name, time.Now().Format(time.RFC1123), ip, userAgent),
+	})
+	if err != nil {
+		log.Printf("Error when trying to send sign-in alert to user %d: %v.\n", userID, err)
+	}
+}
+
+func hasSuccess(events []domain.LoginEvent) bool {
+	for _, e := range events {
+		if e.Success {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *userService) recordEvent(userID uint64, ip, userAgent string, success bool, reason string, newDevice bool) {
+	if len(userAgent) > 255 {
+		userAgent = userAgent[:255]
+	}
+	err := s.loginRepo.CreateEvent(&domain.LoginEvent{
+		UserID:    userID,
+		IP:        ip,
+		UserAgent: userAgent,
+		Success:   success,
+		Reason:    reason,
+		NewDevice: newDevice,
+	})
+	if err != nil {
+		log.Printf("Error when trying to record login event for user %d: %v.\n", userID, err)
+	}
+}
+
+func (s *userService) GetLoginHistory(userID uint64, limit int) ([]domain.LoginEvent, error) {
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+	return s.loginRepo.GetUserEvents(userID, limit)
+}
+
 func (s *userService) GetUserByUsernameOrEmail(username, email string) (*domain.User, error) {
 	return s.repo.GetUserByUsernameOrEmail(strings.ToLower(username), strings.ToLower(email), nil)
 }
@@ -106,8 +269,12 @@ func (s *userService) GetProfile(userID uint64) (*domain.UserProfileResponse, er
 	totalEquity := balance.StockBalance + portfolio.TotalEquity
 
 	return &domain.UserProfileResponse{
-		Name:     user.Name,
-		Username: user.Username,
+		Name:        user.Name,
+		Username:    user.Username,
+		Email:       user.Email,
+		Verified:    user.Verified,
+		TOTPEnabled: user.TOTPEnabled,
+		Role:        user.Role,
 		Balance: domain.BalanceDetail{
 			CashBalance:  balance.CashBalance,
 			StockBalance: balance.StockBalance,
@@ -116,3 +283,271 @@ func (s *userService) GetProfile(userID uint64) (*domain.UserProfileResponse, er
 		Portfolio:   portfolio,
 	}, nil
 }
+
+func (s *userService) SendVerificationEmail(userID uint64) error {
+	user, err := s.repo.GetUserByID(userID)
+	if err != nil {
+		return domain.ErrUserNotFound
+	}
+	if user.Verified {
+		return domain.ErrEmailAlreadyVerify
+	}
+
+	token, err := auth.GenerateActionToken(user.ID, auth.PurposeVerifyEmail, auth.Fingerprint(user.Email), auth.VerifyEmailTTL)
+	if err != nil {
+		return err
+	}
+
+	return s.mailer.Send(mailer.Message{
+		To:      user.Email,
+		Subject: "Verify your email address",
+		Body: fmt.Sprintf("Hi %s,\n\nPlease confirm your email address by opening the link below:\n\n%s\n\nThe link expires in %s.\n",
+			user.Name, appURL("/verify-email", token), auth.VerifyEmailTTL),
+	})
+}
+
+func (s *userService) VerifyEmail(token string) error {
+	claims, err := auth.ParseActionToken(token, auth.PurposeVerifyEmail)
+	if err != nil {
+		return domain.ErrInvalidToken
+	}
+
+	user, err := s.repo.GetUserByID(uint64(claims.UserID))
+	if err != nil || claims.Fingerprint != auth.Fingerprint(user.Email) {
+		return domain.ErrInvalidToken
+	}
+	if user.Verified {
+		return nil
+	}
+
+	user.Verified = true
+	return s.repo.UpdateUser(user, nil)
+}
+
+func (s *userService) IsVerified(userID uint64) bool {
+	user, err := s.repo.GetUserByID(userID)
+	return err == nil && user.Verified
+}
+
+// RequestPasswordReset never reports whether the email exists, to avoid account enumeration.
+func (s *userService) RequestPasswordReset(email string) error {
+	email = strings.ToLower(email)
+	user, err := s.repo.GetUserByUsernameOrEmail(email, email, nil)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	token, err := auth.GenerateActionToken(user.ID, auth.PurposeResetPassword, auth.Fingerprint(user.Password), auth.ResetPasswordTTL)
+	if err != nil {
+		return err
+	}
+
+	return s.mailer.Send(mailer.Message{
+		To:      user.Email,
+		Subject: "Reset your password",
+		Body: fmt.Sprintf("Hi %s,\n\nSomeone requested a password reset for your account. Open the link below to choose a new password:\n\n%s\n\nThe link expires in %s. If you did not request this, you can ignore this email.\n",
+			user.Name, appURL("/reset-password", token), auth.ResetPasswordTTL),
+	})
+}
+
+// ResetPassword sets a new password and returns the user ID so the caller can revoke its sessions.
+// The token is bound to the old password hash, so it can only be used once.
+func (s *userService) ResetPassword(token, password string) (uint64, error) {
+	claims, err := auth.ParseActionToken(token, auth.PurposeResetPassword)
+	if err != nil {
+		return 0, domain.ErrInvalidToken
+	}
+
+	user, err := s.repo.GetUserByID(uint64(claims.UserID))
+	if err != nil || claims.Fingerprint != auth.Fingerprint(user.Password) {
+		return 0, domain.ErrInvalidToken
+	}
+
+	hashed, err := hash.HashPassword(password)
+	if err != nil {
+		return 0, domain.ErrInvalidInput
+	}
+	user.Password = hashed
+	// The reset link reached the user's inbox, which proves ownership of the address.
+	user.Verified = true
+	user.FailedLogins = 0
+	user.LockedUntil = nil
+
+	if err := s.repo.UpdateUser(user, nil); err != nil {
+		return 0, err
+	}
+	return uint64(user.ID), nil
+}
+
+func (s *userService) UpdateProfile(userID uint64, req domain.UpdateProfileReq) error {
+	user, err := s.repo.GetUserByID(userID)
+	if err != nil {
+		return domain.ErrUserNotFound
+	}
+
+	username := strings.ToLower(req.Username)
+	email := strings.ToLower(req.Email)
+	usernameChanged := username != "" && username != user.Username
+	emailChanged := email != "" && email != user.Email
+
+	if usernameChanged || emailChanged {
+		if isCorrect, err := hash.VerifyPassword(req.CurrentPassword, user.Password); err != nil || !isCorrect {
+			return domain.ErrWrongPassword
+		}
+	}
+
+	if req.Name != "" {
+		user.Name = req.Name
+	}
+	if usernameChanged {
+		user.Username = username
+	}
+	if emailChanged {
+		user.Email = email
+		user.Verified = false
+	}
+
+	err = s.repo.GetDB().Transaction(func(tx *gorm.DB) error {
+		if usernameChanged || emailChanged {
+			if existing, err := s.repo.GetUserByUsernameOrEmail(user.Username, user.Email, tx); err == nil && existing.ID != user.ID {
+				return domain.ErrAlreadyExist
+			}
+		}
+
+		if err := s.repo.UpdateUser(user, tx); err != nil {
+			if strings.Contains(err.Error(), "duplicate key") || strings.Contains(err.Error(), "23505") {
+				return domain.ErrAlreadyExist
+			}
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if emailChanged {
+		if err := s.SendVerificationEmail(userID); err != nil {
+			log.Printf("Error when trying to send verification email: %v.\n", err)
+		}
+	}
+	return nil
+}
+
+func (s *userService) ChangePassword(userID uint64, currentPassword, newPassword string) error {
+	user, err := s.repo.GetUserByID(userID)
+	if err != nil {
+		return domain.ErrUserNotFound
+	}
+
+	if isCorrect, err := hash.VerifyPassword(currentPassword, user.Password); err != nil || !isCorrect {
+		return domain.ErrWrongPassword
+	}
+	if currentPassword == newPassword {
+		return domain.ErrPasswordUnchanged
+	}
+
+	hashed, err := hash.HashPassword(newPassword)
+	if err != nil {
+		return domain.ErrInvalidInput
+	}
+	user.Password = hashed
+
+	return s.repo.UpdateUser(user, nil)
+}
+
+func (s *userService) ExportAccount(userID uint64) (*domain.AccountExport, error) {
+	user, err := s.repo.GetUserByID(userID)
+	if err != nil {
+		return nil, domain.ErrUserNotFound
+	}
+
+	export := &domain.AccountExport{
+		ExportedAt: time.Now(),
+		Profile: domain.AccountExportProfile{
+			ID:          user.ID,
+			Name:        user.Name,
+			Username:    user.Username,
+			Email:       user.Email,
+			Verified:    user.Verified,
+			TOTPEnabled: user.TOTPEnabled,
+			CreatedAt:   user.CreatedAt,
+		},
+	}
+
+	for _, assetType := range []string{"stock_balance", "cash_balance"} {
+		accounts, err := s.balService.GetAccountsByType(userID, assetType)
+		if err != nil {
+			return nil, err
+		}
+		export.Accounts = append(export.Accounts, accounts...)
+	}
+
+	if export.Positions, err = s.posService.GetPositions(userID); err != nil {
+		return nil, err
+	}
+	if export.Transactions, err = s.tranService.GetLocalTransactions(userID); err != nil {
+		return nil, err
+	}
+	if export.Notes, err = s.noteService.GetNotes(userID); err != nil {
+		return nil, err
+	}
+
+	return export, nil
+}
+
+// DeleteAccount permanently erases the account. A copy of the data is emailed to the
+// user first; a failed email is logged and does not block the deletion.
+func (s *userService) DeleteAccount(userID uint64, password, confirm string) error {
+	user, err := s.repo.GetUserByID(userID)
+	if err != nil {
+		return domain.ErrUserNotFound
+	}
+
+	if isCorrect, err := hash.VerifyPassword(password, user.Password); err != nil || !isCorrect {
+		return domain.ErrWrongPassword
+	}
+	if strings.ToLower(strings.TrimSpace(confirm)) != user.Username {
+		return domain.ErrDeleteNotConfirmed
+	}
+
+	if export, err := s.ExportAccount(userID); err != nil {
+		log.Printf("Error when trying to export account %d before deletion: %v.\n", userID, err)
+	} else if data, err := json.MarshalIndent(export, "", "  "); err == nil {
+		err := s.mailer.Send(mailer.Message{
+			To:          user.Email,
+			Subject:     "Your Trade Tracker account has been deleted",
+			Body:        fmt.Sprintf("Hi %s,\n\nYour account and all of its data have been deleted. A copy of your data is attached.\n", user.Name),
+			Attachments: map[string][]byte{"trade-tracker-export.json": data},
+		})
+		if err != nil {
+			log.Printf("Error when trying to email export of account %d: %v.\n", userID, err)
+		}
+	}
+
+	// the stored objects go only after the commit, so a failed deletion keeps the account whole
+	var images []domain.NoteImage
+	err = s.repo.GetDB().Transaction(func(tx *gorm.DB) error {
+		if images, err = s.noteService.RemoveUserImages(userID, tx); err != nil {
+			return err
+		}
+		return s.repo.DeleteUser(userID, tx)
+	})
+	if err != nil {
+		return err
+	}
+
+	s.noteService.DeleteImageObjects(images)
+	return nil
+}
+
+func (s *userService) GetAccess(userID uint64) (string, bool, error) {
+	user, err := s.repo.GetUserByID(userID)
+	if err != nil {
+		return "", false, domain.ErrUserNotFound
+	}
+	return user.Role, user.LockedAt != nil, nil
+}
@@ -0,0 +1,143 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"trade-tracker/core/repositories"
+	"trade-tracker/pkg/utils/calendar"
+)
+
+const UpdateStockJobName = "update-stock"
+
+// NewUpdateStockJob refreshes the IDX quotes every 5 minutes during trading hours.
+// Lunch breaks and holidays are filtered by the exchange calendar and recorded as skipped.
+func NewUpdateStockJob(exchange *calendar.Exchange) (Job, error) {
+	schedule, err := ParseCron("*/5 9-15 * * 1-5", exchange.Location)
+	if err != nil {
+		return Job{}, err
+	}
+
+	return Job{
+		Name:       UpdateStockJobName,
+		Schedule:   schedule,
+		MaxRetries: 2,
+		Backoff:    10 * time.Second,
+		Timeout:    time.Minute,
+		Run: func(ctx context.Context, now time.Time) (JobResult, error) {
+			updated, err := UpdateStock(ctx, exchange, now, false)
+			if errors.Is(err, ErrJobSkipped) {
+				return JobResult{Message: "Market closed, no data changed."}, err
+			}
+			if err != nil {
+				return JobResult{}, err
+			}
+			return JobResult{Processed: updated, Message: fmt.Sprintf("%d assets updated.", updated)}, nil
+		},
+	}, nil
+}
+
+const PurgeIdempotencyJobName = "purge-idempotency-keys"
+
+// NewPurgeIdempotencyJob deletes expired idempotency records and worker nonces every hour.
+// Both are ignored once expired, so this only keeps the tables small.
+func NewPurgeIdempotencyJob(repo repositories.IdempotencyRepository) (Job, error) {
+	schedule, err := ParseCron("0 * * * *", time.Local)
+	if err != nil {
+		return Job{}, err
+	}
+
+	return Job{
+		Name:       PurgeIdempotencyJobName,
+		Schedule:   schedule,
+		MaxRetries: 1,
+		Backoff:    time.Minute,
+		Timeout:    time.Minute,
+		Run: func(ctx context.Context, now time.Time) (JobResult, error) {
+			deleted, err := repo.DeleteExpired(now)
+			if err != nil {
+				return JobResult{}, err
+			}
+			nonces, err := repo.DeleteExpiredWorkerNonces(now)
+			if err != nil {
+				return JobResult{}, err
+			}
+			return JobResult{
+				Processed: int(deleted + nonces),
+				Message:   fmt.Sprintf("%d expired records and %d worker nonces deleted.", deleted, nonces),
+			}, nil
+		},
+	}, nil
+}
+
+const DeliverReportsJobName = "deliver-reports"
+
+// ReportDeliverer is implemented by services.ReportScheduleService.
+type ReportDeliverer interface {
+	DeliverDue(ctx context.Context, now time.Time) (int, error)
+}
+
+// NewDeliverReportsJob emails due report schedules every 15 minutes.
+func NewDeliverReportsJob(deliverer ReportDeliverer) (Job, error) {
+	schedule, err := ParseCron("*/15 * * * *", time.Local)
+	if err != nil {
+		return Job{}, err
+	}
+
+	return Job{
+		Name:       DeliverReportsJobName,
+		Schedule:   schedule,
+		MaxRetries: 1,
+		Backoff:    time.Minute,
+		Timeout:    10 * time.Minute,
+		Run: func(ctx context.Context, now time.Time) (JobResult, error) {
+			sent, err := deliverer.DeliverDue(ctx, now)
+			if err != nil {
+				return JobResult{Processed: sent}, err
+			}
+			if sent == 0 {
+				return JobResult{}, ErrJobSkipped
+			}
+			return JobResult{Processed: sent, Message: fmt.Sprintf("%d reports sent.", sent)}, nil
+		},
+	}, nil
+}
+
+const PurgeTrashJobName = "purge-trash"
+
+// TrashPurger is implemented by the services whose deletions go to a trash first.
+type TrashPurger interface {
+	PurgeTrash(before time.Time) (int, error)
+}
+
+// NewPurgeTrashJob permanently deletes items that have been in the trash longer than retention, daily at 03:00.
+func NewPurgeTrashJob(retention time.Duration, purgers ...TrashPurger) (Job, error) {
+	schedule, err := ParseCron("0 3 * * *", time.Local)
+	if err != nil {
+		return Job{}, err
+	}
+
+	return Job{
+		Name:       PurgeTrashJobName,
+		Schedule:   schedule,
+		MaxRetries: 1,
+		Backoff:    time.Minute,
+		Timeout:    10 * time.Minute,
+		Run: func(ctx context.Context, now time.Time) (JobResult, error) {
+			purged := 0
+			for _, p := range purgers {
+				if err := ctx.Err(); err != nil {
+					return JobResult{}, err
+				}
+				n, err := p.PurgeTrash(now.Add(-retention))
+				purged += n
+				if err != nil {
+					return JobResult{}, err
+				}
+			}
+			return JobResult{Processed: purged, Message: fmt.Sprintf("%d trashed items purged.", purged)}, nil
+		},
+	}, nil
+}
@@ -0,0 +1,59 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+type fakeDeliverer struct {
+	sent int
+	err  error
+	ctx  context.Context
+}
+
+func (d *fakeDeliverer) DeliverDue(ctx context.Context, now time.Time) (int, error) {
+	d.ctx = ctx
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	return d.sent, d.err
+}
+
+func TestDeliverReportsJob(t *testing.T) {
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tests := []struct {
+		name          string
+		ctx           context.Context
+		deliverer     *fakeDeliverer
+		wantErr       error
+		wantProcessed int
+	}{
+		{"sent", context.Background(), &fakeDeliverer{sent: 2}, nil, 2},
+		{"nothing due", context.Background(), &fakeDeliverer{}, ErrJobSkipped, 0},
+		{"timed out", cancelled, &fakeDeliverer{sent: 2}, context.Canceled, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			job, err := NewDeliverReportsJob(tt.deliverer)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			result, err := job.Run(tt.ctx, time.Now())
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("error = %v, want %v", err, tt.wantErr)
+			}
+			if result.Processed != tt.wantProcessed {
+				t.Errorf("Processed = %d, want %d", result.Processed, tt.wantProcessed)
+			}
+			if tt.deliverer.ctx != tt.ctx {
+				t.Error("DeliverDue did not get the job's context")
+			}
+		})
+	}
+}
@@ -0,0 +1,175 @@
+package worker
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type Schedule interface {
+	Next(after time.Time) time.Time
+	String() string
+}
+
+type everySchedule struct {
+	interval time.Duration
+}
+
+// Every runs a job at a fixed interval, aligned to the interval boundaries (e.g. :00, :05, :10).
+func Every(interval time.Duration) Schedule {
+	return everySchedule{interval: interval}
+}
+
+func (s everySchedule) Next(after time.Time) time.Time {
+	return after.Truncate(s.interval).Add(s.interval)
+}
+
+func (s everySchedule) String() string {
+	return "every " + s.interval.String()
+}
+
+type fieldRange struct {
+	min, max int
+}
+
+var cronFields = []fieldRange{
+	{0, 59}, // minute
+	{0, 23}, // hour
+	{1, 31}, // day of month
+	{1, 12}, // month
+	{0, 6},  // day of week (0 = Sunday)
+}
+
+type cronSchedule struct {
+	expr     string
+	location *time.Location
+
+	minute, hour, dom, month, dow map[int]bool
+	domAny, dowAny                bool
+}
+
+// ParseCron parses a standard 5-field cron expression ("minute hour dom month dow").
+// Each field accepts "*", single values, ranges ("1-5"), lists ("1,3") and steps ("*/5", "9-15/2").
+// The schedule is evaluated in loc.
+func ParseCron(expr string, loc *time.Location) (Schedule, error) {
+	fields := strings.Fields(expr)
+	if len(fields) != len(cronFields) {
+		return nil, fmt.Errorf("cron expression %q must have %d fields", expr, len(cronFields))
+	}
+
+	sets := make([]map[int]bool, len(fields))
+	for i, f := range fields {
+		set, err := parseCronField(f, cronFields[i])
+		if err != nil {
+			return nil, fmt.Errorf("cron expression %q: %v", expr, err)
+		}
+		sets[i] = set
+	}
+
+	if loc == nil {
+		loc = time.Local
+	}
+
+	return &cronSchedule{
+		expr:     expr,
+		location: loc,
+		minute:   sets[0],
+		hour:     sets[1],
+		dom:      sets[2],
+		month:    sets[3],
+		dow:      sets[4],
+		domAny:   fields[2] == "*",
+		dowAny:   fields[4] == "*",
+	}, nil
+}
+
+func parseCronField(field string, r fieldRange) (map[int]bool, error) {
+	set := make(map[int]bool)
+
+	for _, part := range strings.Split(field, ",") {
+		step := 1
+		if idx := strings.Index(part, "/"); idx >= 0 {
+			s, err := strconv.Atoi(part[idx+1:])
+			if err != nil || s <= 0 {
+				return nil, fmt.Errorf("invalid step in %q", part)
+			}
+			step = s
+			part = part[:idx]
+		}
+
+		lo, hi := r.min, r.max
+		switch {
+		case part == "*":
+		case strings.Contains(part, "-"):
+			bounds := strings.SplitN(part, "-", 2)
+			var err1, err2 error
+			lo, err1 = strconv.Atoi(bounds[0])
+			hi, err2 = strconv.Atoi(bounds[1])
+			if err1 != nil || err2 != nil {
+				return nil, fmt.Errorf("invalid range %q", part)
+			}
+		default:
+			v, err := strconv.Atoi(part)
+			if err != nil {
+				return nil, fmt.Errorf("invalid value %q", part)
+			}
+			lo = v
+			if step == 1 {
+				hi = v
+			}
+		}
+
+		if lo < r.min || hi > r.max || lo > hi {
+			return nil, fmt.Errorf("%q is out of range %d-%d", part, r.min, r.max)
+		}
+
+		for v := lo; v <= hi; v += step {
+			set[v] = true
+		}
+	}
+
+	return set, nil
+}
+
+// dayMatches follows cron semantics: when both day fields are restricted, either may match.
+func (s *cronSchedule) dayMatches(t time.Time) bool {
+	domOk := s.dom[t.Day()]
+	dowOk := s.dow[int(t.Weekday())]
+
+	if s.domAny || s.dowAny {
+		return domOk && dowOk
+	}
+	return domOk || dowOk
+}
+
+func (s *cronSchedule) Next(after time.Time) time.Time {
+	t := after.In(s.location).Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(5, 0, 0)
+
+	for t.Before(limit) {
+		if !s.month[int(t.Month())] {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, s.location)
+			continue
+		}
+		if !s.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, s.location)
+			continue
+		}
+		if !s.hour[t.Hour()] {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, s.location)
+			continue
+		}
+		if !s.minute[t.Minute()] {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+
+	return time.Time{}
+}
+
+func (s *cronSchedule) String() string {
+	return s.expr
+}
@@ -0,0 +1,98 @@
+package worker
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseCronErrors(t *testing.T) {
+	tests := []string{
+		"",
+		"* * * *",
+		"* * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 7",
+		"5-1 * * * *",
+		"*/0 * * * *",
+		"a * * * *",
+		"1-x * * * *",
+	}
+
+	for _, expr := range tests {
+		t.Run(expr, func(t *testing.T) {
+			if _, err := ParseCron(expr, time.UTC); err == nil {
+				t.Errorf("ParseCron(%q) succeeded, want an error", expr)
+			}
+		})
+	}
+}
+
+func TestCronNext(t *testing.T) {
+	jakarta := time.FixedZone("WIB", 7*3600)
+	at := func(loc *time.Location, year int, month time.Month, day, hour, minute int) time.Time {
+		return time.Date(year, month, day, hour, minute, 0, 0, loc)
+	}
+
+	tests := []struct {
+		name  string
+		expr  string
+		loc   *time.Location
+		after time.Time
+		want  time.Time
+	}{
+		{"every minute", "* * * * *", time.UTC, at(time.UTC, 2026, 8, 18, 10, 0).Add(30 * time.Second), at(time.UTC, 2026, 8, 18, 10, 1)},
+		{"strictly after", "0 * * * *", time.UTC, at(time.UTC, 2026, 8, 18, 10, 0), at(time.UTC, 2026, 8, 18, 11, 0)},
+		{"step inside hours", "*/5 9-15 * * 1-5", time.UTC, at(time.UTC, 2026, 8, 18, 9, 7), at(time.UTC, 2026, 8, 18, 9, 10)},
+		{"after last hour", "*/5 9-15 * * 1-5", time.UTC, at(time.UTC, 2026, 8, 18, 15, 55), at(time.UTC, 2026, 8, 19, 9, 0)},
+		{"skips weekend", "*/5 9-15 * * 1-5", time.UTC, at(time.UTC, 2026, 8, 21, 16, 0), at(time.UTC, 2026, 8, 24, 9, 0)},
+		{"range with step", "0 9-15/3 * * *", time.UTC, at(time.UTC, 2026, 8, 18, 12, 1), at(time.UTC, 2026, 8, 18, 15, 0)},
+		{"list", "0 7,19 * * *", time.UTC, at(time.UTC, 2026, 8, 18, 8, 0), at(time.UTC, 2026, 8, 18, 19, 0)},
+		{"value with step", "10/20 * * * *", time.UTC, at(time.UTC, 2026, 8, 18, 8, 31), at(time.UTC, 2026, 8, 18, 8, 50)},
+		{"first of month", "0 7 1 * *", time.UTC, at(time.UTC, 2026, 12, 15, 0, 0), at(time.UTC, 2027, 1, 1, 7, 0)},
+		{"day 31 skips short months", "0 0 31 * *", time.UTC, at(time.UTC, 2026, 4, 1, 0, 0), at(time.UTC, 2026, 5, 31, 0, 0)},
+		{"leap day", "0 0 29 2 *", time.UTC, at(time.UTC, 2026, 3, 1, 0, 0), at(time.UTC, 2028, 2, 29, 0, 0)},
+		{"day of month or weekday", "0 0 15 * 1", time.UTC, at(time.UTC, 2026, 8, 11, 0, 0), at(time.UTC, 2026, 8, 15, 0, 0)},
+		{"weekday before day of month", "0 0 20 * 1", time.UTC, at(time.UTC, 2026, 8, 15, 0, 0), at(time.UTC, 2026, 8, 17, 0, 0)},
+		{"sunday is zero", "0 3 * * 0", time.UTC, at(time.UTC, 2026, 8, 18, 0, 0), at(time.UTC, 2026, 8, 23, 3, 0)},
+		{"evaluated in location", "0 9 * * *", jakarta, at(time.UTC, 2026, 8, 18, 1, 0), at(jakarta, 2026, 8, 18, 9, 0)},
+		{"never matches", "0 0 31 2 *", time.UTC, at(time.UTC, 2026, 1, 1, 0, 0), time.Time{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule, err := ParseCron(tt.expr, tt.loc)
+			if err != nil {
+				t.Fatalf("ParseCron(%q): %v", tt.expr, err)
+			}
+			if got := schedule.Next(tt.after); !got.Equal(tt.want) {
+				t.Errorf("Next(%s) = %s, want %s", tt.after, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEveryNext(t *testing.T) {
+	base := time.Date(2026, 8, 18, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		interval time.Duration
+		after    time.Time
+		want     time.Time
+	}{
+		{"on boundary", 5 * time.Minute, base, base.Add(5 * time.Minute)},
+		{"between boundaries", 5 * time.Minute, base.Add(7 * time.Minute), base.Add(10 * time.Minute)},
+		{"hourly", time.Hour, base.Add(59 * time.Minute), base.Add(time.Hour)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Every(tt.interval).Next(tt.after); !got.Equal(tt.want) {
+				t.Errorf("Next(%s) = %s, want %s", tt.after, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,264 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"trade-tracker/core/domain"
+	"trade-tracker/core/repositories"
+)
+
+const maxBackoff = 5 * time.Minute
+
+// ErrJobSkipped tells the scheduler a run had nothing to do (e.g. market closed). It is not retried.
+var ErrJobSkipped = errors.New("job skipped")
+
+// JobResult is what a run reports back. Both fields are stored with the run.
+type JobResult struct {
+	// Processed counts what the run wrote, e.g. assets updated or reports sent.
+	Processed int
+	Message   string
+}
+
+// JobFunc runs one attempt of a job. now is the time the run was triggered.
+// It should stop early when ctx is done.
+type JobFunc func(ctx context.Context, now time.Time) (JobResult, error)
+
+type Job struct {
+	Name       string
+	Schedule   Schedule
+	Run        JobFunc
+	MaxRetries int
+	Backoff    time.Duration
+	Timeout    time.Duration
+}
+
+type scheduledJob struct {
+	Job
+	running atomic.Bool
+}
+
+type Scheduler struct {
+	repo repositories.JobRepository
+
+	mu      sync.RWMutex
+	jobs    map[string]*scheduledJob
+	started bool
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func NewScheduler(repo repositories.JobRepository) *Scheduler {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Scheduler{
+		repo:   repo,
+		jobs:   make(map[string]*scheduledJob),
+		ctx:    ctx,
+		cancel: cancel,
+	}
+}
+
+func (s *Scheduler) Register(job Job) error {
+	if job.Name == "" || job.Schedule == nil || job.Run == nil {
+		return fmt.Errorf("job requires a name, a schedule and a run function")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.jobs[job.Name]; exists {
+		return fmt.Errorf("job %q is already registered", job.Name)
+	}
+	s.jobs[job.Name] = &scheduledJob{Job: job}
+	return nil
+}
+
+// Start runs every registered job on its schedule. Deployments without a long-running
+// process skip it and call Trigger from the worker endpoints instead.
+func (s *Scheduler) Start() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.started = true
+	for _, job := range s.jobs {
+		s.wg.Add(1)
+		go s.loop(job)
+	}
+	log.Printf("[SCHEDULER]: Started with %d job(s).\n", len(s.jobs))
+}
+
+// Stop cancels running jobs and waits for them to return.
+func (s *Scheduler) Stop() {
+	s.cancel()
+	s.wg.Wait()
+}
+
+func (s *Scheduler) loop(job *scheduledJob) {
+	defer s.wg.Done()
+
+	for {
+		next := job.Schedule.Next(time.Now())
+		if next.IsZero() {
+			log.Printf("[SCHEDULER]: Job %s has no upcoming run, stopping it.\n", job.Name)
+			return
+		}
+
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-s.ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			s.execute(job, time.Now(), job.MaxRetries)
+		}()
+	}
+}
+
+// RunNow triggers a job outside of its schedule. It still respects overlap prevention.
+func (s *Scheduler) RunNow(name string) error {
+	s.mu.RLock()
+	job, found := s.jobs[name]
+	s.mu.RUnlock()
+	if !found {
+		return domain.ErrItemNotFound
+	}
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		s.execute(job, time.Now(), job.MaxRetries)
+	}()
+	return nil
+}
+
+// Trigger runs a job right away and waits for it, recording the run like a scheduled one.
+// It makes a single attempt because the external caller retries on its own schedule.
+func (s *Scheduler) Trigger(name string, now time.Time) (*domain.JobRun, error) {
+	s.mu.RLock()
+	job, found := s.jobs[name]
+	s.mu.RUnlock()
+	if !found {
+		return nil, domain.ErrItemNotFound
+	}
+
+	return s.execute(job, now, 0)
+}
+
+func (s *Scheduler) execute(job *scheduledJob, now time.Time, maxRetries int) (*domain.JobRun, error) {
+	if !job.running.CompareAndSwap(false, true) {
+		log.Printf("[SCHEDULER]: Job %s is still running, skipped run at %s.\n", job.Name, now.Format("15:04:05"))
+		return nil, domain.ErrJobRunning
+	}
+	defer job.running.Store(false)
+
+	run := &domain.JobRun{
+		JobName:   job.Name,
+		Status:    domain.JobStatusRunning,
+		StartedAt: now,
+	}
+	if err := s.repo.CreateRun(run, nil); err != nil {
+		log.Printf("[SCHEDULER]: Failed to record run of %s: %v.\n", job.Name, err)
+	}
+
+	backoff := job.Backoff
+	if backoff <= 0 {
+		backoff = 5 * time.Second
+	}
+
+	var result JobResult
+	var err error
+	for attempt := 0; attempt <= maxRetries; attempt++ {
+		run.Attempts = attempt + 1
+		result, err = s.attempt(job, now)
+		if err == nil || errors.Is(err, ErrJobSkipped) || s.ctx.Err() != nil {
+			break
+		}
+
+		log.Printf("[SCHEDULER]: Job %s attempt %d failed: %v.\n", job.Name, run.Attempts, err)
+		if attempt == maxRetries {
+			break
+		}
+
+		select {
+		case <-s.ctx.Done():
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, maxBackoff)
+	}
+
+	finished := time.Now()
+	run.FinishedAt = &finished
+	run.DurationMs = finished.Sub(run.StartedAt).Milliseconds()
+	run.Processed = result.Processed
+	run.Message = result.Message
+	switch {
+	case err == nil:
+		run.Status = domain.JobStatusSuccess
+	case errors.Is(err, ErrJobSkipped):
+		run.Status = domain.JobStatusSkipped
+	default:
+		run.Status = domain.JobStatusFailed
+		run.Error = err.Error()
+	}
+
+	if err := s.repo.UpdateRun(run, nil); err != nil {
+		log.Printf("[SCHEDULER]: Failed to record run of %s: %v.\n", job.Name, err)
+	}
+	return run, nil
+}
+
+func (s *Scheduler) attempt(job *scheduledJob, now time.Time) (result JobResult, err error) {
+	ctx := s.ctx
+	if job.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, job.Timeout)
+		defer cancel()
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+
+	return job.Run(ctx, now)
+}
+
+func (s *Scheduler) Status() []domain.JobStatusResponse {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	now := time.Now()
+	var result []domain.JobStatusResponse
+	for _, job := range s.jobs {
+		status := domain.JobStatusResponse{
+			Name:     job.Name,
+			Schedule: job.Schedule.String(),
+			Running:  job.running.Load(),
+		}
+		if s.started {
+			next := job.Schedule.Next(now)
+			status.NextRun = &next
+		}
+		if last, err := s.repo.GetLastRun(job.Name); err == nil {
+			status.LastRun = last
+		}
+		result = append(result, status)
+	}
+
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result
+}
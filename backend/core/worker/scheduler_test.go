@@ -0,0 +1,176 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"trade-tracker/core/domain"
+
+	"gorm.io/gorm"
+)
+
+type memoryJobRepo struct {
+	mu   sync.Mutex
+	runs []domain.JobRun
+}
+
+func (r *memoryJobRepo) CreateRun(run *domain.JobRun, trx *gorm.DB) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	run.ID = uint(len(r.runs) + 1)
+	r.runs = append(r.runs, *run)
+	return nil
+}
+
+func (r *memoryJobRepo) UpdateRun(run *domain.JobRun, trx *gorm.DB) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.runs[run.ID-1] = *run
+	return nil
+}
+
+func (r *memoryJobRepo) GetRuns(jobName string, limit int) ([]domain.JobRun, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]domain.JobRun(nil), r.runs...), nil
+}
+
+func (r *memoryJobRepo) GetLastRun(jobName string) (*domain.JobRun, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(r.runs) == 0 {
+		return nil, nil
+	}
+	run := r.runs[len(r.runs)-1]
+	return &run, nil
+}
+
+func TestTrigger(t *testing.T) {
+	tests := []struct {
+		name          string
+		run           JobFunc
+		wantStatus    string
+		wantProcessed int
+		wantError     string
+	}{
+		{
+			name: "success",
+			run: func(ctx context.Context, now time.Time) (JobResult, error) {
+				return JobResult{Processed: 3, Message: "3 done."}, nil
+			},
+			wantStatus:    domain.JobStatusSuccess,
+			wantProcessed: 3,
+		},
+		{
+			name: "skipped",
+			run: func(ctx context.Context, now time.Time) (JobResult, error) {
+				return JobResult{Message: "Nothing to do."}, ErrJobSkipped
+			},
+			wantStatus: domain.JobStatusSkipped,
+		},
+		{
+			name: "failed",
+			run: func(ctx context.Context, now time.Time) (JobResult, error) {
+				return JobResult{}, errors.New("boom")
+			},
+			wantStatus: domain.JobStatusFailed,
+			wantError:  "boom",
+		},
+		{
+			name: "panic",
+			run: func(ctx context.Context, now time.Time) (JobResult, error) {
+				panic("boom")
+			},
+			wantStatus: domain.JobStatusFailed,
+			wantError:  "panic: boom",
+		},
+		{
+			name: "timeout",
+			run: func(ctx context.Context, now time.Time) (JobResult, error) {
+				<-ctx.Done()
+				return JobResult{}, ctx.Err()
+			},
+			wantStatus: domain.JobStatusFailed,
+			wantError:  context.DeadlineExceeded.Error(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &memoryJobRepo{}
+			scheduler := NewScheduler(repo)
+			err := scheduler.Register(Job{
+				Name:       "test",
+				Schedule:   Every(time.Hour),
+				Run:        tt.run,
+				MaxRetries: 3,
+				Timeout:    10 * time.Millisecond,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			run, err := scheduler.Trigger("test", time.Now())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if run.Status != tt.wantStatus || run.Processed != tt.wantProcessed || run.Error != tt.wantError {
+				t.Errorf("run = %s/%d/%q, want %s/%d/%q", run.Status, run.Processed, run.Error, tt.wantStatus, tt.wantProcessed, tt.wantError)
+			}
+			if run.Attempts != 1 {
+				t.Errorf("Attempts = %d, want a single attempt for triggered runs", run.Attempts)
+			}
+
+			stored, _ := repo.GetLastRun("test")
+			if stored == nil || stored.Status != tt.wantStatus || stored.FinishedAt == nil {
+				t.Errorf("stored run = %+v, want a finished %s run", stored, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestTriggerOverlap(t *testing.T) {
+	scheduler := NewScheduler(&memoryJobRepo{})
+	started := make(chan struct{})
+	release := make(chan struct{})
+
+	err := scheduler.Register(Job{
+		Name:     "slow",
+		Schedule: Every(time.Hour),
+		Run: func(ctx context.Context, now time.Time) (JobResult, error) {
+			close(started)
+			<-release
+			return JobResult{}, nil
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := scheduler.Trigger("slow", time.Now())
+		done <- err
+	}()
+	<-started
+
+	if _, err := scheduler.Trigger("slow", time.Now()); !errors.Is(err, domain.ErrJobRunning) {
+		t.Errorf("overlapping Trigger error = %v, want ErrJobRunning", err)
+	}
+
+	close(release)
+	if err := <-done; err != nil {
+		t.Errorf("first Trigger error = %v", err)
+	}
+
+	if _, err := scheduler.Trigger("missing", time.Now()); !errors.Is(err, domain.ErrItemNotFound) {
+		t.Errorf("unknown job error = %v, want ErrItemNotFound", err)
+	}
+}
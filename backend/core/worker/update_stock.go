@@ -1,24 +1,51 @@
 package worker
 
 import (
+	"context"
 	"fmt"
 	"time"
-	"trade-tracker/pkg/utils/market"
+	"trade-tracker/core/integrations/providers"
+	"trade-tracker/pkg/utils/calendar"
 
 	"github.com/VYDev37/go-tvscanner-api/pkg/scanner"
 )
 
-func UpdateStock(holidays market.CheckedList, now time.Time, isInit bool) bool {
-	if !market.IsMarketOpen(holidays, now) && !isInit {
-		return false
+// UpdateStock refreshes scanner.GlobalStore and returns the number of assets written.
+// It returns ErrJobSkipped when the market is closed, unless isInit is set.
+// The scanner client takes no context, so when ctx is done first the fetch is abandoned
+// and its result is never written to the store.
+func UpdateStock(ctx context.Context, exchange *calendar.Exchange, now time.Time, isInit bool) (int, error) {
+	if !exchange.IsOpen(now) && !isInit {
+		return 0, ErrJobSkipped
 	}
 
-	opts := scanner.FetcherOptions{Market: "indonesia", Limit: 1000}
-	data, err := scanner.FetchStockData(opts)
-	if err == nil {
-		scanner.GlobalStore.UpdateData(data)
-		fmt.Println("[WORKER]: Stock data updated at", time.Now().Format("15:04:05"))
+	fetched := make(chan func() (int, error), 1)
+	go func() {
+		opts := scanner.FetcherOptions{Market: "indonesia", Limit: 1000}
+		data, err := scanner.FetchStockData(opts)
+		fetched <- func() (int, error) {
+			if err != nil {
+				return 0, err
+			}
+			scanner.GlobalStore.UpdateData(data)
+			return len(data), nil
+		}
+	}()
+
+	var write func() (int, error)
+	select {
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	case write = <-fetched:
+	}
+
+	updated, err := write()
+	if err != nil {
+		return 0, err
 	}
 
-	return true
+	providers.MarkStoreUpdated(time.Now())
+	fmt.Println("[WORKER]: Stock data updated at", time.Now().Format("15:04:05"))
+
+	return updated, nil
 }
@@ -5,14 +5,40 @@ import (
 	"os"
 	"strings"
 	"time"
+	"trade-tracker/core/domain"
 	"trade-tracker/pkg/utils/auth"
 
 	"github.com/gofiber/fiber/v3"
 	"github.com/golang-jwt/jwt/v5"
 )
 
-func AuthMiddleware() fiber.Handler {
+type SessionValidator interface {
+	IsSessionActive(sessionID uint, userID uint64) bool
+}
+
+type APIKeyValidator interface {
+	Authenticate(rawKey string, ip string) (uint64, []string, error)
+}
+
+// AuthMiddleware accepts a session JWT (cookie or bearer) or a personal API key
+// (X-API-Key or bearer). API key requests also get their scopes in Locals "scopes".
+func AuthMiddleware(sessions SessionValidator, keys APIKeyValidator) fiber.Handler {
 	return func(c fiber.Ctx) error {
+		apiKey := c.Get("X-API-Key")
+		if header := c.Get("Authorization"); apiKey == "" && strings.HasPrefix(header, "Bearer "+domain.APIKeyPrefix) {
+			apiKey = strings.TrimPrefix(header, "Bearer ")
+		}
+		if apiKey != "" {
+			userID, scopes, err := keys.Authenticate(apiKey, c.IP())
+			if err != nil {
+				return c.Status(401).JSON(fiber.Map{"message": "Invalid API key."})
+			}
+
+			c.Locals("user_id", userID)
+			c.Locals("scopes", scopes)
+			return c.Next()
+		}
+
 		tokenStr := c.Cookies("token")
 
 		if tokenStr == "" {
@@ -52,7 +78,12 @@ func AuthMiddleware() fiber.Handler {
 			return c.Status(401).JSON(fiber.Map{"message": "User ID missing from claims."})
 		}
 
+		if claims.SessionID == 0 || !sessions.IsSessionActive(claims.SessionID, uint64(claims.UserID)) {
+			return c.Status(401).JSON(fiber.Map{"message": "Session has been revoked."})
+		}
+
 		c.Locals("user_id", uint64(claims.UserID))
+		c.Locals("session_id", claims.SessionID)
 		return c.Next()
 	}
 }
@@ -0,0 +1,117 @@
+package middleware
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"log"
+	"trade-tracker/core/domain"
+	"trade-tracker/pkg/utils/format"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+const IdempotencyHeader = "Idempotency-Key"
+
+// redactedResponse replaces the stored body of routes marked with SecretResponse.
+var redactedResponse = []byte(`{"message":"This request already succeeded. Its response held one-time secrets, which are not stored and cannot be shown again."}`)
+
+type IdempotencyStore interface {
+	Claim(userID uint64, key, requestHash string) (*domain.IdempotencyRecord, bool, error)
+	Complete(id uint, status int, contentType string, body []byte) error
+	Release(id uint) error
+}
+
+// Idempotency replays the stored response when a mutating request repeats an Idempotency-Key.
+// Reusing a key for a different method, path or body answers 422, and a key whose first request
+// is still running answers 409. Responses with a 5xx status are not stored so the client can retry.
+// It must run after AuthMiddleware; requests without the header pass through.
+func Idempotency(store IdempotencyStore) fiber.Handler {
+	return func(c fiber.Ctx) error {
+		key := c.Get(IdempotencyHeader)
+		if key == "" || !isMutating(c.Method()) {
+			return c.Next()
+		}
+		if len(key) > 255 {
+			return c.Status(400).JSON(fiber.Map{"message": "Idempotency-Key must be at most 255 characters."})
+		}
+
+		uid, ok := c.Locals("user_id").(uint64)
+		if !ok {
+			return c.Status(401).JSON(fiber.Map{"message": "Unauthorized."})
+		}
+
+		hash := sha256.New()
+		hash.Write([]byte(c.Method() + " " + c.Path() + "\n"))
+		hash.Write(c.Body())
+		requestHash := hex.EncodeToString(hash.Sum(nil))
+
+		record, claimed, err := store.Claim(uid, key, requestHash)
+		if err != nil {
+			if errors.Is(err, domain.ErrIdempotencyInProgress) {
+				return format.ErrorResponse(c, err)
+			}
+			log.Printf("Error when trying to claim idempotency key: %v.\n", err)
+			return c.Status(500).JSON(fiber.Map{"message": "Internal server error."})
+		}
+
+		if !claimed {
+			if record.RequestHash != requestHash {
+				return format.ErrorResponse(c, domain.ErrIdempotencyMismatch)
+			}
+			if record.StatusCode == 0 {
+				return format.ErrorResponse(c, domain.ErrIdempotencyInProgress)
+			}
+			c.Set("Idempotent-Replayed", "true")
+			if record.ContentType != "" {
+				c.Set(fiber.HeaderContentType, record.ContentType)
+			}
+			return c.Status(record.StatusCode).Send(record.ResponseBody)
+		}
+
+		if err := c.Next(); err != nil {
+			releaseKey(store, record.ID)
+			return err
+		}
+
+		status := c.Response().StatusCode()
+		if status >= 500 {
+			releaseKey(store, record.ID)
+			return nil
+		}
+
+		body := append([]byte(nil), c.Response().Body()...)
+		contentType := string(c.Response().Header.ContentType())
+		if secret, _ := c.Locals("secret_response").(bool); secret {
+			body, contentType = redactedResponse, fiber.MIMEApplicationJSON
+		}
+		if err := store.Complete(record.ID, status, contentType, body); err != nil {
+			log.Printf("Error when trying to store idempotent response: %v.\n", err)
+		}
+		return nil
+	}
+}
+
+// SecretResponse marks a route whose response carries one-time secrets, such as a new API key,
+// a TOTP secret or recovery codes. Idempotency then keeps only the status of its response,
+// and a replay answers that status with a notice instead of the secrets.
+func SecretResponse() fiber.Handler {
+	return func(c fiber.Ctx) error {
+		c.Locals("secret_response", true)
+		return c.Next()
+	}
+}
+
+func releaseKey(store IdempotencyStore, id uint) {
+	if err := store.Release(id); err != nil {
+		log.Printf("Error when trying to release idempotency key: %v.\n", err)
+	}
+}
+
+func isMutating(method string) bool {
+	switch method {
+	case fiber.MethodPost, fiber.MethodPut, fiber.MethodPatch, fiber.MethodDelete:
+		return true
+	}
+	return false
+}
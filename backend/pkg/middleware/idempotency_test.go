@@ -0,0 +1,240 @@
+package middleware
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"trade-tracker/core/domain"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+type memoryIdempotencyStore struct {
+	mu      sync.Mutex
+	records map[string]*domain.IdempotencyRecord
+}
+
+func newMemoryIdempotencyStore() *memoryIdempotencyStore {
+	return &memoryIdempotencyStore{records: make(map[string]*domain.IdempotencyRecord)}
+}
+
+func (s *memoryIdempotencyStore) Claim(userID uint64, key, requestHash string) (*domain.IdempotencyRecord, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if existing, found := s.records[key]; found {
+		return existing, false, nil
+	}
+	record := &domain.IdempotencyRecord{ID: uint(len(s.records) + 1), UserID: userID, Key: key, RequestHash: requestHash}
+	s.records[key] = record
+	return record, true, nil
+}
+
+func (s *memoryIdempotencyStore) find(id uint) (string, *domain.IdempotencyRecord) {
+	for key, record := range s.records {
+		if record.ID == id {
+			return key, record
+		}
+	}
+	return "", nil
+}
+
+func (s *memoryIdempotencyStore) Complete(id uint, status int, contentType string, body []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, record := s.find(id); record != nil {
+		record.StatusCode = status
+		record.ContentType = contentType
+		record.ResponseBody = body
+	}
+	return nil
+}
+
+func (s *memoryIdempotencyStore) Release(id uint) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if key, _ := s.find(id); key != "" {
+		delete(s.records, key)
+	}
+	return nil
+}
+
+// newIdempotencyTestApp counts handler calls per path, so replays can be told apart from new runs.
+func newIdempotencyTestApp(store IdempotencyStore) (*fiber.App, map[string]int) {
+	calls := make(map[string]int)
+	app := fiber.New()
+	app.Use(func(c fiber.Ctx) error {
+		c.Locals("user_id", uint64(1))
+		return c.Next()
+	})
+	app.Use(Idempotency(store))
+
+	app.Post("/items", func(c fiber.Ctx) error {
+		calls["/items"]++
+		return c.Status(fiber.StatusCreated).JSON(fiber.Map{"call": calls["/items"]})
+	})
+	app.Post("/other", func(c fiber.Ctx) error {
+		calls["/other"]++
+		return c.Status(fiber.StatusCreated).JSON(fiber.Map{"call": calls["/other"]})
+	})
+	app.Post("/fail", func(c fiber.Ctx) error {
+		calls["/fail"]++
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"message": "boom"})
+	})
+	app.Post("/secret", SecretResponse(), func(c fiber.Ctx) error {
+		calls["/secret"]++
+		return c.Status(fiber.StatusCreated).JSON(fiber.Map{"key": "tpt_supersecret"})
+	})
+	return app, calls
+}
+
+type idempotentCall struct {
+	method, path, key, body string
+}
+
+func send(t *testing.T, app *fiber.App, call idempotentCall) (int, string, string) {
+	t.Helper()
+
+	req := httptest.NewRequest(call.method, call.path, strings.NewReader(call.body))
+	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	if call.key != "" {
+		req.Header.Set(IdempotencyHeader, call.key)
+	}
+
+	res, err := app.Test(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(res.Body)
+	return res.StatusCode, string(body), res.Header.Get("Idempotent-Replayed")
+}
+
+func TestIdempotency(t *testing.T) {
+	tests := []struct {
+		name         string
+		first        idempotentCall
+		repeat       idempotentCall
+		wantStatus   int
+		wantReplayed bool
+		wantCalls    int
+	}{
+		{
+			name:         "same request is replayed",
+			first:        idempotentCall{"POST", "/items", "k1", `{"a":1}`},
+			repeat:       idempotentCall{"POST", "/items", "k1", `{"a":1}`},
+			wantStatus:   fiber.StatusCreated,
+			wantReplayed: true,
+			wantCalls:    1,
+		},
+		{
+			name:       "different body is rejected",
+			first:      idempotentCall{"POST", "/items", "k1", `{"a":1}`},
+			repeat:     idempotentCall{"POST", "/items", "k1", `{"a":2}`},
+			wantStatus: fiber.StatusUnprocessableEntity,
+			wantCalls:  1,
+		},
+		{
+			name:       "different path is rejected",
+			first:      idempotentCall{"POST", "/items", "k1", `{"a":1}`},
+			repeat:     idempotentCall{"POST", "/other", "k1", `{"a":1}`},
+			wantStatus: fiber.StatusUnprocessableEntity,
+			wantCalls:  0,
+		},
+		{
+			name:       "other key runs again",
+			first:      idempotentCall{"POST", "/items", "k1", `{"a":1}`},
+			repeat:     idempotentCall{"POST", "/items", "k2", `{"a":1}`},
+			wantStatus: fiber.StatusCreated,
+			wantCalls:  2,
+		},
+		{
+			name:       "without key runs again",
+			first:      idempotentCall{"POST", "/items", "", `{"a":1}`},
+			repeat:     idempotentCall{"POST", "/items", "", `{"a":1}`},
+			wantStatus: fiber.StatusCreated,
+			wantCalls:  2,
+		},
+		{
+			name:       "server errors are not stored",
+			first:      idempotentCall{"POST", "/fail", "k1", `{}`},
+			repeat:     idempotentCall{"POST", "/fail", "k1", `{}`},
+			wantStatus: fiber.StatusInternalServerError,
+			wantCalls:  2,
+		},
+		{
+			name:         "secret response replays only the status",
+			first:        idempotentCall{"POST", "/secret", "k1", `{}`},
+			repeat:       idempotentCall{"POST", "/secret", "k1", `{}`},
+			wantStatus:   fiber.StatusCreated,
+			wantReplayed: true,
+			wantCalls:    1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app, calls := newIdempotencyTestApp(newMemoryIdempotencyStore())
+
+			send(t, app, tt.first)
+			status, _, replayed := send(t, app, tt.repeat)
+
+			if status != tt.wantStatus {
+				t.Errorf("status = %d, want %d", status, tt.wantStatus)
+			}
+			if (replayed == "true") != tt.wantReplayed {
+				t.Errorf("Idempotent-Replayed = %q, want %v", replayed, tt.wantReplayed)
+			}
+			if got := calls[tt.repeat.path]; got != tt.wantCalls {
+				t.Errorf("handler ran %d times, want %d", got, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestIdempotencyInProgress(t *testing.T) {
+	store := newMemoryIdempotencyStore()
+	app, calls := newIdempotencyTestApp(store)
+
+	// a claimed key without a status belongs to a request that is still running
+	hash := sha256.Sum256([]byte("POST /items\n{}"))
+	store.Claim(1, "k1", hex.EncodeToString(hash[:]))
+	status, _, _ := send(t, app, idempotentCall{"POST", "/items", "k1", `{}`})
+
+	if status != fiber.StatusConflict {
+		t.Errorf("status = %d, want %d", status, fiber.StatusConflict)
+	}
+	if calls["/items"] != 0 {
+		t.Errorf("handler ran %d times, want 0", calls["/items"])
+	}
+}
+
+func TestIdempotencyDoesNotStoreSecrets(t *testing.T) {
+	store := newMemoryIdempotencyStore()
+	app, _ := newIdempotencyTestApp(store)
+
+	_, firstBody, _ := send(t, app, idempotentCall{"POST", "/secret", "k1", `{}`})
+	if !strings.Contains(firstBody, "tpt_supersecret") {
+		t.Fatalf("first response = %s, want the secret", firstBody)
+	}
+
+	for _, record := range store.records {
+		if strings.Contains(string(record.ResponseBody), "tpt_supersecret") {
+			t.Errorf("stored body = %s, want it without the secret", record.ResponseBody)
+		}
+		if record.StatusCode != fiber.StatusCreated {
+			t.Errorf("stored status = %d, want %d", record.StatusCode, fiber.StatusCreated)
+		}
+	}
+
+	_, replayBody, _ := send(t, app, idempotentCall{"POST", "/secret", "k1", `{}`})
+	if strings.Contains(replayBody, "tpt_supersecret") {
+		t.Errorf("replayed body = %s, want it without the secret", replayBody)
+	}
+}
@@ -0,0 +1,36 @@
+package middleware
+
+import (
+	"slices"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+type AccessChecker interface {
+	GetAccess(userID uint64) (role string, locked bool, err error)
+}
+
+// RequireRole lets through unlocked users whose role is one of roles.
+// It must run after AuthMiddleware.
+func RequireRole(users AccessChecker, roles ...string) fiber.Handler {
+	return func(c fiber.Ctx) error {
+		uid, ok := c.Locals("user_id").(uint64)
+		if !ok {
+			return c.Status(401).JSON(fiber.Map{"message": "Unauthorized."})
+		}
+
+		role, locked, err := users.GetAccess(uid)
+		if err != nil {
+			return c.Status(401).JSON(fiber.Map{"message": "Unauthorized."})
+		}
+		if locked {
+			return c.Status(403).JSON(fiber.Map{"message": "This account has been locked."})
+		}
+		if !slices.Contains(roles, role) {
+			return c.Status(403).JSON(fiber.Map{"message": "Forbidden."})
+		}
+
+		c.Locals("role", role)
+		return c.Next()
+	}
+}
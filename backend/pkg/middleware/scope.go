@@ -0,0 +1,35 @@
+package middleware
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// RequireScope lets session-authenticated users through and requires API keys to carry
+// at least one of the given scopes. It must run after AuthMiddleware.
+func RequireScope(anyOf ...string) fiber.Handler {
+	return func(c fiber.Ctx) error {
+		scopes, isAPIKey := c.Locals("scopes").([]string)
+		if !isAPIKey {
+			return c.Next()
+		}
+		for _, scope := range anyOf {
+			if slices.Contains(scopes, scope) {
+				return c.Next()
+			}
+		}
+		return c.Status(403).JSON(fiber.Map{"message": "API key requires one of these scopes: " + strings.Join(anyOf, ", ") + "."})
+	}
+}
+
+// SessionOnly rejects API keys, for routes that manage the account itself.
+func SessionOnly() fiber.Handler {
+	return func(c fiber.Ctx) error {
+		if _, isAPIKey := c.Locals("scopes").([]string); isAPIKey {
+			return c.Status(403).JSON(fiber.Map{"message": "This endpoint cannot be used with an API key."})
+		}
+		return c.Next()
+	}
+}
@@ -0,0 +1,32 @@
+package middleware
+
+import (
+	"os"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+type VerificationChecker interface {
+	IsVerified(userID uint64) bool
+}
+
+// VerifiedMiddleware blocks users who have not verified their email when
+// REQUIRE_VERIFIED_EMAIL is "true". It must run after AuthMiddleware.
+func VerifiedMiddleware(users VerificationChecker) fiber.Handler {
+	required := os.Getenv("REQUIRE_VERIFIED_EMAIL") == "true"
+
+	return func(c fiber.Ctx) error {
+		if !required {
+			return c.Next()
+		}
+
+		uid, ok := c.Locals("user_id").(uint64)
+		if !ok {
+			return c.Status(401).JSON(fiber.Map{"message": "Unauthorized."})
+		}
+		if !users.IsVerified(uid) {
+			return c.Status(403).JSON(fiber.Map{"message": "Please verify your email address first."})
+		}
+		return c.Next()
+	}
+}
@@ -0,0 +1,79 @@
+package middleware
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// signatureWindow is how far a signed request's timestamp may drift from server time.
+const signatureWindow = 5 * time.Minute
+
+// NonceStore is implemented by services.IdempotencyService. Used nonces must be shared by
+// every server instance, or a captured request could be replayed against another one.
+type NonceStore interface {
+	// UseWorkerNonce reports false when the nonce was already used.
+	UseWorkerNonce(nonce string, expiresAt time.Time) (bool, error)
+}
+
+// WorkerSignature builds the hex HMAC-SHA256 expected in X-Worker-Signature for
+// "<timestamp>.<nonce>.<METHOD>.<path with query>".
+func WorkerSignature(secret, timestamp, nonce, method, uri string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "." + nonce + "." + strings.ToUpper(method) + "." + uri))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// WorkerAuthMiddleware protects worker endpoints meant for external cron callers.
+// Requests must be signed with WORKER_SECRET through X-Worker-Timestamp, X-Worker-Nonce
+// and X-Worker-Signature (see WorkerSignature). They are rejected outside the signature
+// window or when their nonce is reused. When no secret is configured every request is rejected.
+func WorkerAuthMiddleware(nonces NonceStore) fiber.Handler {
+	secret := os.Getenv("WORKER_SECRET")
+
+	return func(c fiber.Ctx) error {
+		if secret == "" {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"message": "Worker endpoint is not configured."})
+		}
+
+		timestamp := c.Get("X-Worker-Timestamp")
+		nonce := c.Get("X-Worker-Nonce")
+		signature := c.Get("X-Worker-Signature")
+		if timestamp == "" || nonce == "" || len(nonce) > 255 || signature == "" {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"message": "Unauthorized."})
+		}
+
+		unix, err := strconv.ParseInt(timestamp, 10, 64)
+		if err != nil {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"message": "Invalid timestamp."})
+		}
+
+		now := time.Now()
+		drift := now.Sub(time.Unix(unix, 0))
+		if drift > signatureWindow || drift < -signatureWindow {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"message": "Request expired."})
+		}
+
+		expected := WorkerSignature(secret, timestamp, nonce, c.Method(), c.OriginalURL())
+		if !hmac.Equal([]byte(expected), []byte(strings.ToLower(signature))) {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"message": "Invalid signature."})
+		}
+
+		// a nonce is only checked until its timestamp has left the window on either side
+		fresh, err := nonces.UseWorkerNonce(nonce, now.Add(2*signatureWindow))
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"message": "Could not verify the request."})
+		}
+		if !fresh {
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{"message": "Request already processed."})
+		}
+
+		return c.Next()
+	}
+}
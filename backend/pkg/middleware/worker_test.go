@@ -0,0 +1,150 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// memoryNonceStore stands in for the database table shared by all instances.
+type memoryNonceStore struct {
+	mu   sync.Mutex
+	used map[string]time.Time
+	err  error
+}
+
+func (s *memoryNonceStore) UseWorkerNonce(nonce string, expiresAt time.Time) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.err != nil {
+		return false, s.err
+	}
+	if _, found := s.used[nonce]; found {
+		return false, nil
+	}
+	s.used[nonce] = expiresAt
+	return true, nil
+}
+
+func newWorkerTestApp(t *testing.T, secret string, nonces NonceStore) *fiber.App {
+	t.Helper()
+	t.Setenv("WORKER_SECRET", secret)
+
+	app := fiber.New()
+	app.Get("/worker/run", WorkerAuthMiddleware(nonces), func(c fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusOK)
+	})
+	return app
+}
+
+func TestWorkerAuthMiddleware(t *testing.T) {
+	const secret = "s3cret"
+	now := time.Now()
+
+	sign := func(ts time.Time, nonce, key string) map[string]string {
+		timestamp := strconv.FormatInt(ts.Unix(), 10)
+		return map[string]string{
+			"X-Worker-Timestamp": timestamp,
+			"X-Worker-Nonce":     nonce,
+			"X-Worker-Signature": WorkerSignature(key, timestamp, nonce, "GET", "/worker/run"),
+		}
+	}
+
+	tests := []struct {
+		name    string
+		headers map[string]string
+		want    int
+	}{
+		{"signed", sign(now, "n1", secret), fiber.StatusOK},
+		{"nonce reused", sign(now, "n1", secret), fiber.StatusConflict},
+		{"wrong secret", sign(now, "n2", "other"), fiber.StatusUnauthorized},
+		{"expired", sign(now.Add(-10*time.Minute), "n3", secret), fiber.StatusUnauthorized},
+		{"from the future", sign(now.Add(10*time.Minute), "n4", secret), fiber.StatusUnauthorized},
+		{"bearer secret", map[string]string{"Authorization": "Bearer " + secret}, fiber.StatusUnauthorized},
+		{"unsigned", nil, fiber.StatusUnauthorized},
+	}
+
+	app := newWorkerTestApp(t, secret, &memoryNonceStore{used: make(map[string]time.Time)})
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/worker/run", nil)
+			for k, v := range tt.headers {
+				req.Header.Set(k, v)
+			}
+
+			res, err := app.Test(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if res.StatusCode != tt.want {
+				t.Errorf("status = %d, want %d", res.StatusCode, tt.want)
+			}
+		})
+	}
+}
+
+func TestWorkerAuthMiddlewareWithoutSecret(t *testing.T) {
+	app := newWorkerTestApp(t, "", &memoryNonceStore{used: make(map[string]time.Time)})
+
+	res, err := app.Test(httptest.NewRequest("GET", "/worker/run", nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.StatusCode != fiber.StatusServiceUnavailable {
+		t.Errorf("status = %d, want %d", res.StatusCode, fiber.StatusServiceUnavailable)
+	}
+}
+
+func TestWorkerAuthMiddlewareSharesNonces(t *testing.T) {
+	const secret = "s3cret"
+	// two instances, as on serverless hosting, backed by the same store
+	store := &memoryNonceStore{used: make(map[string]time.Time)}
+	first := newWorkerTestApp(t, secret, store)
+	second := newWorkerTestApp(t, secret, store)
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	signed := func() *http.Request {
+		req := httptest.NewRequest("GET", "/worker/run", nil)
+		req.Header.Set("X-Worker-Timestamp", timestamp)
+		req.Header.Set("X-Worker-Nonce", "n1")
+		req.Header.Set("X-Worker-Signature", WorkerSignature(secret, timestamp, "n1", "GET", "/worker/run"))
+		return req
+	}
+
+	tests := []struct {
+		name string
+		app  *fiber.App
+		want int
+	}{
+		{"first use", first, fiber.StatusOK},
+		{"replayed on another instance", second, fiber.StatusConflict},
+	}
+	for _, tt := range tests {
+		res, err := tt.app.Test(signed())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.StatusCode != tt.want {
+			t.Errorf("%s: status = %d, want %d", tt.name, res.StatusCode, tt.want)
+		}
+	}
+
+	store.err = errors.New("database down")
+	req := signed()
+	req.Header.Set("X-Worker-Nonce", "n2")
+	req.Header.Set("X-Worker-Signature", WorkerSignature(secret, timestamp, "n2", "GET", "/worker/run"))
+	res, err := first.Test(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.StatusCode != fiber.StatusInternalServerError {
+		t.Errorf("store error: status = %d, want %d", res.StatusCode, fiber.StatusInternalServerError)
+	}
+}
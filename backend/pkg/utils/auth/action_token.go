@@ -0,0 +1,75 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	PurposeVerifyEmail   = "verify_email"
+	PurposeResetPassword = "reset_password"
+	PurposeLoginTwoStep  = "login_2fa"
+
+	VerifyEmailTTL   = 24 * time.Hour
+	ResetPasswordTTL = 30 * time.Minute
+	LoginTwoStepTTL  = 5 * time.Minute
+)
+
+// ActionClaims back single-purpose links sent by email. Fingerprint ties the token to
+// the state it acts on (e.g. the current password hash), so it stops working once used.
+type ActionClaims struct {
+	jwt.RegisteredClaims
+
+	UserID      uint   `json:"user_id"`
+	Purpose     string `json:"purpose"`
+	Fingerprint string `json:"fp"`
+}
+
+// Fingerprint shortens a value so it can be embedded in a token without leaking it.
+func Fingerprint(value string) string {
+	return HashToken(value)[:16]
+}
+
+func GenerateActionToken(userID uint, purpose, fingerprint string, ttl time.Duration) (string, error) {
+	secret := os.Getenv("JWT_SECRET")
+	if secret == "" {
+		return "", errors.New("JWT_SECRET is not set in environment variables")
+	}
+
+	now := time.Now()
+	claims := ActionClaims{
+		UserID:      userID,
+		Purpose:     purpose,
+		Fingerprint: fingerprint,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
+			Issuer:    "trade-tracker",
+			IssuedAt:  jwt.NewNumericDate(now),
+		},
+	}
+
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(secret))
+}
+
+// ParseActionToken validates the signature, expiry and purpose of an action token.
+func ParseActionToken(tokenStr, purpose string) (*ActionClaims, error) {
+	var claims ActionClaims
+	token, err := jwt.ParseWithClaims(tokenStr, &claims, func(t *jwt.Token) (interface{}, error) {
+		if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", t.Header["alg"])
+		}
+		return []byte(os.Getenv("JWT_SECRET")), nil
+	})
+	if err != nil || !token.Valid {
+		return nil, errors.New("invalid action token")
+	}
+
+	if claims.Purpose != purpose || claims.UserID == 0 {
+		return nil, errors.New("invalid action token")
+	}
+	return &claims, nil
+}
@@ -0,0 +1,119 @@
+package auth
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const testSecret = "test-secret"
+
+func TestGenerateToken(t *testing.T) {
+	t.Setenv("JWT_SECRET", testSecret)
+
+	signed, expiresAt, err := GenerateToken(7, 42)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ttl := time.Until(expiresAt); ttl <= AccessTokenTTL-time.Minute || ttl > AccessTokenTTL {
+		t.Errorf("token expires in %s, want about %s", ttl, AccessTokenTTL)
+	}
+
+	var claims AuthClaims
+	_, err = jwt.ParseWithClaims(signed, &claims, func(t *jwt.Token) (interface{}, error) {
+		return []byte(testSecret), nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if claims.UserID != 7 || claims.SessionID != 42 {
+		t.Errorf("claims = user %d session %d, want user 7 session 42", claims.UserID, claims.SessionID)
+	}
+}
+
+func TestGenerateTokenWithoutSecret(t *testing.T) {
+	t.Setenv("JWT_SECRET", "")
+
+	if _, _, err := GenerateToken(1, 1); err == nil {
+		t.Error("GenerateToken succeeded without JWT_SECRET")
+	}
+	if _, err := GenerateActionToken(1, PurposeVerifyEmail, "", time.Hour); err == nil {
+		t.Error("GenerateActionToken succeeded without JWT_SECRET")
+	}
+}
+
+func TestActionToken(t *testing.T) {
+	t.Setenv("JWT_SECRET", testSecret)
+
+	valid, err := GenerateActionToken(7, PurposeResetPassword, Fingerprint("hash"), time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expired, err := GenerateActionToken(7, PurposeResetPassword, Fingerprint("hash"), -time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	anonymous, err := GenerateActionToken(0, PurposeResetPassword, "", time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	foreign, err := jwt.NewWithClaims(jwt.SigningMethodHS256, ActionClaims{UserID: 7, Purpose: PurposeResetPassword}).SignedString([]byte("other-secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		token   string
+		purpose string
+		wantErr bool
+	}{
+		{"valid", valid, PurposeResetPassword, false},
+		{"other purpose", valid, PurposeVerifyEmail, true},
+		{"expired", expired, PurposeResetPassword, true},
+		{"no user", anonymous, PurposeResetPassword, true},
+		{"signed with another secret", foreign, PurposeResetPassword, true},
+		{"tampered", valid[:len(valid)-2] + "xx", PurposeResetPassword, true},
+		{"garbage", "not-a-token", PurposeResetPassword, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims, err := ParseActionToken(tt.token, tt.purpose)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseActionToken error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && (claims.UserID != 7 || claims.Fingerprint != Fingerprint("hash")) {
+				t.Errorf("claims = %+v", claims)
+			}
+		})
+	}
+}
+
+func TestHashing(t *testing.T) {
+	if got := HashToken("abc"); got != "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad" {
+		t.Errorf("HashToken = %s", got)
+	}
+	if got := Fingerprint("abc"); got != "ba7816bf8f01cfea" {
+		t.Errorf("Fingerprint = %s", got)
+	}
+	if Fingerprint("abc") == Fingerprint("abd") {
+		t.Error("Fingerprint collides for different values")
+	}
+}
+
+func TestGenerateOpaqueToken(t *testing.T) {
+	a, err := GenerateOpaqueToken()
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, _ := GenerateOpaqueToken()
+
+	if len(a) != 43 {
+		t.Errorf("token length = %d, want 43 for 32 random bytes", len(a))
+	}
+	if a == b {
+		t.Error("GenerateOpaqueToken returned the same token twice")
+	}
+}
@@ -0,0 +1,163 @@
+package calendar
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+	_ "time/tzdata" // serverless images do not always ship zoneinfo
+)
+
+const (
+	IDX  = "IDX"
+	NYSE = "NYSE"
+)
+
+type Calendar struct {
+	exchanges map[string]*Exchange
+}
+
+// exchangeFile is one exchange entry in holidays.json. Dates are "DD-MM" grouped by year.
+type exchangeFile struct {
+	Holidays map[string][]string `json:"holidays"`
+	HalfDays map[string][]string `json:"half_days"`
+}
+
+func mustLocation(name string, fallbackOffset int) *time.Location {
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return time.FixedZone(name, fallbackOffset)
+	}
+	return loc
+}
+
+func hm(hour, minute int) int {
+	return hour*60 + minute
+}
+
+func newIDX() *Exchange {
+	regular := []Session{{Open: hm(9, 0), Close: hm(12, 0)}, {Open: hm(13, 30), Close: hm(16, 0)}}
+	friday := []Session{{Open: hm(9, 0), Close: hm(11, 30)}, {Open: hm(14, 0), Close: hm(16, 0)}}
+
+	return newExchange(IDX, mustLocation("Asia/Jakarta", 7*3600), map[time.Weekday][]Session{
+		time.Monday:    regular,
+		time.Tuesday:   regular,
+		time.Wednesday: regular,
+		time.Thursday:  regular,
+		time.Friday:    friday,
+	}, hm(12, 0))
+}
+
+func newNYSE() *Exchange {
+	regular := []Session{{Open: hm(9, 30), Close: hm(16, 0)}}
+
+	return newExchange(NYSE, mustLocation("America/New_York", -5*3600), map[time.Weekday][]Session{
+		time.Monday:    regular,
+		time.Tuesday:   regular,
+		time.Wednesday: regular,
+		time.Thursday:  regular,
+		time.Friday:    regular,
+	}, hm(13, 0))
+}
+
+func New() *Calendar {
+	return &Calendar{exchanges: map[string]*Exchange{
+		IDX:  newIDX(),
+		NYSE: newNYSE(),
+	}}
+}
+
+func parseDates(byYear map[string][]string, loc *time.Location) ([]time.Time, error) {
+	var dates []time.Time
+	for yearStr, days := range byYear {
+		year, err := strconv.Atoi(yearStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid year %q", yearStr)
+		}
+		for _, d := range days {
+			parsed, err := time.ParseInLocation("02-01", d, loc)
+			if err != nil {
+				return nil, fmt.Errorf("invalid date %q in %d", d, year)
+			}
+			dates = append(dates, time.Date(year, parsed.Month(), parsed.Day(), 0, 0, 0, 0, loc))
+		}
+	}
+	return dates, nil
+}
+
+// isLegacyFormat detects the old holidays.json layout, which only held IDX holidays keyed by year.
+func isLegacyFormat(raw map[string]json.RawMessage) bool {
+	for key := range raw {
+		if _, err := strconv.Atoi(key); err != nil {
+			return false
+		}
+	}
+	return len(raw) > 0
+}
+
+// Load builds the calendar and applies the holidays and half days found in path.
+// A missing or broken file is logged and leaves the exchanges without holidays.
+func Load(path string) *Calendar {
+	cal := New()
+
+	file, err := os.ReadFile(path)
+	if err != nil {
+		log.Println("[CALENDAR]: Skipped holidays due to failure when trying to read file:", err)
+		return cal
+	}
+
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(file, &raw); err != nil {
+		log.Println("[CALENDAR]: Skipped holidays due to failure when trying to parse json:", err)
+		return cal
+	}
+
+	entries := make(map[string]exchangeFile)
+	if isLegacyFormat(raw) {
+		var legacy map[string][]string
+		if err := json.Unmarshal(file, &legacy); err != nil {
+			log.Println("[CALENDAR]: Skipped holidays due to failure when trying to parse json:", err)
+			return cal
+		}
+		entries[IDX] = exchangeFile{Holidays: legacy}
+	} else if err := json.Unmarshal(file, &entries); err != nil {
+		log.Println("[CALENDAR]: Skipped holidays due to failure when trying to parse json:", err)
+		return cal
+	}
+
+	for code, entry := range entries {
+		ex, found := cal.exchanges[strings.ToUpper(code)]
+		if !found {
+			log.Printf("[CALENDAR]: Unknown exchange %q in %s, skipped.\n", code, path)
+			continue
+		}
+
+		holidays, err := parseDates(entry.Holidays, ex.Location)
+		if err != nil {
+			log.Printf("[CALENDAR]: Skipped %s holidays: %v.\n", code, err)
+			continue
+		}
+		halfDays, err := parseDates(entry.HalfDays, ex.Location)
+		if err != nil {
+			log.Printf("[CALENDAR]: Skipped %s half days: %v.\n", code, err)
+			continue
+		}
+
+		for _, d := range holidays {
+			ex.addHoliday(d)
+		}
+		for _, d := range halfDays {
+			ex.addHalfDay(d)
+		}
+	}
+
+	return cal
+}
+
+// Exchange returns the exchange registered under code (e.g. "IDX"), or nil.
+func (c *Calendar) Exchange(code string) *Exchange {
+	return c.exchanges[strings.ToUpper(code)]
+}
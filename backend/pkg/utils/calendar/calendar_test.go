@@ -0,0 +1,150 @@
+package calendar
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func loadTestCalendar(t *testing.T, content string) *Calendar {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "holidays.json")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return Load(path)
+}
+
+const testHolidays = `{
+	"IDX": {"holidays": {"2026": ["17-08"]}},
+	"NYSE": {"holidays": {"2026": ["25-12"]}, "half_days": {"2026": ["24-12"]}}
+}`
+
+func TestIsOpen(t *testing.T) {
+	cal := loadTestCalendar(t, testHolidays)
+	idx := cal.Exchange(IDX)
+	nyse := cal.Exchange(NYSE)
+
+	tests := []struct {
+		name string
+		ex   *Exchange
+		at   time.Time
+		want bool
+	}{
+		{"idx morning session", idx, time.Date(2026, 8, 18, 9, 0, 0, 0, idx.Location), true},
+		{"idx before open", idx, time.Date(2026, 8, 18, 8, 59, 0, 0, idx.Location), false},
+		{"idx lunch break", idx, time.Date(2026, 8, 18, 12, 30, 0, 0, idx.Location), false},
+		{"idx afternoon session", idx, time.Date(2026, 8, 18, 15, 59, 0, 0, idx.Location), true},
+		{"idx at close", idx, time.Date(2026, 8, 18, 16, 0, 0, 0, idx.Location), false},
+		{"idx friday break starts early", idx, time.Date(2026, 8, 21, 11, 45, 0, 0, idx.Location), false},
+		{"idx friday afternoon opens late", idx, time.Date(2026, 8, 21, 13, 45, 0, 0, idx.Location), false},
+		{"idx holiday", idx, time.Date(2026, 8, 17, 10, 0, 0, 0, idx.Location), false},
+		{"idx weekend", idx, time.Date(2026, 8, 22, 10, 0, 0, 0, idx.Location), false},
+		{"idx from utc", idx, time.Date(2026, 8, 18, 3, 0, 0, 0, time.UTC), true},
+		{"nyse regular", nyse, time.Date(2026, 12, 23, 15, 0, 0, 0, nyse.Location), true},
+		{"nyse half day before early close", nyse, time.Date(2026, 12, 24, 12, 59, 0, 0, nyse.Location), true},
+		{"nyse half day after early close", nyse, time.Date(2026, 12, 24, 13, 0, 0, 0, nyse.Location), false},
+		{"nyse holiday", nyse, time.Date(2026, 12, 25, 10, 0, 0, 0, nyse.Location), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.ex.IsOpen(tt.at); got != tt.want {
+				t.Errorf("IsOpen(%s) = %v, want %v", tt.at, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNextOpenAndPreviousClose(t *testing.T) {
+	cal := loadTestCalendar(t, testHolidays)
+	idx := cal.Exchange(IDX)
+	at := func(month time.Month, day, hour, minute int) time.Time {
+		return time.Date(2026, month, day, hour, minute, 0, 0, idx.Location)
+	}
+
+	tests := []struct {
+		name      string
+		now       time.Time
+		nextOpen  time.Time
+		prevClose time.Time
+	}{
+		{"lunch break", at(8, 18, 12, 30), at(8, 18, 13, 30), at(8, 18, 12, 0)},
+		{"friday lunch break", at(8, 21, 12, 0), at(8, 21, 14, 0), at(8, 21, 11, 30)},
+		{"weekend", at(8, 22, 10, 0), at(8, 24, 9, 0), at(8, 21, 16, 0)},
+		{"skips holiday", at(8, 16, 10, 0), at(8, 18, 9, 0), at(8, 14, 16, 0)},
+		{"exactly at close", at(8, 18, 16, 0), at(8, 19, 9, 0), at(8, 18, 16, 0)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := idx.NextOpen(tt.now); !got.Equal(tt.nextOpen) {
+				t.Errorf("NextOpen = %s, want %s", got, tt.nextOpen)
+			}
+			if got := idx.PreviousClose(tt.now); !got.Equal(tt.prevClose) {
+				t.Errorf("PreviousClose = %s, want %s", got, tt.prevClose)
+			}
+		})
+	}
+}
+
+func TestTradingDaysBetween(t *testing.T) {
+	cal := loadTestCalendar(t, testHolidays)
+	idx := cal.Exchange(IDX)
+
+	tests := []struct {
+		name     string
+		from, to time.Time
+		want     int
+	}{
+		{"same day", time.Date(2026, 8, 18, 0, 0, 0, 0, idx.Location), time.Date(2026, 8, 18, 23, 0, 0, 0, idx.Location), 0},
+		{"one week with holiday", time.Date(2026, 8, 17, 0, 0, 0, 0, idx.Location), time.Date(2026, 8, 24, 0, 0, 0, 0, idx.Location), 4},
+		{"across weekend", time.Date(2026, 8, 21, 0, 0, 0, 0, idx.Location), time.Date(2026, 8, 25, 0, 0, 0, 0, idx.Location), 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := idx.TradingDaysBetween(tt.from, tt.to); got != tt.want {
+				t.Errorf("TradingDaysBetween = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		holiday time.Time
+		want    bool
+	}{
+		{"exchange format", testHolidays, time.Date(2026, 8, 17, 0, 0, 0, 0, time.UTC), true},
+		{"legacy format", `{"2026": ["17-08"]}`, time.Date(2026, 8, 17, 0, 0, 0, 0, time.UTC), true},
+		{"lowercase exchange code", `{"idx": {"holidays": {"2026": ["17-08"]}}}`, time.Date(2026, 8, 17, 0, 0, 0, 0, time.UTC), true},
+		{"broken date skips exchange", `{"IDX": {"holidays": {"2026": ["17-08", "31-02"]}}}`, time.Date(2026, 8, 17, 0, 0, 0, 0, time.UTC), false},
+		{"broken json", `{"IDX": `, time.Date(2026, 8, 17, 0, 0, 0, 0, time.UTC), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			idx := loadTestCalendar(t, tt.content).Exchange(IDX)
+			if got := idx.IsHoliday(tt.holiday); got != tt.want {
+				t.Errorf("IsHoliday = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHolidaysFile(t *testing.T) {
+	cal := Load(filepath.Join("..", "..", "..", "holidays.json"))
+
+	for _, code := range []string{IDX, NYSE} {
+		for _, year := range []int{2026, 2027} {
+			if !cal.Exchange(code).HasYear(year) {
+				t.Errorf("holidays.json has no %s data for %d", code, year)
+			}
+		}
+	}
+}
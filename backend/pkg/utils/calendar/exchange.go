@@ -0,0 +1,173 @@
+package calendar
+
+import (
+	"log"
+	"sync"
+	"time"
+)
+
+// Session is one continuous trading window, in minutes after local midnight.
+type Session struct {
+	Open  int
+	Close int
+}
+
+type Exchange struct {
+	Code     string
+	Location *time.Location
+	Sessions map[time.Weekday][]Session
+	// HalfDayClose cuts every session of a half day at this minute.
+	HalfDayClose int
+
+	holidays map[string]bool
+	halfDays map[string]bool
+	years    map[int]bool
+
+	warnOnce sync.Map
+}
+
+const dateKey = "2006-01-02"
+
+// maxScanDays bounds NextOpen / PreviousClose so a broken calendar cannot loop forever.
+const maxScanDays = 366
+
+func newExchange(code string, loc *time.Location, sessions map[time.Weekday][]Session, halfDayClose int) *Exchange {
+	return &Exchange{
+		Code:         code,
+		Location:     loc,
+		Sessions:     sessions,
+		HalfDayClose: halfDayClose,
+		holidays:     make(map[string]bool),
+		halfDays:     make(map[string]bool),
+		years:        make(map[int]bool),
+	}
+}
+
+func (e *Exchange) addHoliday(date time.Time) {
+	e.holidays[date.Format(dateKey)] = true
+	e.years[date.Year()] = true
+}
+
+func (e *Exchange) addHalfDay(date time.Time) {
+	e.halfDays[date.Format(dateKey)] = true
+	e.years[date.Year()] = true
+}
+
+// HasYear reports whether holiday data has been loaded for the given year.
+func (e *Exchange) HasYear(year int) bool {
+	return e.years[year]
+}
+
+func (e *Exchange) checkYear(year int) {
+	if e.HasYear(year) {
+		return
+	}
+	if _, warned := e.warnOnce.LoadOrStore(year, true); !warned {
+		log.Printf("[CALENDAR]: No %s holiday data for %d, treating every weekday as a trading day.\n", e.Code, year)
+	}
+}
+
+func (e *Exchange) IsHoliday(t time.Time) bool {
+	local := t.In(e.Location)
+	e.checkYear(local.Year())
+	return e.holidays[local.Format(dateKey)]
+}
+
+func (e *Exchange) IsHalfDay(t time.Time) bool {
+	return e.halfDays[t.In(e.Location).Format(dateKey)]
+}
+
+func (e *Exchange) IsTradingDay(t time.Time) bool {
+	local := t.In(e.Location)
+	return len(e.Sessions[local.Weekday()]) > 0 && !e.IsHoliday(local)
+}
+
+// SessionsOn returns the sessions traded on the day of t, already cut for half days.
+func (e *Exchange) SessionsOn(t time.Time) []Session {
+	if !e.IsTradingDay(t) {
+		return nil
+	}
+
+	sessions := e.Sessions[t.In(e.Location).Weekday()]
+	if !e.IsHalfDay(t) {
+		return sessions
+	}
+
+	var cut []Session
+	for _, s := range sessions {
+		if s.Open >= e.HalfDayClose {
+			break
+		}
+		if s.Close > e.HalfDayClose {
+			s.Close = e.HalfDayClose
+		}
+		cut = append(cut, s)
+	}
+	return cut
+}
+
+func (e *Exchange) IsOpen(t time.Time) bool {
+	local := t.In(e.Location)
+	minute := local.Hour()*60 + local.Minute()
+
+	for _, s := range e.SessionsOn(local) {
+		if minute >= s.Open && minute < s.Close {
+			return true
+		}
+	}
+	return false
+}
+
+func (e *Exchange) at(day time.Time, minute int) time.Time {
+	return time.Date(day.Year(), day.Month(), day.Day(), minute/60, minute%60, 0, 0, e.Location)
+}
+
+// NextOpen returns the start of the first session that opens after t.
+func (e *Exchange) NextOpen(t time.Time) time.Time {
+	local := t.In(e.Location)
+	day := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, e.Location)
+
+	for i := 0; i < maxScanDays; i++ {
+		for _, s := range e.SessionsOn(day) {
+			if open := e.at(day, s.Open); open.After(local) {
+				return open
+			}
+		}
+		day = day.AddDate(0, 0, 1)
+	}
+	return time.Time{}
+}
+
+// PreviousClose returns the end of the last session that closed at or before t.
+func (e *Exchange) PreviousClose(t time.Time) time.Time {
+	local := t.In(e.Location)
+	day := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, e.Location)
+
+	for i := 0; i < maxScanDays; i++ {
+		sessions := e.SessionsOn(day)
+		for j := len(sessions) - 1; j >= 0; j-- {
+			if closeAt := e.at(day, sessions[j].Close); !closeAt.After(local) {
+				return closeAt
+			}
+		}
+		day = day.AddDate(0, 0, -1)
+	}
+	return time.Time{}
+}
+
+// TradingDaysBetween counts trading days from the day of `from` up to, but not including, the day of `to`.
+func (e *Exchange) TradingDaysBetween(from, to time.Time) int {
+	start := from.In(e.Location)
+	end := to.In(e.Location)
+	day := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, e.Location)
+	last := time.Date(end.Year(), end.Month(), end.Day(), 0, 0, 0, 0, e.Location)
+
+	count := 0
+	for day.Before(last) {
+		if e.IsTradingDay(day) {
+			count++
+		}
+		day = day.AddDate(0, 0, 1)
+	}
+	return count
+}
@@ -13,8 +13,19 @@ const (
 	FormatCurrency
 	FormatNumber
 	FormatDate
+	FormatPercent
 )
 
+// Formula is written as a cell formula instead of a value, e.g. Formula("SUM(D3:D9)").
+type Formula string
+
+// ColumnRange returns the A1 range of column col from startRow to endRow, e.g. "D3:D9".
+func ColumnRange(col, startRow, endRow int) string {
+	start, _ := excelize.CoordinatesToCellName(col, startRow)
+	end, _ := excelize.CoordinatesToCellName(col, endRow)
+	return start + ":" + end
+}
+
 type ExcelWriter struct {
 	File       *excelize.File
 	Sheet      string
@@ -44,6 +55,11 @@ func NewWriter(f *excelize.File, sheet string, startRow int) *ExcelWriter {
 		CustomNumFmt: strPtr("yyyy-mm-dd hh:mm"),
 	})
 
+	styles[FormatPercent], _ = f.NewStyle(&excelize.Style{
+		CustomNumFmt: strPtr("0.00%;[Red]-0.00%"),
+		Alignment:    &excelize.Alignment{Horizontal: "right"},
+	})
+
 	if startRow < 1 {
 		startRow = 1
 	}
@@ -88,22 +104,37 @@ func (w *ExcelWriter) AutoFit() {
 }
 
 func (w *ExcelWriter) WriteRow(values []interface{}) error {
+	cells := make([]interface{}, len(values))
+	for i, val := range values {
+		if _, ok := val.(Formula); !ok {
+			cells[i] = val
+		}
+	}
+
 	cell, _ := excelize.CoordinatesToCellName(1, w.CurrentRow)
-	err := w.File.SetSheetRow(w.Sheet, cell, &values)
+	err := w.File.SetSheetRow(w.Sheet, cell, &cells)
 	if err != nil {
 		return err
 	}
 
 	for i, val := range values {
+		targetCell, _ := excelize.CoordinatesToCellName(i+1, w.CurrentRow)
+		if formula, ok := val.(Formula); ok {
+			if err := w.File.SetCellFormula(w.Sheet, targetCell, string(formula)); err != nil {
+				return err
+			}
+		}
 		if fmtType, ok := w.ColMapping[i+1]; ok {
-			targetCell, _ := excelize.CoordinatesToCellName(i+1, w.CurrentRow)
 			w.File.SetCellStyle(w.Sheet, targetCell, targetCell, w.Styles[fmtType])
 		}
 
 		var length float64
-		if _, ok := val.(time.Time); ok {
+		switch val.(type) {
+		case time.Time:
 			length = 18
-		} else {
+		case Formula:
+			length = 14
+		default:
 			strVal := fmt.Sprintf("%v", val)
 			length = float64(utf8.RuneCountInString(strVal))
 		}
@@ -0,0 +1,87 @@
+package export
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"strings"
+)
+
+var (
+	transactionColumns = []string{
+		"ID", "Date", "Provider", "Account No", "Type", "Ticker", "Quantity", "Unit Price",
+		"Amount", "Fee", "Cost Basis", "Realized PnL", "Balance", "Balance Change", "Title", "Notes",
+	}
+	holdingColumns = []string{
+		"Provider", "Account No", "Ticker", "Quantity", "Avg Price", "Cost",
+		"Market Value", "Unrealized PnL", "Price As Of",
+	}
+)
+
+const csvDateFormat = "2006-01-02 15:04:05"
+
+// csvWriter writes one table per record kind. When the kind changes, a blank line and a new header row are written.
+type csvWriter struct {
+	w       *csv.Writer
+	section string
+}
+
+func NewCSVWriter(w io.Writer) Writer {
+	return &csvWriter{w: csv.NewWriter(w)}
+}
+
+func (c *csvWriter) begin(section string, columns []string) error {
+	if c.section == section {
+		return nil
+	}
+	if c.section != "" {
+		if err := c.w.Write(nil); err != nil {
+			return err
+		}
+	}
+	c.section = section
+	return c.w.Write(columns)
+}
+
+func num(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+// SafeCell quotes free text that a spreadsheet would otherwise read as a formula.
+// Numbers are written with num and never pass through here, so negative amounts stay numeric.
+func SafeCell(s string) string {
+	if s != "" && strings.ContainsRune("=+-@\t\r", rune(s[0])) {
+		return "'" + s
+	}
+	return s
+}
+
+func (c *csvWriter) WriteTransaction(t Transaction) error {
+	if err := c.begin("transactions", transactionColumns); err != nil {
+		return err
+	}
+	return c.w.Write([]string{
+		strconv.FormatUint(uint64(t.ID), 10), t.Date.Format(csvDateFormat), SafeCell(t.Provider), SafeCell(t.AccountNo),
+		t.Type, SafeCell(t.Ticker), num(t.Quantity), num(t.UnitPrice), num(t.Amount), num(t.Fee),
+		num(t.CostBasis), num(t.RealizedPnL), t.Balance, num(t.BalanceChange), SafeCell(t.Title), SafeCell(t.Notes),
+	})
+}
+
+func (c *csvWriter) WriteHolding(h Holding) error {
+	if err := c.begin("holdings", holdingColumns); err != nil {
+		return err
+	}
+	asOf := ""
+	if !h.PriceAsOf.IsZero() {
+		asOf = h.PriceAsOf.Format(csvDateFormat)
+	}
+	return c.w.Write([]string{
+		SafeCell(h.Provider), SafeCell(h.AccountNo), SafeCell(h.Ticker), num(h.Quantity), num(h.AvgPrice), num(h.Cost),
+		num(h.MarketValue), num(h.UnrealizedPnL), asOf,
+	})
+}
+
+func (c *csvWriter) Close() error {
+	c.w.Flush()
+	return c.w.Error()
+}
@@ -0,0 +1,80 @@
+package export
+
+import (
+	"bytes"
+	"encoding/csv"
+	"testing"
+	"time"
+)
+
+func TestSafeCell(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"", ""},
+		{"Salary", "Salary"},
+		{"BBCA", "BBCA"},
+		{"top-up via BCA", "top-up via BCA"},
+		{"=HYPERLINK(\"http://evil\")", "'=HYPERLINK(\"http://evil\")"},
+		{"+1+1", "'+1+1"},
+		{"-2+3", "'-2+3"},
+		{"@SUM(A1:A2)", "'@SUM(A1:A2)"},
+		{"\t=1", "'\t=1"},
+		{"\r=1", "'\r=1"},
+	}
+
+	for _, tt := range tests {
+		if got := SafeCell(tt.in); got != tt.want {
+			t.Errorf("SafeCell(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestCSVWriterEscapesFormulas(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewCSVWriter(&buf)
+
+	err := w.WriteTransaction(Transaction{
+		ID:            1,
+		Date:          time.Date(2026, 1, 2, 9, 0, 0, 0, time.UTC),
+		Provider:      "Stockbit",
+		AccountNo:     "123",
+		Type:          "expense",
+		Amount:        50000,
+		BalanceChange: -50000,
+		Balance:       "cash_balance",
+		Title:         "=cmd|' /C calc'!A0",
+		Notes:         "@SUM(1+1)",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 2 {
+		t.Fatalf("rows = %d, want header and one transaction", len(rows))
+	}
+
+	row := map[string]string{}
+	for i, column := range transactionColumns {
+		row[column] = rows[1][i]
+	}
+	want := map[string]string{
+		"Title":          "'=cmd|' /C calc'!A0",
+		"Notes":          "'@SUM(1+1)",
+		"Balance Change": "-50000",
+		"Provider":       "Stockbit",
+	}
+	for column, value := range want {
+		if row[column] != value {
+			t.Errorf("%s = %q, want %q", column, row[column], value)
+		}
+	}
+}
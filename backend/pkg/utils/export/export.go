@@ -0,0 +1,66 @@
+package export
+
+import (
+	"io"
+	"strings"
+	"time"
+)
+
+// Transaction is one ledger entry as exported. Amount is the gross amount stored for the
+// transaction; BalanceChange is the signed change it made to the Balance account.
+type Transaction struct {
+	ID            uint      `json:"id"`
+	Date          time.Time `json:"date"`
+	Provider      string    `json:"provider"`
+	AccountNo     string    `json:"account_no"`
+	Type          string    `json:"type"`
+	Ticker        string    `json:"ticker"`
+	Quantity      float64   `json:"quantity"`
+	UnitPrice     float64   `json:"unit_price"`
+	Amount        float64   `json:"amount"`
+	Fee           float64   `json:"fee"`
+	CostBasis     float64   `json:"cost_basis"`
+	RealizedPnL   float64   `json:"realized_pnl"`
+	Balance       string    `json:"balance"`
+	BalanceChange float64   `json:"balance_change"`
+	Title         string    `json:"title"`
+	Notes         string    `json:"notes"`
+}
+
+type Holding struct {
+	Provider      string    `json:"provider"`
+	AccountNo     string    `json:"account_no"`
+	Ticker        string    `json:"ticker"`
+	Quantity      float64   `json:"quantity"`
+	AvgPrice      float64   `json:"avg_price"`
+	Cost          float64   `json:"cost"`
+	MarketValue   float64   `json:"market_value"`
+	UnrealizedPnL float64   `json:"unrealized_pnl"`
+	PriceAsOf     time.Time `json:"price_as_of"`
+}
+
+// Writer receives transactions and holdings in any order. Close flushes anything the
+// format buffers and must be called once all records are written.
+type Writer interface {
+	WriteTransaction(t Transaction) error
+	WriteHolding(h Holding) error
+	Close() error
+}
+
+type Format struct {
+	Name        string
+	ContentType string
+	Extension   string
+	New         func(w io.Writer) Writer
+}
+
+var formats = map[string]Format{
+	"csv":   {Name: "csv", ContentType: "text/csv", Extension: "csv", New: NewCSVWriter},
+	"jsonl": {Name: "jsonl", ContentType: "application/x-ndjson", Extension: "jsonl", New: NewJSONLinesWriter},
+	"ofx":   {Name: "ofx", ContentType: "application/x-ofx", Extension: "ofx", New: NewOFXWriter},
+}
+
+func Lookup(name string) (Format, bool) {
+	f, ok := formats[strings.ToLower(strings.TrimSpace(name))]
+	return f, ok
+}
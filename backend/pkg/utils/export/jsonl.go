@@ -0,0 +1,33 @@
+package export
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// jsonLinesWriter writes one JSON object per line, tagged with "record": "transaction" or "holding".
+type jsonLinesWriter struct {
+	enc *json.Encoder
+}
+
+func NewJSONLinesWriter(w io.Writer) Writer {
+	return &jsonLinesWriter{enc: json.NewEncoder(w)}
+}
+
+func (j *jsonLinesWriter) WriteTransaction(t Transaction) error {
+	return j.enc.Encode(struct {
+		Record string `json:"record"`
+		Transaction
+	}{"transaction", t})
+}
+
+func (j *jsonLinesWriter) WriteHolding(h Holding) error {
+	return j.enc.Encode(struct {
+		Record string `json:"record"`
+		Holding
+	}{"holding", h})
+}
+
+func (j *jsonLinesWriter) Close() error {
+	return nil
+}
@@ -0,0 +1,200 @@
+package export
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"time"
+)
+
+const ofxDateFormat = "20060102150405"
+
+type ofxAccount struct {
+	Provider     string
+	AccountNo    string
+	Transactions []Transaction
+	Holdings     []Holding
+}
+
+// ofxWriter buffers records and writes an OFX 2.2 investment statement per broker account on Close.
+// Buys, sells and dividends become investment transactions; other cash movements become bank transactions.
+type ofxWriter struct {
+	w        io.Writer
+	accounts map[[2]string]*ofxAccount
+	tickers  map[string]bool
+}
+
+func NewOFXWriter(w io.Writer) Writer {
+	return &ofxWriter{w: w, accounts: map[[2]string]*ofxAccount{}, tickers: map[string]bool{}}
+}
+
+func (o *ofxWriter) account(provider, accountNo string) *ofxAccount {
+	key := [2]string{provider, accountNo}
+	if _, ok := o.accounts[key]; !ok {
+		o.accounts[key] = &ofxAccount{Provider: provider, AccountNo: accountNo}
+	}
+	return o.accounts[key]
+}
+
+func (o *ofxWriter) WriteTransaction(t Transaction) error {
+	acc := o.account(t.Provider, t.AccountNo)
+	acc.Transactions = append(acc.Transactions, t)
+	if t.Type == "buy" || t.Type == "sell" || t.Type == "dividend" {
+		o.tickers[t.Ticker] = true
+	}
+	return nil
+}
+
+func (o *ofxWriter) WriteHolding(h Holding) error {
+	acc := o.account(h.Provider, h.AccountNo)
+	acc.Holdings = append(acc.Holdings, h)
+	o.tickers[h.Ticker] = true
+	return nil
+}
+
+func ofxText(s string) string {
+	var b bytes.Buffer
+	xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
+
+func ofxAmount(v float64) string {
+	return fmt.Sprintf("%.2f", v)
+}
+
+func ofxID(s string) string {
+	if s == "" {
+		return "DEFAULT"
+	}
+	return ofxText(s)
+}
+
+func secID(ticker string) string {
+	return fmt.Sprintf("<SECID><UNIQUEID>%s</UNIQUEID><UNIQUEIDTYPE>TICKER</UNIQUEIDTYPE></SECID>", ofxText(ticker))
+}
+
+func invTran(t Transaction) string {
+	return fmt.Sprintf("<INVTRAN><FITID>%d</FITID><DTTRADE>%s</DTTRADE><MEMO>%s</MEMO></INVTRAN>",
+		t.ID, t.Date.Format(ofxDateFormat), ofxText(t.Notes))
+}
+
+func (o *ofxWriter) writeTransaction(b *bytes.Buffer, t Transaction) {
+	switch t.Type {
+	case "buy":
+		fmt.Fprintf(b, "<BUYSTOCK><INVBUY>%s%s<UNITS>%s</UNITS><UNITPRICE>%s</UNITPRICE><COMMISSION>%s</COMMISSION><TOTAL>%s</TOTAL><SUBACCTSEC>CASH</SUBACCTSEC><SUBACCTFUND>CASH</SUBACCTFUND></INVBUY><BUYTYPE>BUY</BUYTYPE></BUYSTOCK>\n",
+			invTran(t), secID(t.Ticker), num(t.Quantity), num(t.UnitPrice), ofxAmount(t.Fee), ofxAmount(-t.Amount))
+	case "sell":
+		fmt.Fprintf(b, "<SELLSTOCK><INVSELL>%s%s<UNITS>%s</UNITS><UNITPRICE>%s</UNITPRICE><COMMISSION>%s</COMMISSION><TOTAL>%s</TOTAL><SUBACCTSEC>CASH</SUBACCTSEC><SUBACCTFUND>CASH</SUBACCTFUND></INVSELL><SELLTYPE>SELL</SELLTYPE></SELLSTOCK>\n",
+			invTran(t), secID(t.Ticker), num(-t.Quantity), num(t.UnitPrice), ofxAmount(t.Fee), ofxAmount(t.Amount-t.Fee))
+	case "dividend":
+		fmt.Fprintf(b, "<INCOME>%s%s<INCOMETYPE>DIV</INCOMETYPE><TOTAL>%s</TOTAL><SUBACCTSEC>CASH</SUBACCTSEC><SUBACCTFUND>CASH</SUBACCTFUND><WITHHOLDING>%s</WITHHOLDING></INCOME>\n",
+			invTran(t), secID(t.Ticker), ofxAmount(t.Amount), ofxAmount(t.Fee))
+	default:
+		if t.BalanceChange == 0 {
+			return
+		}
+		trnType := "CREDIT"
+		if t.BalanceChange < 0 {
+			trnType = "DEBIT"
+		}
+		name := t.Title
+		if name == "" {
+			name = t.Ticker
+		}
+		fmt.Fprintf(b, "<INVBANKTRAN><STMTTRN><TRNTYPE>%s</TRNTYPE><DTPOSTED>%s</DTPOSTED><TRNAMT>%s</TRNAMT><FITID>%d</FITID><NAME>%s</NAME><MEMO>%s</MEMO></STMTTRN><SUBACCTFUND>CASH</SUBACCTFUND></INVBANKTRAN>\n",
+			trnType, t.Date.Format(ofxDateFormat), ofxAmount(t.BalanceChange), t.ID, ofxText(truncate(name, 32)), ofxText(t.Notes))
+	}
+}
+
+// truncate keeps OFX NAME within its 32 character limit.
+func truncate(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return string(r[:n])
+}
+
+func (o *ofxWriter) Close() error {
+	now := time.Now()
+	var b bytes.Buffer
+
+	b.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="no"?>` + "\n")
+	b.WriteString(`<?OFX OFXHEADER="200" VERSION="220" SECURITY="NONE" OLDFILEUID="NONE" NEWFILEUID="NONE"?>` + "\n")
+	b.WriteString("<OFX>\n")
+	fmt.Fprintf(&b, "<SIGNONMSGSRSV1><SONRS><STATUS><CODE>0</CODE><SEVERITY>INFO</SEVERITY></STATUS><DTSERVER>%s</DTSERVER><LANGUAGE>ENG</LANGUAGE></SONRS></SIGNONMSGSRSV1>\n", now.Format(ofxDateFormat))
+	b.WriteString("<INVSTMTMSGSRSV1>\n")
+
+	keys := make([][2]string, 0, len(o.accounts))
+	for k := range o.accounts {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i][0] != keys[j][0] {
+			return keys[i][0] < keys[j][0]
+		}
+		return keys[i][1] < keys[j][1]
+	})
+
+	for n, key := range keys {
+		acc := o.accounts[key]
+		sort.SliceStable(acc.Transactions, func(i, j int) bool { return acc.Transactions[i].Date.Before(acc.Transactions[j].Date) })
+
+		start, end := now, now
+		if len(acc.Transactions) > 0 {
+			start = acc.Transactions[0].Date
+			end = acc.Transactions[len(acc.Transactions)-1].Date
+		}
+
+		fmt.Fprintf(&b, "<INVSTMTTRNRS><TRNUID>%d</TRNUID><STATUS><CODE>0</CODE><SEVERITY>INFO</SEVERITY></STATUS>\n", n+1)
+		fmt.Fprintf(&b, "<INVSTMTRS><DTASOF>%s</DTASOF><CURDEF>IDR</CURDEF><INVACCTFROM><BROKERID>%s</BROKERID><ACCTID>%s</ACCTID></INVACCTFROM>\n",
+			now.Format(ofxDateFormat), ofxID(acc.Provider), ofxID(acc.AccountNo))
+
+		fmt.Fprintf(&b, "<INVTRANLIST><DTSTART>%s</DTSTART><DTEND>%s</DTEND>\n", start.Format(ofxDateFormat), end.Format(ofxDateFormat))
+		for _, t := range acc.Transactions {
+			o.writeTransaction(&b, t)
+		}
+		b.WriteString("</INVTRANLIST>\n")
+
+		if len(acc.Holdings) > 0 {
+			b.WriteString("<INVPOSLIST>\n")
+			for _, h := range acc.Holdings {
+				unitPrice := h.AvgPrice
+				if h.Quantity > 0 && h.MarketValue > 0 {
+					unitPrice = h.MarketValue / h.Quantity
+				}
+				asOf := h.PriceAsOf
+				if asOf.IsZero() {
+					asOf = now
+				}
+				fmt.Fprintf(&b, "<POSSTOCK><INVPOS>%s<HELDINACCT>CASH</HELDINACCT><POSTYPE>LONG</POSTYPE><UNITS>%s</UNITS><UNITPRICE>%s</UNITPRICE><MKTVAL>%s</MKTVAL><DTPRICEASOF>%s</DTPRICEASOF></INVPOS></POSSTOCK>\n",
+					secID(h.Ticker), num(h.Quantity), num(math.Round(unitPrice*100)/100), ofxAmount(h.MarketValue), asOf.Format(ofxDateFormat))
+			}
+			b.WriteString("</INVPOSLIST>\n")
+		}
+
+		b.WriteString("</INVSTMTRS></INVSTMTTRNRS>\n")
+	}
+	b.WriteString("</INVSTMTMSGSRSV1>\n")
+
+	if len(o.tickers) > 0 {
+		tickers := make([]string, 0, len(o.tickers))
+		for t := range o.tickers {
+			tickers = append(tickers, t)
+		}
+		sort.Strings(tickers)
+
+		b.WriteString("<SECLISTMSGSRSV1><SECLIST>\n")
+		for _, t := range tickers {
+			fmt.Fprintf(&b, "<STOCKINFO><SECINFO>%s<SECNAME>%s</SECNAME><TICKER>%s</TICKER></SECINFO></STOCKINFO>\n", secID(t), ofxText(t), ofxText(t))
+		}
+		b.WriteString("</SECLIST></SECLISTMSGSRSV1>\n")
+	}
+	b.WriteString("</OFX>\n")
+
+	_, err := o.w.Write(b.Bytes())
+	return err
+}
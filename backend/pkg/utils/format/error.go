@@ -45,14 +45,63 @@ func ErrorResponse(c fiber.Ctx, err error) error {
 		errors.Is(err, domain.ErrInsufficientBalance),
 		errors.Is(err, domain.ErrMismatchInfo),
 		errors.Is(err, domain.ErrInvalidAction),
-		errors.Is(err, domain.ErrAlreadyExist):
+		errors.Is(err, domain.ErrAlreadyExist),
+		errors.Is(err, domain.ErrEmailAlreadyVerify),
+		errors.Is(err, domain.ErrTwoFactorAlreadyEnabled),
+		errors.Is(err, domain.ErrTwoFactorNotEnabled),
+		errors.Is(err, domain.ErrTwoFactorNotSetup),
+		errors.Is(err, domain.ErrWrongPassword),
+		errors.Is(err, domain.ErrDeleteNotConfirmed),
+		errors.Is(err, domain.ErrPasswordUnchanged),
+		errors.Is(err, domain.ErrTooManyAPIKeys),
+		errors.Is(err, domain.ErrCannotEditSelf),
+		errors.Is(err, domain.ErrShareSelf),
+		errors.Is(err, domain.ErrShareAccountAbsent),
+		errors.Is(err, domain.ErrAlreadyShared),
+		errors.Is(err, domain.ErrInvalidPeriod),
+		errors.Is(err, domain.ErrInvalidDateRange),
+		errors.Is(err, domain.ErrInvalidAccountList),
+		errors.Is(err, domain.ErrUnknownSheet),
+		errors.Is(err, domain.ErrInvalidInterval),
+		errors.Is(err, domain.ErrInvalidTaxYear),
+		errors.Is(err, domain.ErrTooManySchedules),
+		errors.Is(err, domain.ErrJournalWithoutTrade),
+		errors.Is(err, domain.ErrJournalTradeAbsent),
+		errors.Is(err, domain.ErrInvalidJournalGroup),
+		errors.Is(err, domain.ErrTooManyImages),
+		errors.Is(err, domain.ErrInvalidNoteSort):
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"message": err.Error()})
 
-	case errors.Is(err, domain.ErrWrongCredential):
+	case errors.Is(err, domain.ErrWrongCredential),
+		errors.Is(err, domain.ErrInvalidToken),
+		errors.Is(err, domain.ErrSessionRevoked),
+		errors.Is(err, domain.ErrInvalidTwoFactorCode):
 		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"message": err.Error()})
 
+	case errors.Is(err, domain.ErrEmailNotVerified),
+		errors.Is(err, domain.ErrAccountLocked),
+		errors.Is(err, domain.ErrForbidden):
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"message": err.Error()})
+
+	case errors.Is(err, domain.ErrImageTooLarge):
+		return c.Status(fiber.StatusRequestEntityTooLarge).JSON(fiber.Map{"message": err.Error()})
+
+	case errors.Is(err, domain.ErrUnsupportedImage):
+		return c.Status(fiber.StatusUnsupportedMediaType).JSON(fiber.Map{"message": err.Error()})
+
+	case errors.Is(err, domain.ErrIdempotencyInProgress),
+		errors.Is(err, domain.ErrJobRunning):
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{"message": err.Error()})
+
+	case errors.Is(err, domain.ErrIdempotencyMismatch):
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{"message": err.Error()})
+
+	case errors.Is(err, domain.ErrTooManyAttempts):
+		return c.Status(fiber.StatusTooManyRequests).JSON(fiber.Map{"message": err.Error()})
+
 	case errors.Is(err, domain.ErrItemNotFound),
-		errors.Is(err, domain.ErrUserNotFound):
+		errors.Is(err, domain.ErrUserNotFound),
+		errors.Is(err, domain.ErrImageNotFound):
 		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"message": err.Error()})
 
 	default:
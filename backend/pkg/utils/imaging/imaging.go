@@ -0,0 +1,150 @@
+package imaging
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"net/http"
+)
+
+var (
+	ErrUnsupportedFormat = errors.New("unsupported image format")
+	ErrTooManyPixels     = errors.New("image dimensions are too large")
+)
+
+// maxPixels guards against decompression bombs: a small file that decodes to a huge bitmap.
+// The decoded image is held in memory while its thumbnail is made, so keep this well within
+// what one request may allocate.
+const maxPixels = 20_000_000
+
+var decoders = map[string]func([]byte) (image.Image, error){
+	"image/jpeg": func(b []byte) (image.Image, error) { return jpeg.Decode(bytes.NewReader(b)) },
+	"image/png":  func(b []byte) (image.Image, error) { return png.Decode(bytes.NewReader(b)) },
+	"image/gif":  func(b []byte) (image.Image, error) { return gif.Decode(bytes.NewReader(b)) },
+}
+
+type Info struct {
+	ContentType string
+	Extension   string
+	Width       int
+	Height      int
+}
+
+// Inspect sniffs the content type from the data itself rather than trusting the upload's header.
+func Inspect(data []byte) (Info, error) {
+	contentType := http.DetectContentType(data)
+	if _, ok := decoders[contentType]; !ok {
+		return Info{}, ErrUnsupportedFormat
+	}
+
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return Info{}, ErrUnsupportedFormat
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 {
+		return Info{}, ErrUnsupportedFormat
+	}
+	if cfg.Width*cfg.Height > maxPixels {
+		return Info{}, ErrTooManyPixels
+	}
+
+	ext := map[string]string{"image/jpeg": "jpg", "image/png": "png", "image/gif": "gif"}[contentType]
+	return Info{ContentType: contentType, Extension: ext, Width: cfg.Width, Height: cfg.Height}, nil
+}
+
+// Thumbnail scales the image to fit within size x size and encodes it as JPEG.
+// Transparent areas are filled with white; images already small enough are not enlarged.
+func Thumbnail(data []byte, size int) ([]byte, error) {
+	info, err := Inspect(data)
+	if err != nil {
+		return nil, err
+	}
+	src, err := decoders[info.ContentType](data)
+	if err != nil {
+		return nil, ErrUnsupportedFormat
+	}
+
+	w, h := info.Width, info.Height
+	if w > size || h > size {
+		if w >= h {
+			w, h = size, max(1, h*size/w)
+		} else {
+			w, h = max(1, w*size/h), size
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, downscale(src, w, h), &jpeg.Options{Quality: 80}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// downscale averages every source pixel that falls into each destination pixel (box filter),
+// compositing each one over white. It reads src directly, so the only new bitmap is w x h.
+func downscale(src image.Image, w, h int) *image.RGBA {
+	bounds := src.Bounds()
+	sw, sh := bounds.Dx(), bounds.Dy()
+	at := rgbaAt(src)
+
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		y0, y1 := y*sh/h, max((y+1)*sh/h, y*sh/h+1)
+		for x := 0; x < w; x++ {
+			x0, x1 := x*sw/w, max((x+1)*sw/w, x*sw/w+1)
+
+			var r, g, b, n uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					// premultiplied, so over white each channel gains what alpha leaves uncovered
+					pr, pg, pb, pa := at(bounds.Min.X+sx, bounds.Min.Y+sy)
+					r += uint64(pr + 0xffff - pa)
+					g += uint64(pg + 0xffff - pa)
+					b += uint64(pb + 0xffff - pa)
+					n++
+				}
+			}
+
+			i := y*dst.Stride + x*4
+			dst.Pix[i] = uint8(r / n >> 8)
+			dst.Pix[i+1] = uint8(g / n >> 8)
+			dst.Pix[i+2] = uint8(b / n >> 8)
+			dst.Pix[i+3] = 255
+		}
+	}
+	return dst
+}
+
+// rgbaAt reads premultiplied 16-bit RGBA from src. The image types the decoders return are read
+// through their typed accessors, so a large image is not boxed into color.Color pixel by pixel.
+func rgbaAt(src image.Image) func(x, y int) (r, g, b, a uint32) {
+	switch img := src.(type) {
+	case *image.YCbCr:
+		return func(x, y int) (uint32, uint32, uint32, uint32) { return img.YCbCrAt(x, y).RGBA() }
+	case *image.RGBA:
+		return func(x, y int) (uint32, uint32, uint32, uint32) { return img.RGBAAt(x, y).RGBA() }
+	case *image.NRGBA:
+		return func(x, y int) (uint32, uint32, uint32, uint32) { return img.NRGBAAt(x, y).RGBA() }
+	case *image.Gray:
+		return func(x, y int) (uint32, uint32, uint32, uint32) { return img.GrayAt(x, y).RGBA() }
+	case *image.Paletted:
+		palette := make([][4]uint32, len(img.Palette))
+		for i, c := range img.Palette {
+			r, g, b, a := c.RGBA()
+			palette[i] = [4]uint32{r, g, b, a}
+		}
+		return func(x, y int) (uint32, uint32, uint32, uint32) {
+			index := int(img.ColorIndexAt(x, y))
+			if index >= len(palette) {
+				return 0, 0, 0, 0
+			}
+			c := palette[index]
+			return c[0], c[1], c[2], c[3]
+		}
+	default:
+		return func(x, y int) (uint32, uint32, uint32, uint32) { return src.At(x, y).RGBA() }
+	}
+}
@@ -0,0 +1,94 @@
+package imaging
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+)
+
+func encodePNG(t *testing.T, img image.Image) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestThumbnail(t *testing.T) {
+	// left half opaque red, right half fully transparent
+	wide := image.NewNRGBA(image.Rect(0, 0, 800, 400))
+	for y := 0; y < 400; y++ {
+		for x := 0; x < 400; x++ {
+			wide.SetNRGBA(x, y, color.NRGBA{R: 255, A: 255})
+		}
+	}
+	gray := image.NewGray(image.Rect(0, 0, 100, 1000))
+
+	tests := []struct {
+		name          string
+		img           image.Image
+		wantW, wantH  int
+		checkX        int
+		wantR, wantGB uint8
+	}{
+		{"wide transparent is filled white", wide, 320, 160, 300, 255, 255},
+		{"wide opaque stays red", wide, 320, 160, 20, 255, 0},
+		{"tall is scaled by height", gray, 32, 320, 0, 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			thumb, err := Thumbnail(encodePNG(t, tt.img), 320)
+			if err != nil {
+				t.Fatal(err)
+			}
+			out, err := jpeg.Decode(bytes.NewReader(thumb))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if b := out.Bounds(); b.Dx() != tt.wantW || b.Dy() != tt.wantH {
+				t.Fatalf("size = %dx%d, want %dx%d", b.Dx(), b.Dy(), tt.wantW, tt.wantH)
+			}
+
+			r, g, b, _ := out.At(tt.checkX, tt.wantH/2).RGBA()
+			if !near(r>>8, tt.wantR) || !near(g>>8, tt.wantGB) || !near(b>>8, tt.wantGB) {
+				t.Errorf("pixel = %d,%d,%d, want %d,%d,%d", r>>8, g>>8, b>>8, tt.wantR, tt.wantGB, tt.wantGB)
+			}
+		})
+	}
+}
+
+// near allows for JPEG compression noise.
+func near(got uint32, want uint8) bool {
+	d := int(got) - int(want)
+	return d > -12 && d < 12
+}
+
+func TestThumbnailKeepsSmallImages(t *testing.T) {
+	thumb, err := Thumbnail(encodePNG(t, image.NewGray(image.Rect(0, 0, 40, 30))), 320)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := jpeg.DecodeConfig(bytes.NewReader(thumb))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Width != 40 || cfg.Height != 30 {
+		t.Errorf("size = %dx%d, want 40x30", cfg.Width, cfg.Height)
+	}
+}
+
+func TestInspectRejectsLargeImages(t *testing.T) {
+	// PNG compresses the empty bitmap to a few KB, like a decompression bomb
+	data := encodePNG(t, image.NewGray(image.Rect(0, 0, 5000, 5000)))
+	if _, err := Inspect(data); err != ErrTooManyPixels {
+		t.Errorf("Inspect error = %v, want ErrTooManyPixels", err)
+	}
+	if _, err := Inspect([]byte("not an image")); err != ErrUnsupportedFormat {
+		t.Errorf("Inspect error = %v, want ErrUnsupportedFormat", err)
+	}
+}
@@ -0,0 +1,179 @@
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// A4 portrait in points.
+const (
+	PageWidth  = 595.28
+	PageHeight = 841.89
+)
+
+type Color struct {
+	R, G, B float64
+}
+
+var (
+	Black     = Color{0, 0, 0}
+	White     = Color{1, 1, 1}
+	Navy      = Color{0, 0.125, 0.376} // same as the Excel header fill (#002060)
+	LightGray = Color{0.93, 0.93, 0.93}
+	Gray      = Color{0.55, 0.55, 0.55}
+	Green     = Color{0.1, 0.5, 0.2}
+	Red       = Color{0.75, 0.1, 0.1}
+)
+
+type Point struct {
+	X, Y float64
+}
+
+// Document is a minimal PDF 1.4 writer using the built-in Helvetica fonts,
+// so no font files have to be embedded.
+type Document struct {
+	pages []*Page
+	title string
+}
+
+// Page collects drawing operators. Coordinates are in points from the top-left corner.
+type Page struct {
+	content bytes.Buffer
+}
+
+func New(title string) *Document {
+	return &Document{title: title}
+}
+
+func (d *Document) AddPage() *Page {
+	p := &Page{}
+	d.pages = append(d.pages, p)
+	return p
+}
+
+func (d *Document) PageCount() int {
+	return len(d.pages)
+}
+
+func (p *Page) op(format string, args ...interface{}) {
+	fmt.Fprintf(&p.content, format+"\n", args...)
+}
+
+func (p *Page) Text(x, y, size float64, bold bool, color Color, s string) {
+	font := "F1"
+	if bold {
+		font = "F2"
+	}
+	p.op("BT %.3f %.3f %.3f rg /%s %.2f Tf %.2f %.2f Td (%s) Tj ET",
+		color.R, color.G, color.B, font, size, x, PageHeight-y, escape(s))
+}
+
+func (p *Page) TextRight(right, y, size float64, bold bool, color Color, s string) {
+	p.Text(right-TextWidth(s, size, bold), y, size, bold, color, s)
+}
+
+func (p *Page) TextCenter(center, y, size float64, bold bool, color Color, s string) {
+	p.Text(center-TextWidth(s, size, bold)/2, y, size, bold, color, s)
+}
+
+func (p *Page) Line(x1, y1, x2, y2, width float64, color Color) {
+	p.op("%.3f %.3f %.3f RG %.2f w %.2f %.2f m %.2f %.2f l S",
+		color.R, color.G, color.B, width, x1, PageHeight-y1, x2, PageHeight-y2)
+}
+
+// FillRect fills a rectangle whose top-left corner is (x, y).
+func (p *Page) FillRect(x, y, w, h float64, color Color) {
+	p.op("%.3f %.3f %.3f rg %.2f %.2f %.2f %.2f re f",
+		color.R, color.G, color.B, x, PageHeight-y-h, w, h)
+}
+
+func (p *Page) Polyline(points []Point, width float64, color Color) {
+	if len(points) < 2 {
+		return
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "%.3f %.3f %.3f RG %.2f w 1 j ", color.R, color.G, color.B, width)
+	for i, pt := range points {
+		cmd := "l"
+		if i == 0 {
+			cmd = "m"
+		}
+		fmt.Fprintf(&b, "%.2f %.2f %s ", pt.X, PageHeight-pt.Y, cmd)
+	}
+	b.WriteString("S")
+	p.op("%s", b.String())
+}
+
+// escape encodes s as WinAnsi and escapes PDF string delimiters.
+// Characters outside Latin-1 are replaced with '?'.
+func escape(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteByte(byte(r))
+		case r >= 32 && r < 127, r >= 160 && r <= 255:
+			b.WriteByte(byte(r))
+		default:
+			b.WriteByte('?')
+		}
+	}
+	return b.String()
+}
+
+func (d *Document) Write(w io.Writer) error {
+	if len(d.pages) == 0 {
+		d.AddPage()
+	}
+
+	var out bytes.Buffer
+	offsets := []int{}
+	object := func(body string) {
+		offsets = append(offsets, out.Len())
+		fmt.Fprintf(&out, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	out.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+
+	// Fixed objects: 1 catalog, 2 page tree, 3-4 fonts, 5 info. Pages start at 6,
+	// each followed by its content stream.
+	kids := make([]string, len(d.pages))
+	for i := range d.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 6+i*2)
+	}
+
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(d.pages)))
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+	object(fmt.Sprintf("<< /Title (%s) /Producer (Trade Tracker) >>", escape(d.title)))
+
+	for i, p := range d.pages {
+		object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.2f %.2f] /Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>",
+			PageWidth, PageHeight, 7+i*2))
+
+		var compressed bytes.Buffer
+		zw := zlib.NewWriter(&compressed)
+		if _, err := zw.Write(p.content.Bytes()); err != nil {
+			return err
+		}
+		if err := zw.Close(); err != nil {
+			return err
+		}
+		object(fmt.Sprintf("<< /Length %d /Filter /FlateDecode >>\nstream\n%s\nendstream", compressed.Len(), compressed.String()))
+	}
+
+	xref := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&out, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&out, "trailer\n<< /Size %d /Root 1 0 R /Info 5 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+
+	_, err := w.Write(out.Bytes())
+	return err
+}
@@ -0,0 +1,288 @@
+package pdf
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+const (
+	AlignLeft = iota
+	AlignRight
+)
+
+const (
+	margin     = 40.0
+	footerSize = 30.0
+	bodySize   = 9.0
+	rowHeight  = 16.0
+)
+
+type Column struct {
+	Title string
+	// Weight is the share of the content width the column takes, relative to the other columns.
+	Weight float64
+	Align  int
+}
+
+// PDFWriter lays content out top to bottom and starts a new page when the current one is full.
+type PDFWriter struct {
+	Doc    *Document
+	Page   *Page
+	Y      float64
+	Footer string
+}
+
+func NewWriter(doc *Document) *PDFWriter {
+	w := &PDFWriter{Doc: doc}
+	w.newPage()
+	return w
+}
+
+func (w *PDFWriter) newPage() {
+	w.Page = w.Doc.AddPage()
+	w.Y = margin
+}
+
+func contentWidth() float64 {
+	return PageWidth - 2*margin
+}
+
+// ensure starts a new page unless h more points fit on the current one.
+func (w *PDFWriter) ensure(h float64) bool {
+	if w.Y+h <= PageHeight-margin-footerSize {
+		return false
+	}
+	w.newPage()
+	return true
+}
+
+func (w *PDFWriter) Skip(h float64) {
+	w.Y += h
+}
+
+func (w *PDFWriter) Title(title, subtitle string) {
+	w.ensure(50)
+	w.Page.FillRect(margin, w.Y, contentWidth(), 4, Navy)
+	w.Y += 26
+	w.Page.Text(margin, w.Y, 18, true, Navy, title)
+	if subtitle != "" {
+		w.Y += 16
+		w.Page.Text(margin, w.Y, 10, false, Gray, subtitle)
+	}
+	w.Y += 20
+}
+
+func (w *PDFWriter) Heading(text string) {
+	w.ensure(40)
+	w.Y += 10
+	w.Page.Text(margin, w.Y, 12, true, Navy, text)
+	w.Y += 6
+	w.Page.Line(margin, w.Y, PageWidth-margin, w.Y, 0.8, Navy)
+	w.Y += 10
+}
+
+// Text writes a paragraph, wrapping words at the content width.
+func (w *PDFWriter) Text(text string, color Color) {
+	for _, line := range wrap(text, contentWidth(), bodySize) {
+		w.ensure(rowHeight)
+		w.Y += 12
+		w.Page.Text(margin, w.Y, bodySize, false, color, line)
+	}
+	w.Y += 4
+}
+
+func wrap(text string, width, size float64) []string {
+	var lines []string
+	current := ""
+	for _, word := range strings.Fields(text) {
+		candidate := word
+		if current != "" {
+			candidate = current + " " + word
+		}
+		if current != "" && TextWidth(candidate, size, false) > width {
+			lines = append(lines, current)
+			candidate = word
+		}
+		current = candidate
+	}
+	if current != "" {
+		lines = append(lines, current)
+	}
+	return lines
+}
+
+// KeyValues writes label/value pairs in two columns of a shaded box.
+func (w *PDFWriter) KeyValues(pairs [][2]string) {
+	rows := (len(pairs) + 1) / 2
+	height := float64(rows)*rowHeight + 8
+	w.ensure(height)
+
+	w.Page.FillRect(margin, w.Y, contentWidth(), height, LightGray)
+	half := contentWidth() / 2
+	for i, pair := range pairs {
+		x := margin + float64(i%2)*half
+		y := w.Y + 4 + float64(i/2)*rowHeight + 11
+		w.Page.Text(x+8, y, bodySize, false, Gray, pair[0])
+		w.Page.TextRight(x+half-8, y, bodySize, true, valueColor(pair[1]), pair[1])
+	}
+	w.Y += height + 6
+}
+
+// valueColor marks amounts formatted as negatives, e.g. "(Rp 1.000)" or "-2,5%", red.
+func valueColor(s string) Color {
+	if strings.HasPrefix(s, "(") || strings.HasPrefix(s, "-") {
+		return Red
+	}
+	return Black
+}
+
+// Table writes rows under a header that repeats on every page. footer, when given, is a bold totals row.
+func (w *PDFWriter) Table(cols []Column, rows [][]string, footer []string) {
+	var totalWeight float64
+	for _, c := range cols {
+		totalWeight += c.Weight
+	}
+	widths := make([]float64, len(cols))
+	for i, c := range cols {
+		widths[i] = contentWidth() * c.Weight / totalWeight
+	}
+
+	header := func() {
+		w.Page.FillRect(margin, w.Y, contentWidth(), rowHeight+2, Navy)
+		x := margin
+		for i, c := range cols {
+			w.cell(x, widths[i], c.Title, c.Align, true, White)
+			x += widths[i]
+		}
+		w.Y += rowHeight + 2
+	}
+
+	w.ensure(3 * rowHeight)
+	header()
+
+	if len(rows) == 0 {
+		w.Page.Text(margin+4, w.Y+11, bodySize, false, Gray, "No data for this period.")
+		w.Y += rowHeight
+	}
+
+	for n, row := range rows {
+		if w.ensure(rowHeight) {
+			header()
+		}
+		if n%2 == 1 {
+			w.Page.FillRect(margin, w.Y, contentWidth(), rowHeight, LightGray)
+		}
+		x := margin
+		for i, c := range cols {
+			value := ""
+			if i < len(row) {
+				value = row[i]
+			}
+			color := Black
+			if c.Align == AlignRight {
+				color = valueColor(value)
+			}
+			w.cell(x, widths[i], value, c.Align, false, color)
+			x += widths[i]
+		}
+		w.Y += rowHeight
+	}
+
+	if footer != nil {
+		w.ensure(rowHeight)
+		w.Page.Line(margin, w.Y, PageWidth-margin, w.Y, 0.8, Navy)
+		x := margin
+		for i, c := range cols {
+			if i < len(footer) {
+				w.cell(x, widths[i], footer[i], c.Align, true, valueColor(footer[i]))
+			}
+			x += widths[i]
+		}
+		w.Y += rowHeight
+	}
+	w.Y += 8
+}
+
+func (w *PDFWriter) cell(x, width float64, value string, align int, bold bool, color Color) {
+	const pad = 4.0
+	value = Truncate(value, width-2*pad, bodySize, bold)
+	y := w.Y + 11
+	if align == AlignRight {
+		w.Page.TextRight(x+width-pad, y, bodySize, bold, color, value)
+		return
+	}
+	w.Page.Text(x+pad, y, bodySize, bold, color, value)
+}
+
+// LineChart draws values as a line with four horizontal grid lines.
+// labels has one entry per value; only the first, middle and last are printed.
+func (w *PDFWriter) LineChart(labels []string, values []float64, height float64, formatY func(float64) string) {
+	w.ensure(height + 30)
+	if len(values) == 0 {
+		w.Text("No data for this period.", Gray)
+		return
+	}
+
+	lo, hi := values[0], values[0]
+	for _, v := range values {
+		lo = math.Min(lo, v)
+		hi = math.Max(hi, v)
+	}
+	if hi == lo {
+		pad := math.Max(math.Abs(hi)*0.05, 1)
+		lo, hi = lo-pad, hi+pad
+	}
+
+	const axisWidth = 80.0
+	left := margin + axisWidth
+	top := w.Y + 6
+	width := contentWidth() - axisWidth
+	bottom := top + height
+
+	for i := 0; i <= 4; i++ {
+		v := lo + (hi-lo)*float64(i)/4
+		y := bottom - height*float64(i)/4
+		w.Page.Line(left, y, left+width, y, 0.3, LightGray)
+		w.Page.TextRight(left-6, y+3, 7, false, Gray, formatY(v))
+	}
+	w.Page.Line(left, bottom, left+width, bottom, 0.8, Gray)
+
+	points := make([]Point, len(values))
+	for i, v := range values {
+		x := left
+		if len(values) > 1 {
+			x += width * float64(i) / float64(len(values)-1)
+		}
+		points[i] = Point{X: x, Y: bottom - height*(v-lo)/(hi-lo)}
+	}
+	if len(points) == 1 {
+		points = append(points, Point{X: left + width, Y: points[0].Y})
+	}
+	w.Page.Polyline(points, 1.5, Navy)
+
+	if len(labels) > 0 {
+		w.Page.Text(left, bottom+12, 7, false, Gray, labels[0])
+		if len(labels) > 2 {
+			w.Page.TextCenter(left+width/2, bottom+12, 7, false, Gray, labels[len(labels)/2])
+		}
+		if len(labels) > 1 {
+			w.Page.TextRight(left+width, bottom+12, 7, false, Gray, labels[len(labels)-1])
+		}
+	}
+
+	w.Y = bottom + 24
+}
+
+// Finish prints the footer and page numbers on every page. Call it once, before Doc.Write.
+func (w *PDFWriter) Finish() {
+	total := w.Doc.PageCount()
+	for i, p := range w.Doc.pages {
+		y := PageHeight - margin + 10
+		p.Line(margin, y-12, PageWidth-margin, y-12, 0.5, LightGray)
+		if w.Footer != "" {
+			p.Text(margin, y, 7, false, Gray, w.Footer)
+		}
+		p.TextRight(PageWidth-margin, y, 7, false, Gray, fmt.Sprintf("Page %d of %d", i+1, total))
+	}
+}
@@ -0,0 +1,108 @@
+package totp
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+// rfcSecret is the SHA-1 key from RFC 6238 appendix B ("12345678901234567890") in base32.
+const rfcSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestValidateRFCVectors(t *testing.T) {
+	// The RFC lists 8-digit codes; 6-digit codes are their last six digits.
+	tests := []struct {
+		unix int64
+		code string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+		{20000000000, "353130"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.code, func(t *testing.T) {
+			at := time.Unix(tt.unix, 0)
+			step, ok := Validate(tt.code, rfcSecret, at)
+			if !ok {
+				t.Fatalf("Validate(%s) at %d rejected a valid code", tt.code, tt.unix)
+			}
+			if step != Step(at) {
+				t.Errorf("step = %d, want %d", step, Step(at))
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	at := time.Unix(1111111111, 0)
+
+	tests := []struct {
+		name     string
+		code     string
+		secret   string
+		at       time.Time
+		want     bool
+		wantStep int64
+	}{
+		{"current step", "050471", rfcSecret, at, true, Step(at)},
+		{"with spaces", " 050 471 ", rfcSecret, at, true, Step(at)},
+		{"lowercase secret", "050471", strings.ToLower(rfcSecret), at, true, Step(at)},
+		{"previous step inside skew", "050471", rfcSecret, at.Add(Period * time.Second), true, Step(at)},
+		{"next step inside skew", "050471", rfcSecret, at.Add(-Period * time.Second), true, Step(at)},
+		{"outside skew", "050471", rfcSecret, at.Add(2 * Period * time.Second), false, 0},
+		{"wrong code", "123456", rfcSecret, at, false, 0},
+		{"too short", "05047", rfcSecret, at, false, 0},
+		{"too long", "0504711", rfcSecret, at, false, 0},
+		{"invalid secret", "050471", "not base32!", at, false, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			step, ok := Validate(tt.code, tt.secret, tt.at)
+			if ok != tt.want || step != tt.wantStep {
+				t.Errorf("Validate = (%d, %v), want (%d, %v)", step, ok, tt.wantStep, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerateSecret(t *testing.T) {
+	secret, err := GenerateSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(secret) != 32 {
+		t.Errorf("secret length = %d, want 32 base32 characters for 20 bytes", len(secret))
+	}
+
+	other, _ := GenerateSecret()
+	if secret == other {
+		t.Error("GenerateSecret returned the same secret twice")
+	}
+}
+
+func TestProvisioningURI(t *testing.T) {
+	uri, err := url.Parse(ProvisioningURI(rfcSecret, "jane@example.com", "Trade Tracker"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if uri.Scheme != "otpauth" || uri.Host != "totp" {
+		t.Errorf("uri = %s, want otpauth://totp/...", uri)
+	}
+	if uri.Path != "/Trade Tracker:jane@example.com" {
+		t.Errorf("label = %q", uri.Path)
+	}
+
+	query := uri.Query()
+	for key, want := range map[string]string{"secret": rfcSecret, "issuer": "Trade Tracker", "digits": "6", "period": "30", "algorithm": "SHA1"} {
+		if got := query.Get(key); got != want {
+			t.Errorf("%s = %q, want %q", key, got, want)
+		}
+	}
+}
@@ -1,6 +1,6 @@
 "use client";
 
-import { useState, useMemo } from "react";
+import { useState, useEffect } from "react";
 import { useGetNotes } from "@/hooks";
 import { NoteCard, NoteEmpty, NoteSheet } from "@/components/journal";
 
@@ -10,19 +10,17 @@ import { Button } from "@/components/ui/button";
 
 export default function JournalsClient() {
     const [open, setOpen] = useState(false);
-    const { notes, refreshNote, loading } = useGetNotes();
-
     const [currentPage, setCurrentPage] = useState(1);
     const itemsPerPage = 20;
 
-    const paginatedNotes = useMemo(() => {
-        if (!notes)
-            return [];
-        const startIndex = (currentPage - 1) * itemsPerPage;
-        return notes.slice(startIndex, startIndex + itemsPerPage);
-    }, [notes, currentPage]);
+    const { notes, total, refreshNote, loading } = useGetNotes(currentPage, itemsPerPage);
+    const totalPages = Math.ceil(total / itemsPerPage);
 
-    const totalPages = Math.ceil((notes?.length || 0) / itemsPerPage);
+    // removing the last note on a page leaves it empty: step back to the new last page
+    useEffect(() => {
+        if (totalPages > 0 && currentPage > totalPages)
+            setCurrentPage(totalPages);
+    }, [currentPage, totalPages]);
 
     const renderPaginationDots = () => {
         let pages: (number | string)[] = [];
@@ -66,7 +64,7 @@ export default function JournalsClient() {
                 <>
                     <div className="grid grid-cols-2 md:grid-cols-3 lg:grid-cols-4 xl:grid-cols-5 gap-4 md:gap-6 items-start w-full">
                         <AnimatePresence mode="popLayout">
-                            {paginatedNotes.map((note, i) => (
+                            {notes.map((note, i) => (
                                 <motion.div className="h-full"
                                     key={note.id}
                                     layout
@@ -82,7 +80,7 @@ export default function JournalsClient() {
 
                     <div className="flex flex-col sm:flex-row items-center justify-between gap-4 px-2 pt-4">
                         <div className="text-sm text-slate-400">
-                            Showing {((currentPage - 1) * itemsPerPage) + 1} to {Math.min(currentPage * itemsPerPage, notes.length)} of {notes.length} entries
+                            Showing {((currentPage - 1) * itemsPerPage) + 1} to {Math.min(currentPage * itemsPerPage, total)} of {total} entries
                         </div>
                         <div className="flex items-center gap-2">
                             <Button
@@ -5,8 +5,9 @@ export default async function Home() {
   const storedCookies = await cookies();
 
   const token = storedCookies.get("token")?.value;
+  const refreshToken = storedCookies.get("refresh_token")?.value;
 
-  if (!token)
+  if (!token && !refreshToken)
     return redirect("/login")
 
   return redirect("/admin/dashboard")
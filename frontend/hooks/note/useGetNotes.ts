@@ -5,8 +5,10 @@ import { axios } from "@/lib";
 
 import { JournalListSchema, type JournalInfo } from "@/schemas/journal.schema";
 
-export default function useGetNotes() {
+// /notes/get is paginated on the server; page starts at 1 and limit is at most 100.
+export default function useGetNotes(page = 1, limit = 20) {
     const [notes, setNotes] = useState<JournalInfo[]>();
+    const [total, setTotal] = useState(0);
     const [loading, setLoading] = useState(false);
     const [error, setError] = useState<string | null>(null);
 
@@ -15,9 +17,10 @@ export default function useGetNotes() {
         setError(null);
 
         try {
-            const result = await axios.get("/notes/get");
-            const notes = JournalListSchema.parse(result.data.notes);
+            const result = await axios.get("/notes/get", { params: { page, limit } });
+            const notes = JournalListSchema.parse(result.data.notes ?? []);
             setNotes(notes);
+            setTotal(result.data.total ?? notes.length);
             return true;
         } catch (err: any) {
             setError(err.message || "Failed to add note.");
@@ -29,7 +32,7 @@ export default function useGetNotes() {
 
     useEffect(() => {
         refreshNote();
-    }, []);
+    }, [page, limit]);
 
-    return { notes, loading, error, refreshNote };
-}
\ No newline at end of file
+    return { notes, total, loading, error, refreshNote };
+}
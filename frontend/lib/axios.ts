@@ -1,4 +1,4 @@
-import axios from "axios";
+import axios, { type InternalAxiosRequestConfig } from "axios";
 
 const api = axios.create({
     baseURL: process.env.NEXT_PUBLIC_API_URL || "/api",
@@ -8,13 +8,40 @@ const api = axios.create({
     }
 });
 
+type RetriableConfig = InternalAxiosRequestConfig & { _retried?: boolean };
+
+// Access tokens are short-lived. Concurrent 401s share one refresh call,
+// because the refresh token rotates and can only be used once.
+let refreshing: Promise<void> | null = null;
+
+const refreshSession = () => {
+    if (!refreshing) {
+        refreshing = api.post("/account/refresh")
+            .then(() => undefined)
+            .finally(() => { refreshing = null; });
+    }
+    return refreshing;
+};
+
 api.interceptors.response.use(
     response => response,
     async (error) => {
+        const config = error.config as RetriableConfig | undefined;
+        const url = config?.url ?? "";
         const isLoginPath = window.location.pathname === "/login";
-        const isLoginRequest = error.config.url.includes("/account/login");
+        const isAuthRequest = url.includes("/account/login") || url.includes("/account/refresh");
+
+        if (error.response?.status === 401 && config && !isAuthRequest && !config._retried) {
+            config._retried = true;
+            try {
+                await refreshSession();
+                return api(config);
+            } catch {
+                // refresh token missing, expired or revoked: fall through to the login redirect
+            }
+        }
 
-        if (error.response?.status === 401 && !isLoginPath && !isLoginRequest) {
+        if (error.response?.status === 401 && !isLoginPath && !isAuthRequest) {
             document.cookie = "token=; expires=Thu, 01 Jan 1970 00:00:00 UTC; path=/;";
             window.location.href = "/login";
             return Promise.reject(new Error("Session expired. Please login again."));
@@ -25,4 +52,4 @@ api.interceptors.response.use(
     }
 );
 
-export default api;
\ No newline at end of file
+export default api;
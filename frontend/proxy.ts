@@ -3,10 +3,12 @@ import type { NextRequest } from 'next/server';
 
 export function proxy(req: NextRequest) {
     const token = req.cookies.get('token')?.value;
+    // the access token cookie expires after 15 minutes, the API client renews it from the refresh token
+    const refreshToken = req.cookies.get('refresh_token')?.value;
 
     const { pathname } = req.nextUrl;
 
-    if (!token && (pathname.startsWith('/admin')))
+    if (!token && !refreshToken && (pathname.startsWith('/admin')))
         return NextResponse.redirect(new URL('/login', req.url));
 
     if (pathname.startsWith('/login') && token)